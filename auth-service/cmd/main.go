@@ -8,6 +8,7 @@ import (
 	"github.com/pokonti/psychologist-backend/auth-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/auth-service/internal/handlers"
 	"github.com/pokonti/psychologist-backend/auth-service/internal/routes"
+	"github.com/pokonti/psychologist-backend/auth-service/middleware"
 
 	_ "github.com/pokonti/psychologist-backend/auth-service/docs"
 )
@@ -23,6 +24,10 @@ func main() {
 	r.TrustedPlatform = gin.PlatformCloudflare
 	config.ConnectDB()
 
+	if err := middleware.LoadSigningKeys(); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
 	config.ConnectRabbitMQ()
 	defer config.RabbitConn.Close()
 	defer config.RabbitChannel.Close()
@@ -31,7 +31,7 @@ func ConnectDB() {
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
-	err = DB.AutoMigrate(&models.User{})
+	err = DB.AutoMigrate(&models.User{}, &models.Session{}, &models.RefreshToken{})
 	if err != nil {
 		log.Fatal("Failed to migrate database: ", err)
 	}
@@ -10,6 +10,11 @@ import (
 
 type RabbitMQClient struct{}
 
+// NotificationPublisher is what handlers need from RabbitMQ, so tests can replace it
+type NotificationPublisher interface {
+	PublishNotification(msg NotificationMessage) error
+}
+
 // Message payload structure
 type NotificationMessage struct {
 	Type    string            `json:"type"`
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/models"
+	"github.com/pokonti/psychologist-backend/auth-service/middleware"
+)
+
+// JWKS serves the public keys at /.well-known/jwks.json (outside the /api/v1/auth base path).
+// Every key that may have signed a still-valid token is listed; verifiers pick one by the kid header.
+func (ac *AuthController) JWKS(c *gin.Context) {
+	set, err := middleware.PublicJWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Signing keys unavailable"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/auth-service/config"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/models"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+const forgotPasswordMessage = "If an account with this email exists, a reset code has been sent."
+
+// maxResetAttempts is how many wrong codes a reset code survives before it is
+// thrown away and the user has to request a new one.
+const maxResetAttempts = 5
+
+// ForgotPassword godoc
+// @Summary      Request a password reset code
+// @Description  Emails a 6-digit reset code valid for 15 minutes. Always answers the same way so it cannot be used to probe which emails are registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body models.ForgotPasswordInput true "Account email"
+// @Success      200  {object}  models.MessageResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Router       /forgot-password [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var input models.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil || !user.IsVerified || user.IsBlocked {
+		c.JSON(http.StatusOK, models.MessageResponse{Message: forgotPasswordMessage})
+		return
+	}
+
+	resetCode := utils.GenerateRandomCode()
+	err := config.DB.Model(&user).Updates(map[string]interface{}{
+		"reset_code":            resetCode,
+		"reset_code_expires_at": time.Now().Add(15 * time.Minute),
+		"reset_attempts":        0,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate reset code"})
+		return
+	}
+
+	msg := clients.NotificationMessage{
+		Type:    "password_reset",
+		ToEmail: user.Email,
+		Data: map[string]string{
+			"code": resetCode,
+		},
+	}
+	if err := ac.RabbitMQ.PublishNotification(msg); err != nil {
+		// Answer exactly as for an unknown email so a broker outage does not
+		// reveal which addresses are registered
+		log.Printf("RabbitMQ Error: %v", err)
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: forgotPasswordMessage})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password with the emailed code
+// @Description  Sets a new password if the reset code is valid. Every existing session is revoked, so all devices must log in again. After 5 wrong codes the reset code is discarded and a new one must be requested.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body models.ResetPasswordInput true "Email, reset code and new password"
+// @Success      200  {object}  models.MessageResponse
+// @Failure      400  {object}  models.ErrorResponse "Code expired"
+// @Failure      401  {object}  models.ErrorResponse "Invalid code"
+// @Router       /reset-password [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var input models.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil || user.ResetCode == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid reset code"})
+		return
+	}
+
+	if time.Now().After(user.ResetCodeExpiresAt) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Reset code expired"})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(user.ResetCode), []byte(input.Code)) != 1 {
+		// Count in SQL so parallel guesses cannot all read the same attempt number
+		err := config.DB.Model(&user).Update("reset_attempts", gorm.Expr("reset_attempts + 1")).Error
+		if err == nil {
+			err = config.DB.Model(&models.User{}).
+				Where("id = ? AND reset_attempts >= ?", user.ID, maxResetAttempts).
+				Update("reset_code", "").Error
+		}
+		if err != nil {
+			log.Printf("Failed to count reset attempt for %s: %v", user.ID, err)
+		}
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid reset code"})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to reset password"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password":       hashedPassword,
+			"reset_code":     "",
+			"reset_attempts": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return revokeAllSessions(tx, user.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Password has been reset. Please log in again."})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/auth-service/config"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/models"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+var errTokenReused = errors.New("refresh token reuse detected")
+
+// createSession starts a new token family for the device the request came from
+// and returns the session together with its first refresh token.
+func createSession(c *gin.Context, userID, device string) (*models.Session, string, error) {
+	now := time.Now()
+	session := models.Session{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		Device:     device,
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(refreshTokenTTL),
+	}
+
+	refreshToken := uuid.NewString()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.RefreshToken{
+			TokenHash: utils.HashToken(refreshToken),
+			SessionID: session.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &session, refreshToken, nil
+}
+
+// rotateRefreshToken exchanges a refresh token for a new one in the same session.
+// Presenting a token that was already rotated revokes the whole session.
+func rotateRefreshToken(token string) (*models.Session, string, error) {
+	var session models.Session
+	newToken := uuid.NewString()
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.First(&stored, "token_hash = ?", utils.HashToken(token)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&session, "id = ?", stored.SessionID).Error; err != nil {
+			return err
+		}
+
+		if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+			return gorm.ErrRecordNotFound
+		}
+
+		if stored.RotatedAt != nil {
+			return errTokenReused
+		}
+
+		now := time.Now()
+		// Guard against two concurrent refreshes with the same token
+		res := tx.Model(&models.RefreshToken{}).
+			Where("token_hash = ? AND rotated_at IS NULL", stored.TokenHash).
+			Update("rotated_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errTokenReused
+		}
+
+		if err := tx.Create(&models.RefreshToken{
+			TokenHash: utils.HashToken(newToken),
+			SessionID: session.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		session.LastUsedAt = now
+		return tx.Model(&session).Update("last_used_at", now).Error
+	})
+
+	if errors.Is(err, errTokenReused) {
+		log.Printf("Refresh token reuse detected for session %s (user %s). Revoking session.", session.ID, session.UserID)
+		revokeSession(session.ID)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &session, newToken, nil
+}
+
+func revokeSession(sessionID string) {
+	config.DB.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+}
+
+// revokeAllSessions logs the user out everywhere. Used by "log out all devices" and password reset.
+func revokeAllSessions(db *gorm.DB, userID string) error {
+	return db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// ListSessions godoc
+// @Summary      List my active sessions
+// @Description  Returns every device where the current user is logged in. The session of the current access token is marked as current.
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.SessionResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /sessions [get]
+func (ac *AuthController) ListSessions(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	currentID := c.GetHeader("X-Session-ID")
+
+	var sessions []models.Session
+	if err := config.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at desc").
+		Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	response := []models.SessionResponse{}
+	for _, s := range sessions {
+		response = append(response, models.SessionResponse{
+			ID:         s.ID,
+			Device:     s.Device,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke one session
+// @Description  Logs out a single device. Its refresh token stops working immediately; its access token stays valid until it expires.
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  models.MessageResponse
+// @Failure      404  {object}  models.ErrorResponse "Session not found"
+// @Router       /sessions/{id} [delete]
+func (ac *AuthController) RevokeSession(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	sessionID := c.Param("id")
+
+	res := config.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Session revoked"})
+}
+
+// RevokeAllSessions godoc
+// @Summary      Revoke all sessions
+// @Description  Logs the current user out of every device, including this one.
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  models.MessageResponse
+// @Router       /sessions [delete]
+func (ac *AuthController) RevokeAllSessions(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	if err := revokeAllSessions(config.DB, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "All sessions revoked"})
+}
@@ -17,7 +17,7 @@ import (
 
 type AuthController struct {
 	UserClient userprofile.UserProfileServiceClient
-	RabbitMQ   *clients.RabbitMQClient
+	RabbitMQ   clients.NotificationPublisher
 }
 
 // Register godoc
@@ -182,7 +182,7 @@ func (ac *AuthController) VerifyEmail(c *gin.Context) {
 	user.VerificationCode = ""
 	config.DB.Save(&user)
 
-	token, _ := middleware.GenerateJWT(user.ID, user.Email, user.Role)
+	token, _ := middleware.GenerateJWT(user.ID, user.Email, user.Role, "")
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Email verified successfully",
@@ -230,15 +230,18 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := middleware.GenerateJWT(user.ID, user.Email, user.Role)
+	session, refreshToken, err := createSession(c, user.ID, input.Device)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate token"})
+		log.Printf("DB Error: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create session"})
 		return
 	}
 
-	refreshToken := uuid.NewString()
-
-	config.DB.Model(&user).Update("refresh_token", refreshToken)
+	token, err := middleware.GenerateJWT(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate token"})
+		return
+	}
 
 	c.JSON(http.StatusOK, models.TokenResponse{
 		Token:        token,
@@ -248,7 +251,7 @@ func (ac *AuthController) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary      Get a new access token
-// @Description  Uses a valid refresh token to generate a new 15-minute access token. Implements Refresh Token Rotation (returns a new refresh token too).
+// @Description  Uses a valid refresh token to generate a new 15-minute access token. Implements Refresh Token Rotation (returns a new refresh token too). Reusing an already rotated token revokes the whole session.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -263,9 +266,14 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Find user by Refresh Token
+	session, newRefreshToken, err := rotateRefreshToken(input.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid refresh token. Please log in again."})
+		return
+	}
+
 	var user models.User
-	if err := config.DB.Where("refresh_token = ?", input.RefreshToken).First(&user).Error; err != nil {
+	if err := config.DB.First(&user, "id = ?", session.UserID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid refresh token. Please log in again."})
 		return
 	}
@@ -275,10 +283,7 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, _ := middleware.GenerateJWT(user.ID, user.Email, user.Role)
-
-	newRefreshToken := uuid.NewString()
-	config.DB.Model(&user).Update("refresh_token", newRefreshToken)
+	newAccessToken, _ := middleware.GenerateJWT(user.ID, user.Email, user.Role, session.ID)
 
 	c.JSON(http.StatusOK, models.TokenResponse{
 		Token:        newAccessToken,
@@ -288,14 +293,33 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 
 // Logout godoc
 // @Summary      Logout user
-// @Description  Revokes the refresh token, forcing the user to log in again once their access token expires.
+// @Description  Revokes the current session (or the session of the given refresh token). Other devices stay logged in.
 // @Tags         auth
+// @Accept       json
+// @Produce      json
 // @Security     BearerAuth
+// @Param        request body models.LogoutInput false "Refresh token of the session to end"
+// @Success      200 {object} models.MessageResponse
 // @Router       /logout [post]
 func (ac *AuthController) Logout(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
+	sessionID := c.GetHeader("X-Session-ID")
 
-	config.DB.Model(&models.User{}).Where("id = ?", userID).Update("refresh_token", "")
+	var input models.LogoutInput
+	_ = c.ShouldBindJSON(&input)
+
+	if input.RefreshToken != "" {
+		var stored models.RefreshToken
+		if err := config.DB.First(&stored, "token_hash = ?", utils.HashToken(input.RefreshToken)).Error; err == nil {
+			sessionID = stored.SessionID
+		}
+	}
+
+	if sessionID != "" {
+		config.DB.Model(&models.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+			Update("revoked_at", time.Now())
+	}
 
 	c.JSON(http.StatusOK, models.MessageResponse{Message: "Successfully logged out"})
 }
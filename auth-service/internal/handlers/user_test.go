@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/auth-service/config"
+	"github.com/pokonti/psychologist-backend/auth-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/auth-service/internal/models"
 	"github.com/pokonti/psychologist-backend/auth-service/internal/utils"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
@@ -46,11 +48,33 @@ func (m *MockUserClient) GetBatchUserProfiles(ctx context.Context, in *userprofi
 	return args.Get(0).(*userprofile.GetBatchUserProfilesResponse), args.Error(1)
 }
 
+func (m *MockUserClient) UpdateUserPhone(ctx context.Context, in *userprofile.UpdateUserPhoneRequest, opts ...grpc.CallOption) (*userprofile.UpdateUserPhoneResponse, error) {
+	return &userprofile.UpdateUserPhoneResponse{Success: true}, nil
+}
+
+func (m *MockUserClient) GetConsentedMoodLogs(ctx context.Context, in *userprofile.GetConsentedMoodLogsRequest, opts ...grpc.CallOption) (*userprofile.GetConsentedMoodLogsResponse, error) {
+	return &userprofile.GetConsentedMoodLogsResponse{}, nil
+}
+
+func (m *MockUserClient) GetNotificationPreferences(ctx context.Context, in *userprofile.GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*userprofile.GetNotificationPreferencesResponse, error) {
+	return &userprofile.GetNotificationPreferencesResponse{}, nil
+}
+
+// MockPublisher records notifications instead of sending them to RabbitMQ
+type MockPublisher struct {
+	Sent []clients.NotificationMessage
+}
+
+func (m *MockPublisher) PublishNotification(msg clients.NotificationMessage) error {
+	m.Sent = append(m.Sent, msg)
+	return nil
+}
+
 func setupTestDB() {
 	// Using in-memory SQLite instead of Postgres
 	db, _ := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
 	config.DB = db
-	config.DB.AutoMigrate(&models.User{})
+	config.DB.AutoMigrate(&models.User{}, &models.Session{}, &models.RefreshToken{})
 }
 
 func setupRouter(ac *AuthController) *gin.Engine {
@@ -59,26 +83,28 @@ func setupRouter(ac *AuthController) *gin.Engine {
 	r.POST("/register", ac.Register)
 	r.POST("/verify", ac.VerifyEmail)
 	r.POST("/login", ac.Login)
+	r.POST("/refresh", ac.RefreshToken)
+	r.POST("/logout", ac.Logout)
+	r.POST("/forgot-password", ac.ForgotPassword)
+	r.POST("/reset-password", ac.ResetPassword)
+	r.GET("/sessions", ac.ListSessions)
+	r.DELETE("/sessions", ac.RevokeAllSessions)
+	r.DELETE("/sessions/:id", ac.RevokeSession)
 	return r
 }
 
 func TestRegisterSuccess(t *testing.T) {
 	setupTestDB()
 
-	originalEmailFunc := utils.SendVerificationEmail
-	defer func() { utils.SendVerificationEmail = originalEmailFunc }()
-
-	utils.SendVerificationEmail = func(toEmail, code string) error {
-		return nil
-	}
-
 	mockUserClient := new(MockUserClient)
 
 	mockUserClient.On("CreateUserProfile", mock.Anything, mock.Anything).
 		Return(&userprofile.CreateUserProfileResponse{Id: "123"}, nil)
 
+	publisher := &MockPublisher{}
 	ac := &AuthController{
 		UserClient: mockUserClient,
+		RabbitMQ:   publisher,
 	}
 	r := setupRouter(ac)
 
@@ -100,6 +126,9 @@ func TestRegisterSuccess(t *testing.T) {
 	config.DB.Where("email = ?", "newuser@test.com").First(&user)
 	assert.NotEmpty(t, user.ID)
 	assert.False(t, user.IsVerified) // Should be false initially
+
+	assert.Len(t, publisher.Sent, 1)
+	assert.Equal(t, "auth_verification", publisher.Sent[0].Type)
 }
 
 func TestVerifySuccess(t *testing.T) {
@@ -272,3 +301,195 @@ func TestVerifyFailExpired(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code) // 400
 }
+
+// loginAs seeds a verified user and logs them in from the given device
+func loginAs(t *testing.T, r *gin.Engine, email, device string) models.TokenResponse {
+	input := models.LoginInput{Email: email, Password: "password123", Device: device}
+	jsonBytes, _ := json.Marshal(input)
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonBytes))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tokens models.TokenResponse
+	json.Unmarshal(w.Body.Bytes(), &tokens)
+	return tokens
+}
+
+func refresh(r *gin.Engine, refreshToken string) *httptest.ResponseRecorder {
+	jsonBytes, _ := json.Marshal(models.RefreshInput{RefreshToken: refreshToken})
+	req, _ := http.NewRequest("POST", "/refresh", bytes.NewBuffer(jsonBytes))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMultipleSessionsPerUser(t *testing.T) {
+	setupTestDB()
+
+	hash, _ := utils.HashPassword("password123")
+	config.DB.Create(&models.User{ID: "user-sessions", Email: "sessions@test.com", Password: hash, IsVerified: true})
+
+	r := setupRouter(&AuthController{})
+
+	phone := loginAs(t, r, "sessions@test.com", "phone")
+	laptop := loginAs(t, r, "sessions@test.com", "laptop")
+
+	// Logging in on the laptop must not invalidate the phone
+	assert.Equal(t, http.StatusOK, refresh(r, phone.RefreshToken).Code)
+	assert.Equal(t, http.StatusOK, refresh(r, laptop.RefreshToken).Code)
+
+	req, _ := http.NewRequest("GET", "/sessions", nil)
+	req.Header.Set("X-User-ID", "user-sessions")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var sessions []models.SessionResponse
+	json.Unmarshal(w.Body.Bytes(), &sessions)
+	assert.Len(t, sessions, 2)
+
+	// Revoke all
+	req, _ = http.NewRequest("DELETE", "/sessions", nil)
+	req.Header.Set("X-User-ID", "user-sessions")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var active int64
+	config.DB.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", "user-sessions").Count(&active)
+	assert.Equal(t, int64(0), active)
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	setupTestDB()
+
+	hash, _ := utils.HashPassword("password123")
+	config.DB.Create(&models.User{ID: "user-reuse", Email: "reuse@test.com", Password: hash, IsVerified: true})
+
+	r := setupRouter(&AuthController{})
+	tokens := loginAs(t, r, "reuse@test.com", "phone")
+
+	w := refresh(r, tokens.RefreshToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var rotated models.TokenResponse
+	json.Unmarshal(w.Body.Bytes(), &rotated)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+
+	// The old token was already rotated: replaying it kills the whole family
+	assert.Equal(t, http.StatusUnauthorized, refresh(r, tokens.RefreshToken).Code)
+	assert.Equal(t, http.StatusUnauthorized, refresh(r, rotated.RefreshToken).Code)
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	setupTestDB()
+
+	hash, _ := utils.HashPassword("password123")
+	config.DB.Create(&models.User{ID: "user-reset", Email: "reset@test.com", Password: hash, IsVerified: true})
+
+	publisher := &MockPublisher{}
+	r := setupRouter(&AuthController{RabbitMQ: publisher})
+	tokens := loginAs(t, r, "reset@test.com", "laptop")
+
+	jsonBytes, _ := json.Marshal(models.ForgotPasswordInput{Email: "reset@test.com"})
+	req, _ := http.NewRequest("POST", "/forgot-password", bytes.NewBuffer(jsonBytes))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, publisher.Sent, 1)
+	assert.Equal(t, "password_reset", publisher.Sent[0].Type)
+	code := publisher.Sent[0].Data["code"]
+
+	jsonBytes, _ = json.Marshal(models.ResetPasswordInput{Email: "reset@test.com", Code: code, NewPassword: "newpassword456"})
+	req, _ = http.NewRequest("POST", "/reset-password", bytes.NewBuffer(jsonBytes))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updatedUser models.User
+	config.DB.First(&updatedUser, "id = ?", "user-reset")
+	assert.True(t, utils.CheckPasswordHash("newpassword456", updatedUser.Password))
+	assert.Empty(t, updatedUser.ResetCode)
+
+	// Existing refresh tokens no longer work
+	assert.Equal(t, http.StatusUnauthorized, refresh(r, tokens.RefreshToken).Code)
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	setupTestDB()
+
+	publisher := &MockPublisher{}
+	r := setupRouter(&AuthController{RabbitMQ: publisher})
+
+	jsonBytes, _ := json.Marshal(models.ForgotPasswordInput{Email: "nobody@test.com"})
+	req, _ := http.NewRequest("POST", "/forgot-password", bytes.NewBuffer(jsonBytes))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// Same answer as for a real account, but nothing is sent
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, publisher.Sent)
+}
+
+// FailingPublisher simulates RabbitMQ being unreachable
+type FailingPublisher struct{}
+
+func (FailingPublisher) PublishNotification(clients.NotificationMessage) error {
+	return errors.New("connection refused")
+}
+
+func TestForgotPasswordPublishErrorLooksLikeUnknownEmail(t *testing.T) {
+	setupTestDB()
+
+	hash, _ := utils.HashPassword("password123")
+	config.DB.Create(&models.User{ID: "user-broker", Email: "broker@test.com", Password: hash, IsVerified: true})
+
+	r := setupRouter(&AuthController{RabbitMQ: FailingPublisher{}})
+
+	jsonBytes, _ := json.Marshal(models.ForgotPasswordInput{Email: "broker@test.com"})
+	req, _ := http.NewRequest("POST", "/forgot-password", bytes.NewBuffer(jsonBytes))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), forgotPasswordMessage)
+}
+
+func TestResetPasswordDiscardsCodeAfterTooManyAttempts(t *testing.T) {
+	setupTestDB()
+
+	hash, _ := utils.HashPassword("password123")
+	config.DB.Create(&models.User{ID: "user-guess", Email: "guess@test.com", Password: hash, IsVerified: true})
+
+	publisher := &MockPublisher{}
+	r := setupRouter(&AuthController{RabbitMQ: publisher})
+
+	jsonBytes, _ := json.Marshal(models.ForgotPasswordInput{Email: "guess@test.com"})
+	req, _ := http.NewRequest("POST", "/forgot-password", bytes.NewBuffer(jsonBytes))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	code := publisher.Sent[0].Data["code"]
+
+	reset := func(code string) int {
+		jsonBytes, _ := json.Marshal(models.ResetPasswordInput{Email: "guess@test.com", Code: code, NewPassword: "newpassword456"})
+		req, _ := http.NewRequest("POST", "/reset-password", bytes.NewBuffer(jsonBytes))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < maxResetAttempts; i++ {
+		assert.Equal(t, http.StatusUnauthorized, reset(wrong))
+	}
+
+	// The right code no longer works once the limit is hit
+	assert.Equal(t, http.StatusUnauthorized, reset(code))
+
+	var user models.User
+	config.DB.First(&user, "id = ?", "user-guess")
+	assert.Empty(t, user.ResetCode)
+	assert.True(t, utils.CheckPasswordHash("password123", user.Password))
+}
@@ -14,8 +14,23 @@ type VerifyInput struct {
 type LoginInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device" binding:"omitempty,max=100"` // e.g. "iPhone 15", shown in the sessions list
 }
 
 type RefreshInput struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Email       string `json:"email" binding:"required,email"`
+	Code        string `json:"code" binding:"required,len=6"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"` // Optional, defaults to the session of the access token
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -12,3 +14,14 @@ type TokenResponse struct {
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Session is one logged-in device. Every refresh rotates its token, but all
+// tokens issued for the same login share the session (the token family).
+type Session struct {
+	ID        string `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID    string `gorm:"type:uuid;not null;index" json:"user_id"`
+	Device    string `gorm:"type:varchar(100)" json:"device"`
+	UserAgent string `gorm:"type:text" json:"user_agent"`
+	IP        string `gorm:"type:varchar(45)" json:"ip"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+}
+
+// RefreshToken stores the hash of every token issued for a session.
+// A token with RotatedAt set has already been exchanged; presenting it again means it leaked.
+type RefreshToken struct {
+	TokenHash string     `gorm:"primaryKey;type:varchar(64)" json:"-"`
+	SessionID string     `gorm:"type:uuid;not null;index" json:"session_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
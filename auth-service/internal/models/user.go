@@ -14,7 +14,9 @@ type User struct {
 	IsBlocked        bool      `gorm:"default:false" json:"is_blocked"`
 	BlockReason      string    `gorm:"type:text" json:"block_reason,omitempty"`
 
-	RefreshToken string `gorm:"index" json:"-"`
+	ResetCode          string    `json:"-"`
+	ResetCodeExpiresAt time.Time `json:"-"`
+	ResetAttempts      int       `gorm:"default:0" json:"-"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
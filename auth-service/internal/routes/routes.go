@@ -9,6 +9,7 @@ import (
 
 func SetupRoutes(r *gin.Engine, authController *handlers.AuthController) {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/.well-known/jwks.json", authController.JWKS)
 
 	api := r.Group("/api/v1/auth")
 	api.POST("/register", authController.Register)
@@ -16,6 +17,12 @@ func SetupRoutes(r *gin.Engine, authController *handlers.AuthController) {
 	api.POST("/login", authController.Login)
 	api.POST("/refresh", authController.RefreshToken)
 	api.POST("/logout", authController.Logout)
+	api.POST("/forgot-password", authController.ForgotPassword)
+	api.POST("/reset-password", authController.ResetPassword)
+
+	api.GET("/sessions", authController.ListSessions)
+	api.DELETE("/sessions", authController.RevokeAllSessions)
+	api.DELETE("/sessions/:id", authController.RevokeSession)
 
 	admin := r.Group("/api/v1/admin")
 	{
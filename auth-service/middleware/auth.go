@@ -1,29 +1,26 @@
 package middleware
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte(getSecret())
-
-func getSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "supersecret"
+func GenerateJWT(userID, email, role, sessionID string) (string, error) {
+	if err := LoadSigningKeys(); err != nil {
+		return "", err
 	}
-	return secret
-}
 
-func GenerateJWT(userID, email, role string) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":   userID, // used by gateway as X-User-ID
 		"email": email,
 		"role":  role,
 		"exp":   time.Now().Add(24 * time.Hour).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	if sessionID != "" {
+		claims["sid"] = sessionID // used by gateway as X-Session-ID
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keys.active.kid // lets verifiers pick the right key from the JWKS
+	return token.SignedString(keys.active.private)
 }
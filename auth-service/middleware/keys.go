@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Signing keys are read from JWT_KEYS_DIR, one PEM encoded RSA private key per file.
+// The file name without extension is the key ID ("kid"). Every key in the directory is
+// published in the JWKS, but only JWT_ACTIVE_KID signs new tokens. If the directory is
+// empty on first start, a key named after the current month (e.g. "2026-03") is generated
+// into it, so the directory must be writable until it holds at least one key.
+//
+// Rotation without downtime:
+//  1. Add the new key file and restart auth-service: it is published but not used yet.
+//  2. After the gateway has refreshed its JWKS cache, point JWT_ACTIVE_KID at the new key.
+//  3. Once the longest-lived token signed by the old key has expired, delete the old file.
+type signingKey struct {
+	kid     string
+	private *rsa.PrivateKey
+}
+
+type keySet struct {
+	active *signingKey
+	keys   []*signingKey
+}
+
+var (
+	keys     *keySet
+	keysErr  error
+	keysOnce sync.Once
+)
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadSigningKeys reads the key directory. It is safe to call more than once.
+func LoadSigningKeys() error {
+	keysOnce.Do(func() {
+		keys, keysErr = loadKeySet(os.Getenv("JWT_KEYS_DIR"), os.Getenv("JWT_ACTIVE_KID"))
+	})
+	return keysErr
+}
+
+func loadKeySet(dir, activeKID string) (*keySet, error) {
+	if dir == "" {
+		// Local development: a throwaway key means tokens do not survive a restart
+		log.Println("JWT_KEYS_DIR not set. Using an ephemeral signing key.")
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		k := &signingKey{kid: "ephemeral", private: private}
+		return &keySet{active: k, keys: []*signingKey{k}}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		path, err := generateKeyFile(dir, time.Now().UTC().Format("2006-01"))
+		if err != nil {
+			return nil, fmt.Errorf("no *.pem keys found in %s and generating one failed: %w", dir, err)
+		}
+		log.Printf("No JWT signing keys found. Generated %s", path)
+		files = []string{path}
+	}
+
+	set := &keySet{}
+	for _, f := range files {
+		private, err := readPrivateKey(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", f, err)
+		}
+		kid := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		k := &signingKey{kid: kid, private: private}
+		set.keys = append(set.keys, k)
+
+		if kid == activeKID {
+			set.active = k
+		}
+	}
+
+	if set.active == nil {
+		if activeKID != "" {
+			return nil, fmt.Errorf("JWT_ACTIVE_KID %q not found in %s", activeKID, dir)
+		}
+		// Without an explicit choice, sign with the newest key by name (e.g. "2026-03")
+		set.active = set.keys[len(set.keys)-1]
+	}
+
+	log.Printf("Loaded %d JWT signing keys, active kid: %s", len(set.keys), set.active.kid)
+	return set, nil
+}
+
+// generateKeyFile writes a new 2048-bit RSA key to dir/<kid>.pem, readable only by this user
+func generateKeyFile(dir, kid string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+
+	path := filepath.Join(dir, kid+".pem")
+	// O_EXCL so two replicas starting together never overwrite each other's key
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA private key")
+	}
+	return key, nil
+}
+
+// PublicJWKS returns the public half of every loaded key
+func PublicJWKS() (JWKS, error) {
+	if err := LoadSigningKeys(); err != nil {
+		return JWKS{}, err
+	}
+
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range keys.keys {
+		pub := k.private.PublicKey
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: k.kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return set, nil
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useKeys loads dir as the process-wide key set, bypassing the sync.Once
+func useKeys(t *testing.T, dir, activeKID string) {
+	t.Helper()
+	set, err := loadKeySet(dir, activeKID)
+	require.NoError(t, err)
+	keys, keysErr = set, nil
+	keysOnce.Do(func() {})
+}
+
+// verifyWithJWKS checks a token the way the gateway does: pick the key by kid from the published set
+func verifyWithJWKS(t *testing.T, tokenString string) (*jwt.Token, error) {
+	t.Helper()
+	set, err := PublicJWKS()
+	require.NoError(t, err)
+
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, k := range set.Keys {
+			if k.Kid != kid {
+				continue
+			}
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, err
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return nil, err
+			}
+			return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+		}
+		return nil, jwt.ErrTokenUnverifiable
+	}, jwt.WithValidMethods([]string{"RS256"}))
+}
+
+func TestLoadKeySetGeneratesKeyInEmptyDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "jwt")
+
+	set, err := loadKeySet(dir, "")
+	require.NoError(t, err)
+	assert.Len(t, set.keys, 1)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.pem"))
+	require.Len(t, files, 1)
+	info, _ := os.Stat(files[0])
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The next start reuses the generated key instead of making another
+	again, err := loadKeySet(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, set.active.kid, again.active.kid)
+	assert.True(t, set.active.private.Equal(again.active.private))
+}
+
+func TestGenerateJWTSignsWithActiveKid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := generateKeyFile(dir, "2026-01")
+	require.NoError(t, err)
+	_, err = generateKeyFile(dir, "2026-02")
+	require.NoError(t, err)
+
+	useKeys(t, dir, "2026-01")
+
+	tokenString, err := GenerateJWT("user-1", "user@test.com", "student", "session-1")
+	require.NoError(t, err)
+
+	token, err := verifyWithJWKS(t, tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01", token.Header["kid"])
+
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, "user-1", claims["sub"])
+	assert.Equal(t, "session-1", claims["sid"])
+}
+
+func TestJWKSVerifiesAcrossKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	_, err := generateKeyFile(dir, "2026-01")
+	require.NoError(t, err)
+
+	useKeys(t, dir, "")
+	oldToken, err := GenerateJWT("user-1", "user@test.com", "student", "")
+	require.NoError(t, err)
+
+	// Step 1: the new key is published but the old one still signs
+	_, err = generateKeyFile(dir, "2026-02")
+	require.NoError(t, err)
+	useKeys(t, dir, "2026-01")
+	set, err := PublicJWKS()
+	require.NoError(t, err)
+	assert.Len(t, set.Keys, 2)
+
+	// Step 2: switch to the new key, tokens from both keys verify
+	useKeys(t, dir, "2026-02")
+	newToken, err := GenerateJWT("user-1", "user@test.com", "student", "")
+	require.NoError(t, err)
+
+	token, err := verifyWithJWKS(t, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02", token.Header["kid"])
+	_, err = verifyWithJWKS(t, oldToken)
+	assert.NoError(t, err)
+
+	// Step 3: once the old key is removed its tokens stop verifying
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.pem")))
+	useKeys(t, dir, "2026-02")
+	_, err = verifyWithJWKS(t, oldToken)
+	assert.Error(t, err)
+	_, err = verifyWithJWKS(t, newToken)
+	assert.NoError(t, err)
+}
+
+func TestLoadKeySetUnknownActiveKid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := generateKeyFile(dir, "2026-01")
+	require.NoError(t, err)
+
+	_, err = loadKeySet(dir, "2025-12")
+	assert.Error(t, err)
+}
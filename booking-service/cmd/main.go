@@ -2,14 +2,21 @@ package main
 
 import (
 	"log"
+	"net"
+	_ "time/tzdata" // IANA zones for slim container images
 
 	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	_ "github.com/pokonti/psychologist-backend/booking-service/docs"
 	clients2 "github.com/pokonti/psychologist-backend/booking-service/internal/clients"
+	grpcserver "github.com/pokonti/psychologist-backend/booking-service/internal/grpc"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/handlers"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/questionnaire"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/worker"
 	"github.com/pokonti/psychologist-backend/booking-service/routes"
+	"github.com/pokonti/psychologist-backend/proto/booking"
+	"google.golang.org/grpc"
 )
 
 // @title       KBTU Psychologist Booking Service API
@@ -23,13 +30,21 @@ import (
 // @name Authorization
 func main() {
 
+	if err := notecrypt.LoadKeys(); err != nil {
+		log.Fatalf("Failed to load note encryption keys: %v", err)
+	}
+
 	config.ConnectDB()
 	config.ConnectRabbitMQ()
 
+	if err := questionnaire.SeedScreeners(config.DB); err != nil {
+		log.Printf("Failed to seed screening questionnaires: %v", err)
+	}
+
 	defer config.RabbitConn.Close()
 	defer config.RabbitChannel.Close()
 
-	worker.StartReservationCleanup()
+	worker.StartTemplateMaterializer()
 
 	// Init gRPC Client
 	userClient, conn, err := clients2.NewUserProfileClient()
@@ -48,10 +63,26 @@ func main() {
 	r := gin.Default()
 	r.TrustedPlatform = gin.PlatformCloudflare
 
+	worker.StartReservationCleanup(h.ReleaseExpiredReservation)
 	worker.StartReminderWorker(userClient, rabbitMQ)
+	worker.StartOutboxRelay(rabbitMQ)
 
 	routes.SetupRoutes(r, h)
 
+	// gRPC server (for other services)
+	lis, err := net.Listen("tcp", ":9094")
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	booking.RegisterBookingServiceServer(grpcServer, grpcserver.NewBookingServer(userClient))
+	go func() {
+		log.Println("booking-service gRPC listening on :9094")
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	log.Println("Booking Service running on port 8084")
 	r.Run(":8084")
 }
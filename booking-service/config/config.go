@@ -34,10 +34,30 @@ func ConnectDB() {
 	}
 
 	log.Println("Booking DB Connected. Running Migrations")
-	err = DB.AutoMigrate(&models.Slot{}, &models.WaitlistEntry{}, &models.BookingLog{})
+	err = DB.AutoMigrate(&models.Slot{}, &models.WaitlistEntry{}, &models.BookingLog{}, &models.OutboxMessage{}, &models.AvailabilityTemplate{}, &models.TimeOff{}, &models.CalendarFeed{}, &models.NoteAccessLog{}, &models.SessionNote{}, &models.SessionNoteRevision{}, &models.Questionnaire{})
 	if err != nil {
 		log.Fatal("Failed to migrate database: ", err)
 	}
+
+	// The note access log is evidence: nobody, including the service itself, may rewrite it
+	err = DB.Exec(`
+		CREATE OR REPLACE FUNCTION note_access_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'note_access_logs is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS note_access_logs_no_change ON note_access_logs;
+		CREATE TRIGGER note_access_logs_no_change BEFORE UPDATE OR DELETE ON note_access_logs
+			FOR EACH ROW EXECUTE FUNCTION note_access_logs_append_only();
+
+		DROP TRIGGER IF EXISTS note_access_logs_no_truncate ON note_access_logs;
+		CREATE TRIGGER note_access_logs_no_truncate BEFORE TRUNCATE ON note_access_logs
+			FOR EACH STATEMENT EXECUTE FUNCTION note_access_logs_append_only();
+	`).Error
+	if err != nil {
+		log.Fatal("Failed to protect note access log: ", err)
+	}
 }
 
 func ConnectRabbitMQ() {
@@ -120,39 +120,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/reviews": {
+        "/admin/notes/access-log": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin views unmasked ratings and reviews across the platform, including student and psychologist identities.",
+                "description": "Returns the append-only log of decrypted note reads, newest first, optionally filtered by student or reader.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Admin: View all reviews",
+                "summary": "Admin: Who read clinical notes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only reads of this student's data",
+                        "name": "student_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reads by this user",
+                        "name": "reader_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max entries (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.AdminReviewResponse"
+                                "$ref": "#/definitions/models.NoteAccessLog"
                             }
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Database error",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -160,75 +174,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/psychologist/reviews": {
-            "get": {
+        "/admin/notes/rotate-key": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Psychologist views their ratings and written reviews. Student identities and exact dates are anonymized to protect privacy.",
+                "description": "Re-encrypts session notes, questionnaire answers and recommendations that are not sealed with the active master key, including plain text stored before encryption. Safe to run repeatedly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
+                    "admin"
                 ],
-                "summary": "Get anonymous reviews",
+                "summary": "Admin: Re-encrypt clinical notes with the active key",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.AnonymousReviewResponse"
-                            }
+                            "$ref": "#/definitions/models.NoteKeyRotationResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/psychologist/slots": {
+        "/admin/questionnaires": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns all slots for the logged-in psychologist, including student details for booked slots.",
+                "description": "Returns every version of every questionnaire, newest version first within each code.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
-                ],
-                "summary": "Get psychologist's own schedule",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter period: 'day', 'week', 'month' (default: day)",
-                        "name": "period",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date for filter (YYYY-MM-DD), default: today",
-                        "name": "date",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Admin: List questionnaire versions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.PsychologistScheduleResponse"
+                                "$ref": "#/definitions/models.Questionnaire"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -241,7 +250,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Psychologist generates multiple slots based on a weekly schedule pattern between start_date and end_date.",
+                "description": "Saves the questions as the next version of the code (version 1 for a new code). Earlier versions stay untouched so existing answers keep their meaning; new bookings use the new version.",
                 "consumes": [
                     "application/json"
                 ],
@@ -249,17 +258,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
+                    "admin"
                 ],
-                "summary": "Create schedule slots for a psychologist",
+                "summary": "Admin: Publish a questionnaire version",
                 "parameters": [
                     {
-                        "description": "Schedule configuration",
+                        "description": "Questionnaire definition",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateScheduleInput"
+                            "$ref": "#/definitions/models.QuestionnaireInput"
                         }
                     }
                 ],
@@ -267,23 +276,17 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.ScheduleCreatedResponse"
+                            "$ref": "#/definitions/models.Questionnaire"
                         }
                     },
                     "400": {
-                        "description": "validation error or no slots created",
+                        "description": "Invalid definition",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "only psychologists can create slots",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "database error",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -291,28 +294,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/psychologist/slots/{id}": {
-            "delete": {
+        "/admin/questionnaires/{code}/active": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allows a psychologist to remove an available slot from their schedule. Fails if the slot is already booked.",
+                "description": "Inactive questionnaires are no longer asked at booking. Stored answers are kept.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
+                    "admin"
                 ],
-                "summary": "Delete an unbooked slot",
+                "summary": "Admin: Turn a questionnaire on or off",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Slot ID (UUID)",
-                        "name": "id",
+                        "description": "Questionnaire code",
+                        "name": "code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Whether to ask it",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuestionnaireActiveInput"
+                        }
                     }
                 ],
                 "responses": {
@@ -322,26 +337,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "403": {
-                        "description": "Not authorized or not the owner",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Slot not found",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin views unmasked ratings and reviews across the platform, including student and psychologist identities.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Admin: View all reviews",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AdminReviewResponse"
+                            }
+                        }
                     },
-                    "409": {
-                        "description": "Cannot delete a booked slot",
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -355,94 +392,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/psychologist/slots/{id}/cancel": {
+        "/calendar/feed": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Psychologist cancels a session. Frees the slot and triggers a cancellation email to the student.",
+                "description": "Returns a secret URL serving upcoming booked sessions as an iCal feed (the psychologist's schedule or the student's appointments). Any previous URL stops working.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
-                ],
-                "summary": "Psychologist cancels a booked appointment",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Slot ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "calendar"
                 ],
+                "summary": "Create or rotate the iCal subscription URL",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "$ref": "#/definitions/models.CalendarFeedResponse"
                         }
                     },
                     "403": {
-                        "description": "Not authorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Slot not found",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Slot is not booked",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/psychologist/slots/{id}/notes": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Psychologist writes private notes after a session. Hidden from the student.",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "psychologist-slots"
-                ],
-                "summary": "Add private notes to a session",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Slot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "The private notes",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AddNoteInput"
-                        }
-                    }
+                    "calendar"
                 ],
+                "summary": "Revoke the iCal subscription URL",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -453,63 +451,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/psychologist/slots/{id}/recommendations": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/calendar/ics/{token}": {
+            "get": {
+                "description": "Public endpoint for calendar apps. The secret token in the path is the only authentication.",
+                "produces": [
+                    "text/calendar"
                 ],
-                "description": "Psychologist writes post-session recommendations. This is visible to the student and triggers an email notification.",
                 "tags": [
-                    "psychologist-slots"
+                    "calendar"
                 ],
-                "summary": "Add recommendations for a student",
+                "summary": "iCal feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Slot ID (UUID of the completed session)",
-                        "name": "id",
+                        "description": "Feed token, optionally followed by .ics",
+                        "name": "token",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "The recommendations text",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RecommendationInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Recommendations successfully saved and student notified",
-                        "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Not authorized or not the owner of this session",
+                        "description": "text/calendar body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Slot not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Database or internal server error",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -517,53 +486,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/psychologist/statistics": {
+        "/psychologist/reviews": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns meaningful KPIs: Load, Ratings, Trends, and efficiency.",
+                "description": "Psychologist views their ratings and written reviews. Student identities and exact dates are anonymized to protect privacy.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "psychologist-slots"
                 ],
-                "summary": "Get psychologist dashboard statistics",
+                "summary": "Get anonymous reviews",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PsychologistStats"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AnonymousReviewResponse"
+                            }
                         }
                     }
                 }
             }
         },
-        "/psychologist/students/{student_id}/history": {
+        "/psychologist/slots": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Psychologist views all past sessions and private notes for a specific student.",
+                "description": "Returns all slots for the logged-in psychologist, including student details for booked slots.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "psychologist-slots"
                 ],
-                "summary": "Get a student's session history",
+                "summary": "Get psychologist's own schedule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Student ID",
-                        "name": "student_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter period: 'day', 'week', 'month' (default: day)",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date for filter (YYYY-MM-DD), default: today",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -572,62 +549,67 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.StudentHistoryResponse"
+                                "$ref": "#/definitions/models.PsychologistScheduleResponse"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/slots": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns free slots for a given psychologist and date, enriched with psychologist name via gRPC.",
+                "description": "Psychologist generates multiple slots based on a weekly schedule pattern between start_date and end_date.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "slots"
+                    "psychologist-slots"
                 ],
-                "summary": "Get available slots for a specific date",
+                "summary": "Create schedule slots for a psychologist",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Psychologist ID",
-                        "name": "psychologist_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date in format YYYY-MM-DD",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "Schedule configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateScheduleInput"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SlotResponse"
-                            }
+                            "$ref": "#/definitions/models.ScheduleCreatedResponse"
                         }
                     },
                     "400": {
-                        "description": "missing params",
+                        "description": "validation error or no slots created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "only psychologists can create slots",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "database or gRPC error",
+                        "description": "database error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -635,41 +617,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/slots/calendar": {
-            "get": {
+        "/psychologist/slots/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns dates within the specified month where the psychologist has at least one free slot.",
+                "description": "Allows a psychologist to remove an available slot from their schedule. Fails if the slot is already booked.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "slots"
+                    "psychologist-slots"
                 ],
-                "summary": "Get calendar days with available slots",
+                "summary": "Delete an unbooked slot",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Psychologist ID",
-                        "name": "psychologist_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Year (e.g. 2026)",
-                        "name": "year",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Month (1-12 or 01-12)",
-                        "name": "month",
-                        "in": "query",
+                        "description": "Slot ID (UUID)",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -677,45 +645,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CalendarAvailabilityResponse"
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "missing params",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/student/appointments": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns a list of all upcoming and past appointments booked by the logged-in student.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "student-booking"
-                ],
-                "summary": "Get student's booked appointments",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Not authorized or not the owner",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.StudentAppointmentResponse"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not authorized",
+                    "404": {
+                        "description": "Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot delete a booked slot",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -729,14 +681,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/slots/{id}/book": {
-            "post": {
+        "/psychologist/slots/{id}/attendance": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student books a specific slot. Uses optimistic locking on the version field to avoid double-booking.",
+                "description": "Psychologist marks a started session as completed, no_show or canceled. Only booked sessions can be marked, and a no_show can later be corrected to completed. Students with repeated no-shows are temporarily blocked from booking.",
                 "consumes": [
                     "application/json"
                 ],
@@ -744,24 +696,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-slots"
                 ],
-                "summary": "Book a slot as a student",
+                "summary": "Record what happened to a session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Slot ID",
+                        "description": "Slot ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Booking details: type and answers",
+                        "description": "New session state",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BookSlotInput"
+                            "$ref": "#/definitions/models.AttendanceInput"
                         }
                     }
                 ],
@@ -773,25 +725,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid request body",
+                        "description": "Invalid status or session has not started yet",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "slot not found",
+                    "403": {
+                        "description": "Not authorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "slot already booked or just booked",
+                    "404": {
+                        "description": "Slot not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "database error",
+                    "409": {
+                        "description": "Transition not allowed or slot changed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -799,21 +751,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/slots/{id}/cancel": {
+        "/psychologist/slots/{id}/cancel": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student cancels their own booking. This resets the slot to 'available' and notifies the waitlist.",
+                "description": "Psychologist cancels a session. Frees the slot and triggers a cancellation email to the student.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-slots"
                 ],
-                "summary": "Cancel a booked appointment",
+                "summary": "Psychologist cancels a booked appointment",
                 "parameters": [
                     {
                         "type": "string",
@@ -825,19 +777,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Appointment successfully canceled",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "403": {
-                        "description": "Not authorized or not the owner",
+                        "description": "Not authorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -854,8 +800,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Database error",
+                    "503": {
+                        "description": "User service unavailable, nothing was changed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -863,90 +809,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/slots/{id}/confirm": {
-            "post": {
+        "/psychologist/slots/{id}/notes": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Finalizes the reservation by submitting the questionnaire and student's phone number. Requires a previous 'reserve' action.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the latest revision of the note, signed or not. The read is recorded in the note access log.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-notes"
                 ],
-                "summary": "Confirm a booked appointment",
+                "summary": "Get the current session note",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Slot ID (Must be currently 'reserved')",
+                        "description": "Slot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Booking details (type, phone, answers)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.BookSlotInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.SessionNoteResponse"
                         }
                     },
                     "403": {
-                        "description": "Not authorized or no active reservation",
+                        "description": "Not your session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Slot not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Reservation expired or conflict",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Database or gRPC error",
+                        "description": "No note for this session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/student/slots/{id}/rate": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student leaves a 1-5 star rating and an optional review for a completed appointment.",
+                "description": "Psychologist writes the structured note for a session. Every save adds an immutable revision; earlier ones are kept. Hidden from the student. Fails once the note is signed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -954,9 +868,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-notes"
                 ],
-                "summary": "Rate a completed session",
+                "summary": "Save a new revision of a session note",
                 "parameters": [
                     {
                         "type": "string",
@@ -966,12 +880,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Rating and Review",
+                        "description": "Full content of the new revision",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RateSessionInput"
+                            "$ref": "#/definitions/models.SessionNoteInput"
                         }
                     }
                 ],
@@ -979,17 +893,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "$ref": "#/definitions/models.SessionNoteRevisionSummary"
                         }
                     },
                     "400": {
-                        "description": "Invalid rating (must be 1-5) or session not finished",
+                        "description": "Invalid input or session not booked",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Not authorized",
+                        "description": "Not your session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1001,7 +915,13 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Session already rated",
+                        "description": "Note is signed and locked, or saved by someone else at the same time",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1009,75 +929,101 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/slots/{id}/reschedule": {
-            "post": {
+        "/psychologist/slots/{id}/notes/revisions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student moves their booking from one slot to another available slot atomically. Alerts waitlist for the old slot.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+                "description": "Returns every revision without its content, newest first.",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-notes"
                 ],
-                "summary": "Reschedule an appointment",
+                "summary": "List the revisions of a session note",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Old Slot ID (The one currently booked)",
+                        "description": "Slot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "The new Slot ID to move to",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RescheduleInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Appointment successfully rescheduled",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SessionNoteRevisionSummary"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body or same slot IDs",
+                    "403": {
+                        "description": "Not your session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not authorized or not the owner",
+                    "404": {
+                        "description": "No note for this session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/psychologist/slots/{id}/notes/revisions/{revision}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the content of one revision. The read is recorded in the note access log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-notes"
+                ],
+                "summary": "Get an earlier revision of a session note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "404": {
-                        "description": "Slot not found",
+                    {
+                        "type": "integer",
+                        "description": "Revision number, starting at 1",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.SessionNoteResponse"
                         }
                     },
-                    "409": {
-                        "description": "New slot already booked or race condition",
+                    "403": {
+                        "description": "Not your session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Database error",
+                    "404": {
+                        "description": "No such revision",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1085,14 +1031,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/slots/{id}/reserve": {
+        "/psychologist/slots/{id}/notes/sign": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student books a specific slot. Uses optimistic locking on the version field to avoid double-booking.",
+                "description": "Psychologist signs the latest revision of the note, which locks it against further edits.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1100,9 +1046,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-booking"
+                    "psychologist-notes"
                 ],
-                "summary": "Book a slot as a student",
+                "summary": "Sign a session note",
                 "parameters": [
                     {
                         "type": "string",
@@ -1112,12 +1058,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Booking details: type and answers",
+                        "description": "Revision being signed, must be the latest",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BookSlotInput"
+                            "$ref": "#/definitions/models.SignNoteInput"
                         }
                     }
                 ],
@@ -1128,26 +1074,84 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "invalid request body",
+                    "403": {
+                        "description": "Not your session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "slot not found",
+                        "description": "No note for this session",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "slot already booked or just booked",
+                        "description": "Already signed or not the latest revision",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/psychologist/slots/{id}/recommendations": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Psychologist writes post-session recommendations. This is visible to the student and triggers an email notification.",
+                "tags": [
+                    "psychologist-slots"
+                ],
+                "summary": "Add recommendations for a student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID (UUID of the completed session)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The recommendations text",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecommendationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendations successfully saved and student notified",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized or not the owner of this session",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Slot not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "database error",
+                        "description": "Database or internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1155,66 +1159,109 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/waitlist": {
+        "/psychologist/statistics": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student sees all the dates and psychologists they are waiting for.",
+                "description": "Returns meaningful KPIs: Load, Ratings, Trends, and efficiency.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-waitlist"
+                    "psychologist-slots"
+                ],
+                "summary": "Get psychologist dashboard statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PsychologistStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/psychologist/students/{student_id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Psychologist views all past sessions for a specific student with the latest signed revision of each session note. Earlier revisions are available from the session note endpoints. Every note read is recorded in the access log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-slots"
+                ],
+                "summary": "Get a student's session history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "View my waitlists",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.WaitlistResponse"
+                                "$ref": "#/definitions/models.StudentHistoryResponse"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/psychologist/students/{student_id}/mood": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student joins a waitlist for a specific psychologist on a specific date.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the mood entries the student shared with the psychologist, oldest first. Requires a session with the student that is booked or has taken place (completed or no-show) and the student's consent, which they can revoke at any time. The range defaults to the last 30 days.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-waitlist"
+                    "psychologist-slots"
                 ],
-                "summary": "Join a waitlist",
+                "summary": "Get a student's mood timeline",
                 "parameters": [
                     {
-                        "description": "Waitlist details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.JoinWaitlistInput"
-                        }
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "$ref": "#/definitions/models.StudentMoodResponse"
                         }
                     },
                     "400": {
@@ -1223,8 +1270,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Already on waitlist",
+                    "403": {
+                        "description": "No session or no consent",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1232,52 +1279,1119 @@ const docTemplate = `{
                 }
             }
         },
-        "/student/waitlist/{id}": {
-            "delete": {
+        "/psychologist/templates": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student removes themselves from a specific waitlist entry.",
+                "description": "Returns all recurring availability templates of the logged-in psychologist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-waitlist"
-                ],
-                "summary": "Leave a waitlist",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Waitlist Entry ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "psychologist-templates"
                 ],
+                "summary": "List availability templates",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AvailabilityTemplate"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.AdminDashboard": {
-            "type": "object",
-            "properties": {
-                "most_popular_psych_id": {
-                    "type": "string"
-                },
-                "most_popular_psych_name": {
-                    "type": "string"
-                },
-                "offline_ratio": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves a weekly schedule pattern. Slots are generated from it immediately and kept generated a few weeks ahead by a background worker.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-templates"
+                ],
+                "summary": "Create a recurring availability template",
+                "parameters": [
+                    {
+                        "description": "Template configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailabilityTemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailabilityTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "validation error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "only psychologists can create templates",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/psychologist/templates/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the template's pattern and regenerates its future available slots. Reserved and booked slots are never touched.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-templates"
+                ],
+                "summary": "Update an availability template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailabilityTemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailabilityTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the template and its future available slots. Reserved and booked slots are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-templates"
+                ],
+                "summary": "Delete an availability template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/psychologist/time-off": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the logged-in psychologist's time-off blocks, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-time-off"
+                ],
+                "summary": "List time-off blocks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimeOff"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes free and reserved slots and waitlists in the range and prevents new slots there. Slots that have already started are not touched. Booked sessions in the range are marked canceled. Every student with a booked or reserved slot in the range is notified; with action \"reschedule\" they are also offered the nearest free slot.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-time-off"
+                ],
+                "summary": "Block a date range as time off",
+                "parameters": [
+                    {
+                        "description": "Date range (inclusive, in the psychologist's time zone)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeOffInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeOffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "validation error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "only psychologists can take time off",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "a booking changed concurrently, retry",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "students to notify could not be resolved",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/psychologist/time-off/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows slots to be created in the range again. Removed slots and canceled bookings are not restored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "psychologist-time-off"
+                ],
+                "summary": "Remove a time-off block",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Time-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/questionnaires": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the latest version of every active questionnaire. Each must be answered in the confirm request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "questionnaires"
+                ],
+                "summary": "Questionnaires to fill in when booking",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Questionnaire"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns free slots for a given psychologist and date, enriched with psychologist name via gRPC.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slots"
+                ],
+                "summary": "Get available slots for a specific date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Psychologist ID",
+                        "name": "psychologist_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in format YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SlotResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "missing params",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "database or gRPC error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slots/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns dates within the specified month where the psychologist has at least one free slot.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slots"
+                ],
+                "summary": "Get calendar days with available slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Psychologist ID",
+                        "name": "psychologist_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Year (e.g. 2026)",
+                        "name": "year",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (1-12 or 01-12)",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "missing params",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/appointments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a list of all upcoming and past appointments booked by the logged-in student.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Get student's booked appointments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StudentAppointmentResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/book": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student books a specific slot. Uses optimistic locking on the version field to avoid double-booking.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Book a slot as a student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking details: type and answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookSlotInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "booking limit reached or restricted after repeated no-shows",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "slot already booked or just booked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student cancels their own booking. This resets the slot to 'available' and notifies the waitlist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Cancel a booked appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment successfully canceled",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized or not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slot is not booked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "User service unavailable, nothing was changed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Finalizes the reservation by submitting the answers to every active questionnaire (see GET /questionnaires) and the student's phone number. Requires a previous 'reserve' action. Clients that predate questionnaires may send free-text \"answers\" instead while no questionnaire is active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Confirm a booked appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID (Must be currently 'reserved')",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking details (type, phone, questionnaires)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookSlotInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or questionnaire answers",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized or no active reservation",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Reservation expired, conflict or outdated questionnaire version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database or gRPC error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "User service unavailable, nothing was changed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/rate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student leaves a 1-5 star rating and an optional review for a completed appointment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Rate a completed session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and Review",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RateSessionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rating (must be 1-5) or session not completed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Session already rated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student moves their booking from one slot to another available slot atomically. Alerts waitlist for the old slot.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Reschedule an appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Old Slot ID (The one currently booked)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new Slot ID to move to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment successfully rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or same slot IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not authorized or not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "New slot already booked or race condition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "User service unavailable, nothing was changed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/slots/{id}/reserve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student books a specific slot. Uses optimistic locking on the version field to avoid double-booking.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-booking"
+                ],
+                "summary": "Book a slot as a student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking details: type and answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookSlotInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "booking limit reached or restricted after repeated no-shows",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "slot already booked or just booked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student sees all the dates and psychologists they are waiting for, with their position in line or the slot currently held for them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-waitlist"
+                ],
+                "summary": "View my waitlists",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WaitlistResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student joins a waitlist for a specific psychologist on a specific date. When a slot of that day frees up, it is held for the first student in line, who gets an email with a confirm link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-waitlist"
+                ],
+                "summary": "Join a waitlist",
+                "parameters": [
+                    {
+                        "description": "Waitlist details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JoinWaitlistInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Booking restricted after repeated no-shows",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already on waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/waitlist/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Student removes themselves from a specific waitlist entry. A slot held for them is passed on to the next student in line.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student-waitlist"
+                ],
+                "summary": "Leave a waitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Waitlist Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AdminDashboard": {
+            "type": "object",
+            "properties": {
+                "most_popular_psych_id": {
+                    "type": "string"
+                },
+                "most_popular_psych_name": {
+                    "type": "string"
+                },
+                "offline_ratio": {
                     "type": "number"
                 },
                 "online_ratio": {
@@ -1286,62 +2400,165 @@ const docTemplate = `{
                 "total_bookings": {
                     "type": "integer"
                 },
-                "total_waitlisted": {
-                    "type": "integer"
+                "total_waitlisted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AdminReviewResponse": {
+            "type": "object",
+            "properties": {
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "psychologist_name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "review": {
+                    "type": "string"
+                },
+                "slot_id": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AnonymousReviewResponse": {
+            "type": "object",
+            "properties": {
+                "month_year": {
+                    "description": "e.g., \"February 2026\"",
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "review": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Answer": {
+            "type": "object",
+            "properties": {
+                "question": {
+                    "type": "string"
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "q1"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
                 }
             }
         },
-        "models.AddNoteInput": {
+        "models.AttendanceInput": {
             "type": "object",
             "required": [
-                "notes"
+                "status"
             ],
             "properties": {
-                "notes": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "completed",
+                        "no_show",
+                        "canceled"
+                    ],
+                    "example": "completed"
                 }
             }
         },
-        "models.AdminReviewResponse": {
+        "models.AvailabilityTemplate": {
             "type": "object",
             "properties": {
-                "psychologist_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "psychologist_name": {
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "empty means open-ended",
                     "type": "string"
                 },
-                "rating": {
-                    "type": "integer"
+                "generated_until": {
+                    "description": "last date slots were generated for",
+                    "type": "string"
                 },
-                "review": {
+                "id": {
                     "type": "string"
                 },
-                "slot_id": {
+                "name": {
+                    "description": "e.g. \"Spring semester\"",
                     "type": "string"
                 },
-                "start_time": {
+                "psychologist_id": {
                     "type": "string"
                 },
-                "student_id": {
+                "schedule": {
+                    "description": "weekly pattern",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DaySchedule"
+                    }
+                },
+                "start_date": {
+                    "description": "\"2026-02-20\"",
                     "type": "string"
                 },
-                "student_name": {
+                "time_zone": {
+                    "description": "psychologist's zone the start times are in",
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.AnonymousReviewResponse": {
+        "models.AvailabilityTemplateInput": {
             "type": "object",
+            "required": [
+                "schedule",
+                "start_date"
+            ],
             "properties": {
-                "month_year": {
-                    "description": "e.g., \"February 2026\"",
+                "duration": {
+                    "description": "50 (default)",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "optional, \"2026-06-30\"",
                     "type": "string"
                 },
-                "rating": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "review": {
+                "schedule": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.DaySchedule"
+                    }
+                },
+                "start_date": {
+                    "description": "\"2026-02-20\"",
                     "type": "string"
                 }
             }
@@ -1354,7 +2571,9 @@ const docTemplate = `{
             ],
             "properties": {
                 "answers": {
-                    "type": "string"
+                    "description": "Deprecated: free-text answers from before questionnaires, kept when none are active",
+                    "type": "string",
+                    "maxLength": 2000
                 },
                 "booking_type": {
                     "type": "string",
@@ -1364,7 +2583,16 @@ const docTemplate = `{
                     ]
                 },
                 "phone_number": {
-                    "type": "string"
+                    "description": "E.164, also used for SMS notifications",
+                    "type": "string",
+                    "example": "+77011234567"
+                },
+                "questionnaires": {
+                    "description": "one per active questionnaire, see GET /questionnaires",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireSubmission"
+                    }
                 }
             }
         },
@@ -1383,6 +2611,15 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CalendarFeedResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8080/api/v1/calendar/ics/3f9a.ics"
+                }
+            }
+        },
         "models.CreateScheduleInput": {
             "type": "object",
             "required": [
@@ -1465,6 +2702,57 @@ const docTemplate = `{
                 }
             }
         },
+        "models.NoteAccessLog": {
+            "type": "object",
+            "properties": {
+                "accessed_at": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "type": "string",
+                    "example": "/api/v1/psychologist/students/:student_id/history"
+                },
+                "fields": {
+                    "type": "string",
+                    "example": "psychologist_notes,questionnaire_answers"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reader_id": {
+                    "type": "string"
+                },
+                "reader_role": {
+                    "description": "student, psychologist or admin",
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "session note revision, when a note was read",
+                    "type": "integer"
+                },
+                "slot_id": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "description": "whose data was read",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NoteKeyRotationResponse": {
+            "type": "object",
+            "properties": {
+                "active_key_id": {
+                    "type": "string",
+                    "example": "2026-10"
+                },
+                "reencrypted": {
+                    "description": "values rewritten by this run",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
         "models.PsychologistScheduleResponse": {
             "type": "object",
             "properties": {
@@ -1484,44 +2772,287 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "questionnaire_answers": {
+                    "description": "free text of bookings made before questionnaires",
                     "type": "string"
                 },
+                "questionnaires": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireResult"
+                    }
+                },
+                "scores": {
+                    "description": "PHQ-9, GAD-7 and other scored questionnaires",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireScore"
+                    }
+                },
                 "start_time": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PsychologistStats": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "cancellation_rate": {
+                    "description": "% of bookings cancelled by students",
+                    "type": "number"
+                },
+                "most_common_booking": {
+                    "description": "\"online\" or \"offline\"",
+                    "type": "string"
+                },
+                "no_show_rate": {
+                    "description": "% of attended-or-missed sessions the student missed",
+                    "type": "number"
+                },
+                "no_shows": {
+                    "type": "integer"
+                },
+                "sessions_this_month": {
+                    "type": "integer"
+                },
+                "total_sessions": {
+                    "description": "completed sessions",
+                    "type": "integer"
+                },
+                "unmarked_sessions": {
+                    "description": "past booked sessions whose attendance is not recorded yet",
+                    "type": "integer"
+                },
+                "upcoming_sessions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Question": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "q1"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionOption"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Little interest or pleasure in doing things"
+                },
+                "type": {
+                    "description": "single_choice, multi_choice, scale, text",
+                    "type": "string",
+                    "example": "single_choice"
+                }
+            }
+        },
+        "models.QuestionOption": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Several days"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "value": {
+                    "type": "string",
+                    "example": "several_days"
+                }
+            }
+        },
+        "models.Questionnaire": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "inactive codes are not asked at booking",
+                    "type": "boolean"
+                },
+                "bands": {
+                    "description": "severity labels for scored questionnaires",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScoreBand"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "example": "phq9"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "intro": {
+                    "type": "string",
+                    "example": "Over the last 2 weeks, how often have you been bothered by the following problems?"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Question"
+                    }
+                },
+                "scored": {
+                    "description": "sum of option scores and scale values",
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "PHQ-9"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.QuestionnaireActiveInput": {
+            "type": "object",
+            "required": [
+                "active"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.QuestionnaireInput": {
+            "type": "object",
+            "required": [
+                "code",
+                "questions",
+                "title"
+            ],
+            "properties": {
+                "bands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScoreBand"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "intake"
+                },
+                "intro": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.Question"
+                    }
+                },
+                "scored": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Intake"
+                }
+            }
+        },
+        "models.QuestionnaireResult": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Answer"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "example": "phq9"
+                },
+                "questionnaire_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "moderate"
                 },
-                "student_id": {
-                    "type": "string"
+                "title": {
+                    "type": "string",
+                    "example": "PHQ-9"
                 },
-                "student_name": {
-                    "type": "string"
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "models.PsychologistStats": {
+        "models.QuestionnaireScore": {
             "type": "object",
             "properties": {
-                "average_rating": {
-                    "type": "number"
-                },
-                "cancellation_rate": {
-                    "description": "% of bookings cancelled by students",
-                    "type": "number"
+                "code": {
+                    "type": "string",
+                    "example": "gad7"
                 },
-                "most_common_booking": {
-                    "description": "\"online\" or \"offline\"",
-                    "type": "string"
+                "score": {
+                    "type": "integer",
+                    "example": 6
                 },
-                "sessions_this_month": {
-                    "type": "integer"
+                "severity": {
+                    "type": "string",
+                    "example": "mild"
                 },
-                "total_sessions": {
-                    "type": "integer"
+                "title": {
+                    "type": "string",
+                    "example": "GAD-7"
+                }
+            }
+        },
+        "models.QuestionnaireSubmission": {
+            "type": "object",
+            "required": [
+                "questionnaire_id"
+            ],
+            "properties": {
+                "answers": {
+                    "type": "object"
                 },
-                "upcoming_sessions": {
-                    "type": "integer"
+                "questionnaire_id": {
+                    "type": "string"
                 }
             }
         },
@@ -1579,6 +3110,150 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ScoreBand": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "min": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.SessionNoteInput": {
+            "type": "object",
+            "required": [
+                "risk_level"
+            ],
+            "properties": {
+                "interventions": {
+                    "type": "string",
+                    "example": "Psychoeducation on sleep hygiene, breathing exercise"
+                },
+                "plan": {
+                    "type": "string",
+                    "example": "Follow-up in one week, sleep diary"
+                },
+                "presenting_concern": {
+                    "type": "string",
+                    "example": "Exam stress, poor sleep for two weeks"
+                },
+                "risk_assessment": {
+                    "type": "string",
+                    "example": "No suicidal ideation reported"
+                },
+                "risk_level": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "moderate",
+                        "high"
+                    ],
+                    "example": "none"
+                }
+            }
+        },
+        "models.SessionNoteResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "latest_revision": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "revision": {
+                    "$ref": "#/definitions/models.SessionNoteRevisionResponse"
+                },
+                "signed_at": {
+                    "type": "string"
+                },
+                "signed_revision": {
+                    "type": "integer"
+                },
+                "slot_id": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SessionNoteRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "interventions": {
+                    "type": "string"
+                },
+                "plan": {
+                    "type": "string"
+                },
+                "presenting_concern": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "risk_assessment": {
+                    "type": "string"
+                },
+                "risk_level": {
+                    "type": "string",
+                    "example": "low"
+                },
+                "signed": {
+                    "description": "true for the revision the note was signed at",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SessionNoteRevisionSummary": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "risk_level": {
+                    "type": "string",
+                    "example": "none"
+                },
+                "signed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SignNoteInput": {
+            "type": "object",
+            "required": [
+                "revision"
+            ],
+            "properties": {
+                "revision": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                }
+            }
+        },
         "models.SlotResponse": {
             "type": "object",
             "properties": {
@@ -1624,11 +3299,22 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "questionnaire_answers": {
+                    "description": "free text of bookings made before questionnaires",
                     "type": "string"
                 },
+                "questionnaires": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireResult"
+                    }
+                },
                 "start_time": {
                     "type": "string"
                 },
+                "status": {
+                    "description": "booked, completed, no_show or canceled",
+                    "type": "string"
+                },
                 "student_recommendations": {
                     "type": "string"
                 }
@@ -1640,17 +3326,196 @@ const docTemplate = `{
                 "booking_type": {
                     "type": "string"
                 },
+                "has_unsigned_draft": {
+                    "description": "a note exists but is not signed yet",
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "latest signed revision",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SessionNoteRevisionResponse"
+                        }
+                    ]
+                },
+                "note_revisions": {
+                    "description": "all revisions, fetch earlier ones by number",
+                    "type": "integer"
+                },
                 "psychologist_notes": {
+                    "description": "free-text notes written before structured notes existed",
                     "type": "string"
                 },
                 "questionnaire_answers": {
+                    "description": "free text of bookings made before questionnaires",
                     "type": "string"
                 },
+                "questionnaires": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireResult"
+                    }
+                },
+                "scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionnaireScore"
+                    }
+                },
                 "slot_id": {
                     "type": "string"
                 },
                 "start_time": {
                     "type": "string"
+                },
+                "status": {
+                    "description": "booked (not marked yet), completed or no_show",
+                    "type": "string"
+                }
+            }
+        },
+        "models.StudentMoodEntry": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-03-02"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logged_at": {
+                    "type": "string",
+                    "example": "2026-03-02T21:15:00+05:00"
+                },
+                "mood": {
+                    "type": "string",
+                    "example": "Anxiously"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sleep",
+                        "exams"
+                    ]
+                }
+            }
+        },
+        "models.StudentMoodResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentMoodEntry"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-02-01"
+                },
+                "notes_shared": {
+                    "description": "journal notes are included only if the student chose to share them",
+                    "type": "boolean"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-03-02"
+                }
+            }
+        },
+        "models.TimeOff": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "cancel or reschedule",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "\"2026-03-14\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "e.g. \"Vacation\", never shown to students",
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "\"2026-03-01\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimeOffInput": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "action": {
+                    "description": "for booked sessions, default \"cancel\"",
+                    "type": "string",
+                    "enum": [
+                        "cancel",
+                        "reschedule"
+                    ]
+                },
+                "end_date": {
+                    "description": "\"2026-03-14\", inclusive",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "start_date": {
+                    "description": "\"2026-03-01\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimeOffResponse": {
+            "type": "object",
+            "properties": {
+                "canceled_bookings": {
+                    "description": "booked sessions canceled",
+                    "type": "integer",
+                    "example": 2
+                },
+                "removed_slots": {
+                    "description": "free or reserved slots deleted",
+                    "type": "integer",
+                    "example": 12
+                },
+                "reschedule_offers": {
+                    "description": "students of canceled or removed reserved slots who got a suggested slot",
+                    "type": "integer",
+                    "example": 1
+                },
+                "time_off": {
+                    "$ref": "#/definitions/models.TimeOff"
                 }
             }
         },
@@ -1663,14 +3528,30 @@ const docTemplate = `{
                 "date": {
                     "type": "string"
                 },
+                "hold_expires_at": {
+                    "description": "confirm before this time",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "position": {
+                    "description": "1 = next in line, only while waiting",
+                    "type": "integer"
+                },
                 "psychologist_id": {
                     "type": "string"
                 },
                 "psychologist_name": {
                     "type": "string"
+                },
+                "slot_id": {
+                    "description": "held slot to confirm, only while offered",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "waiting or offered",
+                    "type": "string"
                 }
             }
         }
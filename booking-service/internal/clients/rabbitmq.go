@@ -20,10 +20,18 @@ type NotificationMessage struct {
 	Data    map[string]string `json:"data"`
 }
 
+// UserEventMessage is consumed by user-service. "new_rating" uses PsychologistID and Rating,
+// "risk_signal" uses the student and rule fields.
 type UserEventMessage struct {
 	Type           string `json:"type"`
 	PsychologistID string `json:"psychologist_id"`
 	Rating         int    `json:"rating"`
+	StudentID      string `json:"student_id,omitempty"`
+	SlotID         string `json:"slot_id,omitempty"`
+	Rule           string `json:"rule,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	Detail         string `json:"detail,omitempty"`
 }
 
 // NewRabbitMQClient creates a new publisher using the global config connection
@@ -83,6 +91,21 @@ func (r *RabbitMQClient) PublishUserEvent(msg UserEventMessage) error {
 	return nil
 }
 
+// PublishRaw sends an already encoded JSON body to the given queue.
+// Used by the outbox relay, which stores messages pre-serialized.
+func (r *RabbitMQClient) PublishRaw(queue string, body []byte) error {
+	return r.ch.Publish(
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Body:         body,
+		})
+}
+
 // Close cleanly shuts down the connection
 func (r *RabbitMQClient) Close() {
 	r.ch.Close()
@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/booking"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type BookingServer struct {
+	booking.UnimplementedBookingServiceServer
+	UserClient userprofile.UserProfileServiceClient
+}
+
+func NewBookingServer(userClient userprofile.UserProfileServiceClient) *BookingServer {
+	return &BookingServer{UserClient: userClient}
+}
+
+func (s *BookingServer) GetUpcomingAppointments(ctx context.Context, req *booking.GetUpcomingAppointmentsRequest) (*booking.GetUpcomingAppointmentsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	column := "student_id"
+	if req.Role == "psychologist" {
+		column = "psychologist_id"
+	}
+
+	var slots []models.Slot
+	if err := config.DB.WithContext(ctx).
+		Where(column+" = ? AND status = ? AND start_time > ?", req.UserId, models.StatusBooked, time.Now()).
+		Order("start_time asc").
+		Find(&slots).Error; err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	// Name the other side of each session; the list is still useful without names
+	counterpart := func(s models.Slot) string {
+		if req.Role == "psychologist" {
+			if s.StudentID == nil {
+				return ""
+			}
+			return *s.StudentID
+		}
+		return s.PsychologistID
+	}
+	var ids []string
+	for _, s := range slots {
+		if id := counterpart(s); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	names := make(map[string]string)
+	if len(ids) > 0 && s.UserClient != nil {
+		resp, err := s.UserClient.GetBatchUserProfiles(ctx, &userprofile.GetBatchUserProfilesRequest{Ids: ids})
+		if err == nil {
+			for _, p := range resp.Profiles {
+				names[p.Id] = p.FullName
+			}
+		} else {
+			log.Printf("Failed to fetch profiles for upcoming appointments: %v", err)
+		}
+	}
+
+	res := &booking.GetUpcomingAppointmentsResponse{}
+	for _, slot := range slots {
+		res.Appointments = append(res.Appointments, &booking.Appointment{
+			SlotId:      slot.ID,
+			StartTime:   slot.StartTime.UTC().Format(time.RFC3339),
+			BookingType: slot.BookingType,
+			With:        names[counterpart(slot)],
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/booking"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// MockUserClient mocks the gRPC client; only the batch profile lookup is used here
+type MockUserClient struct {
+	userprofile.UserProfileServiceClient
+	mock.Mock
+}
+
+func (m *MockUserClient) GetBatchUserProfiles(ctx context.Context, in *userprofile.GetBatchUserProfilesRequest, opts ...grpc.CallOption) (*userprofile.GetBatchUserProfilesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetBatchUserProfilesResponse), args.Error(1)
+}
+
+func setupSlots(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Slot{}))
+	config.DB = db
+
+	student, other := "student-1", "student-2"
+	now := time.Now()
+	for _, s := range []models.Slot{
+		{ID: "later", StudentID: &student, StartTime: now.Add(48 * time.Hour), Status: models.StatusBooked, BookingType: "offline"},
+		{ID: "sooner", StudentID: &student, StartTime: now.Add(24 * time.Hour), Status: models.StatusBooked, BookingType: "online"},
+		{ID: "past", StudentID: &student, StartTime: now.Add(-24 * time.Hour), Status: models.StatusBooked},
+		{ID: "reserved", StudentID: &student, StartTime: now.Add(72 * time.Hour), Status: models.StatusReserved},
+		{ID: "someone-else", StudentID: &other, StartTime: now.Add(96 * time.Hour), Status: models.StatusBooked},
+	} {
+		s.PsychologistID = "psych-1"
+		s.Duration = 50
+		require.NoError(t, db.Create(&s).Error)
+	}
+}
+
+func TestGetUpcomingAppointments(t *testing.T) {
+	setupSlots(t)
+	client := new(MockUserClient)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: []*userprofile.BasicUserProfile{
+			{Id: "psych-1", FullName: "Dr. Psych"},
+			{Id: "student-1", FullName: "Aruzhan"},
+		}}, nil)
+	server := NewBookingServer(client)
+
+	resp, err := server.GetUpcomingAppointments(context.Background(), &booking.GetUpcomingAppointmentsRequest{UserId: "student-1", Role: "student"})
+	require.NoError(t, err)
+	require.Len(t, resp.Appointments, 2)
+	assert.Equal(t, "sooner", resp.Appointments[0].SlotId)
+	assert.Equal(t, "online", resp.Appointments[0].BookingType)
+	assert.Equal(t, "Dr. Psych", resp.Appointments[0].With)
+	assert.Equal(t, "later", resp.Appointments[1].SlotId)
+	_, err = time.Parse(time.RFC3339, resp.Appointments[0].StartTime)
+	assert.NoError(t, err)
+
+	resp, err = server.GetUpcomingAppointments(context.Background(), &booking.GetUpcomingAppointmentsRequest{UserId: "psych-1", Role: "psychologist"})
+	require.NoError(t, err)
+	require.Len(t, resp.Appointments, 3)
+	assert.Equal(t, "Aruzhan", resp.Appointments[0].With)
+}
+
+func TestGetUpcomingAppointmentsWithoutNames(t *testing.T) {
+	setupSlots(t)
+	client := new(MockUserClient)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+	resp, err := NewBookingServer(client).GetUpcomingAppointments(context.Background(), &booking.GetUpcomingAppointmentsRequest{UserId: "student-1", Role: "student"})
+	require.NoError(t, err)
+	require.Len(t, resp.Appointments, 2)
+	assert.Empty(t, resp.Appointments[0].With)
+}
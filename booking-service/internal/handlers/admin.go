@@ -9,6 +9,7 @@ import (
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"gorm.io/gorm"
 )
 
 // GetAllBookings godoc
@@ -28,7 +29,8 @@ func (h *BookingHandler) GetAllBookings(c *gin.Context) {
 	}
 
 	var slots []models.Slot
-	if err := config.DB.Where("status = ?", models.StatusBooked).Find(&slots).Error; err != nil {
+	if err := config.DB.Where("status IN ?", []string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow, models.StatusCanceled}).
+		Find(&slots).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
 		return
 	}
@@ -56,21 +58,41 @@ func (h *BookingHandler) ForceCancelBooking(c *gin.Context) {
 
 	slotID := c.Param("id")
 
+	var slot models.Slot
+	if err := config.DB.First(&slot, "id = ?", slotID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Booking not found or already canceled"})
+		return
+	}
+	candidates := h.waitlistCandidates(c.Request.Context(), slot)
+
+	tx := config.DB.Begin()
+
 	// Logic: Same as CancelAppointment, but skip the "is owner" check
-	result := config.DB.Model(&models.Slot{}).
-		Where("id = ? AND status = ?", slotID, models.StatusBooked).
-		Updates(map[string]interface{}{
-			"status":                models.StatusAvailable,
+	result := models.TransitionSlot(tx.Where("id = ?", slotID),
+		models.StatusBooked, models.StatusAvailable, map[string]interface{}{
 			"student_id":            nil,
 			"booking_type":          "",
 			"questionnaire_answers": "",
+			"version":               gorm.Expr("version + 1"),
 		})
 
 	if result.RowsAffected == 0 {
+		tx.Rollback()
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Booking not found or already canceled"})
 		return
 	}
 
+	if err := promoteWaitlist(tx, slotID, candidates); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.MessageResponse{Message: "Booking force-canceled by admin"})
 }
 
@@ -101,9 +123,10 @@ func (h *BookingHandler) GetDashboard(c *gin.Context) {
 	var stats AdminDashboard
 	var total int64
 
-	// Booked stats
-	config.DB.Model(&models.Slot{}).Where("status = ?", models.StatusBooked).Count(&stats.TotalBookings)
-	config.DB.Model(&models.Slot{}).Where("status = ?", models.StatusBooked).Count(&total)
+	// Booked stats: upcoming sessions plus those that took place or were missed
+	held := []string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow}
+	config.DB.Model(&models.Slot{}).Where("status IN ?", held).Count(&stats.TotalBookings)
+	config.DB.Model(&models.Slot{}).Where("status IN ?", held).Count(&total)
 
 	var onlineCount int64
 	config.DB.Model(&models.Slot{}).Where("booking_type = ?", "online").Count(&onlineCount)
@@ -122,7 +145,7 @@ func (h *BookingHandler) GetDashboard(c *gin.Context) {
 	var res Result
 	err := config.DB.Model(&models.Slot{}).
 		Select("psychologist_id, count(*) as count").
-		Where("status = ?", models.StatusBooked).
+		Where("status IN ?", held).
 		Group("psychologist_id").
 		Order("count desc").
 		Limit(1).
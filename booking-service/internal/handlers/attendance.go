@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// MarkAttendance godoc
+// @Summary      Record what happened to a session
+// @Description  Psychologist marks a started session as completed, no_show or canceled. Only booked sessions can be marked, and a no_show can later be corrected to completed. Students with repeated no-shows are temporarily blocked from booking.
+// @Tags         psychologist-slots
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                  true  "Slot ID (UUID)"
+// @Param        request  body      models.AttendanceInput  true  "New session state"
+// @Success      200      {object}  models.MessageResponse
+// @Failure      400      {object}  models.ErrorResponse "Invalid status or session has not started yet"
+// @Failure      403      {object}  models.ErrorResponse "Not authorized"
+// @Failure      404      {object}  models.ErrorResponse "Slot not found"
+// @Failure      409      {object}  models.ErrorResponse "Transition not allowed or slot changed"
+// @Router       /psychologist/slots/{id}/attendance [put]
+func (h *BookingHandler) MarkAttendance(c *gin.Context) {
+	slotID := c.Param("id")
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can mark attendance"})
+		return
+	}
+
+	var input models.AttendanceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var slot models.Slot
+	if err := config.DB.First(&slot, "id = ?", slotID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Slot not found"})
+		return
+	}
+
+	if slot.PsychologistID != psychID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only mark your own sessions"})
+		return
+	}
+
+	if slot.StudentID == nil || !models.CanTransition(slot.Status, input.Status) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: fmt.Sprintf("A %s session cannot be marked as %s", slot.Status, input.Status),
+		})
+		return
+	}
+
+	// Sessions that have not started yet are canceled through the cancel endpoint, which frees the slot
+	if time.Now().Before(slot.StartTime) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Attendance can only be marked once the session has started"})
+		return
+	}
+
+	res := models.TransitionSlot(config.DB.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		slot.Status, input.Status, map[string]interface{}{
+			"version": slot.Version + 1,
+		})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The session was changed by someone else. Please try again."})
+		return
+	}
+
+	logBookingAction(slot.ID, slot.PsychologistID, *slot.StudentID, input.Status)
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Session marked as " + input.Status})
+}
+
+// noShowLimit is how many no-shows within noShowWindow block a student from booking: NO_SHOW_LIMIT (default 3, 0 disables)
+func noShowLimit() int {
+	value := os.Getenv("NO_SHOW_LIMIT")
+	if value == "" {
+		return 3
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 3
+	}
+	return limit
+}
+
+// noShowWindow is how far back no-shows are counted: NO_SHOW_WINDOW_DAYS (default 90)
+func noShowWindow() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("NO_SHOW_WINDOW_DAYS"))
+	if err != nil || days <= 0 {
+		days = 90
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// noShowRestricted reports whether the student missed too many recent sessions to book new ones
+func noShowRestricted(db *gorm.DB, studentID string) (bool, error) {
+	limit := noShowLimit()
+	if limit <= 0 {
+		return false, nil
+	}
+
+	var missed int64
+	err := db.Model(&models.Slot{}).
+		Where("student_id = ? AND status = ? AND start_time >= ?", studentID, models.StatusNoShow, time.Now().Add(-noShowWindow())).
+		Count(&missed).Error
+	return missed >= int64(limit), err
+}
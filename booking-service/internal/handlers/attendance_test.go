@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB points config.DB at a fresh in-memory database for the test
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Slot{}, &models.BookingLog{}, &models.OutboxMessage{}, &models.WaitlistEntry{},
+		&models.SessionNote{}, &models.SessionNoteRevision{}, &models.NoteAccessLog{}, &models.TimeOff{}))
+	config.DB = db
+}
+
+func createSlot(t *testing.T, id, status string, studentID *string, start time.Time) {
+	t.Helper()
+	require.NoError(t, config.DB.Create(&models.Slot{
+		ID:             id,
+		PsychologistID: "psych-1",
+		StudentID:      studentID,
+		StartTime:      start,
+		Duration:       50,
+		Status:         status,
+	}).Error)
+}
+
+func markAttendance(slotID, status string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/psychologist/slots/:id/attendance", (&BookingHandler{}).MarkAttendance)
+
+	req := httptest.NewRequest(http.MethodPut, "/psychologist/slots/"+slotID+"/attendance", strings.NewReader(`{"status":"`+status+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "psych-1")
+	req.Header.Set("X-User-Role", "psychologist")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMarkAttendanceFollowsTransitions(t *testing.T) {
+	setupTestDB(t)
+	student := "student-1"
+	past := time.Now().Add(-2 * time.Hour)
+	createSlot(t, "booked", models.StatusBooked, &student, past)
+	createSlot(t, "completed", models.StatusCompleted, &student, past.Add(-time.Hour))
+	createSlot(t, "future", models.StatusBooked, &student, time.Now().Add(time.Hour))
+
+	w := markAttendance("booked", models.StatusNoShow)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var slot models.Slot
+	config.DB.First(&slot, "id = ?", "booked")
+	assert.Equal(t, models.StatusNoShow, slot.Status)
+	assert.Equal(t, 2, slot.Version)
+
+	// A no-show can be corrected once
+	assert.Equal(t, http.StatusOK, markAttendance("booked", models.StatusCompleted).Code)
+
+	assert.Equal(t, http.StatusConflict, markAttendance("completed", models.StatusNoShow).Code)
+	assert.Equal(t, http.StatusBadRequest, markAttendance("future", models.StatusCompleted).Code)
+}
+
+func TestNoShowRestricted(t *testing.T) {
+	setupTestDB(t)
+	student := "student-1"
+	for i, ago := range []int{1, 10, 30, 120} {
+		createSlot(t, "no-show-"+string(rune('a'+i)), models.StatusNoShow, &student, time.Now().AddDate(0, 0, -ago))
+	}
+
+	cases := []struct {
+		name       string
+		limit      string
+		window     string
+		restricted bool
+	}{
+		{"default limit counts the last 90 days", "", "", true},
+		{"under the limit", "4", "", false},
+		{"old no-shows fall out of the window", "3", "20", false},
+		{"wider window", "4", "365", true},
+		{"zero disables", "0", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_SHOW_LIMIT", tc.limit)
+			t.Setenv("NO_SHOW_WINDOW_DAYS", tc.window)
+
+			restricted, err := noShowRestricted(config.DB, student)
+			require.NoError(t, err)
+			assert.Equal(t, tc.restricted, restricted)
+		})
+	}
+
+	restricted, err := noShowRestricted(config.DB, "student-2")
+	require.NoError(t, err)
+	assert.False(t, restricted)
+}
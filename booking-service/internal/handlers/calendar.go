@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/ical"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"gorm.io/gorm"
+)
+
+// CreateCalendarFeed godoc
+// @Summary      Create or rotate the iCal subscription URL
+// @Description  Returns a secret URL serving upcoming booked sessions as an iCal feed (the psychologist's schedule or the student's appointments). Any previous URL stops working.
+// @Tags         calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201 {object} models.CalendarFeedResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /calendar/feed [post]
+func (h *BookingHandler) CreateCalendarFeed(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "student" && role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only students and psychologists have calendars"})
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create calendar feed"})
+		return
+	}
+	token := hex.EncodeToString(raw)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.CalendarFeed{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.CalendarFeed{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			Role:      role,
+			TokenHash: hashFeedToken(token),
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CalendarFeedResponse{URL: calendarFeedURL(token)})
+}
+
+// DeleteCalendarFeed godoc
+// @Summary      Revoke the iCal subscription URL
+// @Tags         calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.MessageResponse
+// @Router       /calendar/feed [delete]
+func (h *BookingHandler) DeleteCalendarFeed(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	if err := config.DB.Where("user_id = ?", userID).Delete(&models.CalendarFeed{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Calendar feed revoked"})
+}
+
+// GetCalendarFeed godoc
+// @Summary      iCal feed
+// @Description  Public endpoint for calendar apps. The secret token in the path is the only authentication.
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        token path string true "Feed token, optionally followed by .ics"
+// @Success      200 {string} string "text/calendar body"
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /calendar/ics/{token} [get]
+func (h *BookingHandler) GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var feed models.CalendarFeed
+	if err := config.DB.First(&feed, "token_hash = ?", hashFeedToken(token)).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Calendar not found"})
+		return
+	}
+
+	column := "student_id"
+	if feed.Role == "psychologist" {
+		column = "psychologist_id"
+	}
+
+	var slots []models.Slot
+	if err := config.DB.
+		Where(column+" = ? AND status = ? AND start_time >= ?", feed.UserID, models.StatusBooked, time.Now().Add(-24*time.Hour)).
+		Order("start_time asc").
+		Find(&slots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	// The other side of each session is named in the event title
+	var counterpartIDs []string
+	for _, s := range slots {
+		if feed.Role == "psychologist" && s.StudentID != nil {
+			counterpartIDs = append(counterpartIDs, *s.StudentID)
+		} else {
+			counterpartIDs = append(counterpartIDs, s.PsychologistID)
+		}
+	}
+	names := make(map[string]string)
+	if len(counterpartIDs) > 0 {
+		resp, err := h.UserClient.GetBatchUserProfiles(c.Request.Context(), &userprofile.GetBatchUserProfilesRequest{Ids: counterpartIDs})
+		if err == nil {
+			for _, p := range resp.Profiles {
+				names[p.Id] = p.FullName
+			}
+		} else {
+			log.Printf("Failed to fetch profiles for calendar feed: %v", err)
+		}
+	}
+
+	var events []ical.Event
+	for _, s := range slots {
+		counterpart := names[s.PsychologistID]
+		if feed.Role == "psychologist" && s.StudentID != nil {
+			counterpart = names[*s.StudentID]
+		}
+		summary := "KBTU Care session"
+		if counterpart != "" {
+			summary += " with " + counterpart
+		}
+
+		events = append(events, ical.Event{
+			UID:         s.ID + "@kbtu-care",
+			Start:       s.StartTime,
+			Duration:    time.Duration(s.Duration) * time.Minute,
+			Summary:     summary,
+			Description: "Format: " + s.BookingType,
+			Sequence:    s.Version,
+		})
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ical.Feed("KBTU Care", events)))
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// calendarFeedURL builds the public subscription URL: PUBLIC_API_URL (default http://localhost:8080/api/v1) + /calendar/ics/<token>.ics
+func calendarFeedURL(token string) string {
+	base := os.Getenv("PUBLIC_API_URL")
+	if base == "" {
+		base = "http://localhost:8080/api/v1"
+	}
+	return strings.TrimRight(base, "/") + "/calendar/ics/" + token + ".ics"
+}
@@ -2,14 +2,22 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"gorm.io/gorm"
 )
 
 type BookingHandler struct {
@@ -22,55 +30,145 @@ func parseDate(dateStr string) (time.Time, error) {
 	return time.Parse("2006-01-02", dateStr)
 }
 
-// Helper to merge date "2026-01-01" with time "14:30"
-func combineDateAndTime(date time.Time, timeStr string) (time.Time, error) {
-	t, err := time.Parse("15:04", timeStr)
+// bookingContacts is what notifications about a booking need to know about both sides
+type bookingContacts struct {
+	StudentEmail    string
+	PsychName       string
+	StudentLocation *time.Location
+	PsychLocation   *time.Location
+}
+
+// errContactsUnavailable means the user service could not tell who to notify about a booking
+var errContactsUnavailable = errors.New("booking contacts unavailable")
+
+// lookupStudentAndPsych resolves the student's email, the psychologist's name and both time zones via gRPC.
+// It fails when the student cannot be resolved, so callers refuse the change instead of committing it without a notification.
+func (h *BookingHandler) lookupStudentAndPsych(ctx context.Context, studentID, psychID string) (bookingContacts, error) {
+	contacts := bookingContacts{
+		StudentLocation: scheduling.LoadLocation(""),
+		PsychLocation:   scheduling.LoadLocation(""),
+	}
+
+	resp, err := h.UserClient.GetBatchUserProfiles(ctx, &userprofile.GetBatchUserProfilesRequest{
+		Ids: []string{studentID, psychID},
+	})
 	if err != nil {
-		return time.Time{}, err
+		log.Printf("Failed to fetch profiles for notification: %v", err)
+		return contacts, fmt.Errorf("%w: %v", errContactsUnavailable, err)
 	}
-	// Return new date with specific hour/minute
-	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC), nil
+
+	for _, p := range resp.Profiles {
+		if p.Id == studentID {
+			contacts.StudentEmail = p.Email
+			contacts.StudentLocation = scheduling.LoadLocation(p.TimeZone)
+		} else if p.Id == psychID {
+			contacts.PsychName = p.FullName
+			contacts.PsychLocation = scheduling.LoadLocation(p.TimeZone)
+		}
+	}
+	if contacts.StudentEmail == "" {
+		log.Printf("No email for student %s, cannot notify about booking", studentID)
+		return contacts, fmt.Errorf("%w: no email for student %s", errContactsUnavailable, studentID)
+	}
+	return contacts, nil
 }
 
-// notifyWaitlist is a background helper that finds waitlisted students and emails them
-func (h *BookingHandler) notifyWaitlist(psychID string, dateStr string, psychName string) {
-	var waitlist []models.WaitlistEntry
-	config.DB.Where("psychologist_id = ? AND date = ?", psychID, dateStr).Find(&waitlist)
+// respondContactsUnavailable is the answer when lookupStudentAndPsych fails; nothing has been changed yet
+func respondContactsUnavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Could not reach the user service. Please try again."})
+}
 
-	if len(waitlist) == 0 {
-		return
+// userLocation returns the user's time zone, or the default one if user-service cannot resolve it
+func (h *BookingHandler) userLocation(ctx context.Context, userID string) *time.Location {
+	resp, err := h.UserClient.GetUserProfileByID(ctx, &userprofile.GetUserProfileByIDRequest{Id: userID})
+	if err != nil {
+		log.Printf("Failed to fetch time zone of %s, using default: %v", userID, err)
+		return scheduling.LoadLocation("")
 	}
+	return scheduling.LoadLocation(resp.TimeZone)
+}
 
-	var studentIDs []string
-	for _, w := range waitlist {
-		studentIDs = append(studentIDs, w.StudentID)
+// enqueueNotification stores the message in the outbox using the caller's transaction,
+// so it is only published if the surrounding slot update commits.
+func enqueueNotification(tx *gorm.DB, msg clients.NotificationMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
 	}
 
-	resp, err := h.UserClient.GetBatchUserProfiles(context.Background(), &userprofile.GetBatchUserProfilesRequest{
-		Ids: studentIDs,
-	})
+	return tx.Create(&models.OutboxMessage{
+		ID:            uuid.NewString(),
+		Queue:         config.RabbitQueue.Name,
+		Type:          msg.Type,
+		Payload:       string(body),
+		Status:        models.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// enqueueUserEvent stores an event for user-service in the outbox using the caller's transaction
+func enqueueUserEvent(tx *gorm.DB, msg clients.UserEventMessage) error {
+	body, err := json.Marshal(msg)
 	if err != nil {
-		log.Printf("Failed to fetch waitlist users: %v", err)
-		return
+		return err
 	}
 
-	for _, profile := range resp.Profiles {
-		if profile.Email != "" {
-			msg := clients.NotificationMessage{
-				Type:    "waitlist_alert",
-				ToEmail: profile.Email,
-				Data: map[string]string{
-					"psychologist_name": psychName,
-					"date":              dateStr,
-				},
-			}
-			h.RabbitMQ.PublishNotification(msg)
-		}
+	return tx.Create(&models.OutboxMessage{
+		ID:            uuid.NewString(),
+		Queue:         config.UserEventsQueue.Name,
+		Type:          msg.Type,
+		Payload:       string(body),
+		Status:        models.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// bookingLimitReached checks the daily (1) and weekly (2) session limits for a session starting at start.
+// Days and weeks are taken in the student's time zone. It returns the message to show, or "" when the student may book.
+func bookingLimitReached(db *gorm.DB, studentID string, start time.Time, loc *time.Location) (string, error) {
+	localStart := start.In(loc)
+	startOfDay := scheduling.StartOfDay(localStart)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+	startOfWeek, endOfWeek := getWeekRange(localStart)
+
+	// Sessions that already took place or were missed still count toward the limits
+	counted := []string{models.StatusReserved, models.StatusBooked, models.StatusCompleted, models.StatusNoShow}
+
+	var dailyCount int64
+	if err := db.Model(&models.Slot{}).
+		Where("student_id = ? AND status IN ?", studentID, counted).
+		Where("start_time >= ? AND start_time < ?", startOfDay, endOfDay).
+		Count(&dailyCount).Error; err != nil {
+		return "", err
 	}
+	if dailyCount >= 1 {
+		return "You can only book 1 appointment per day.", nil
+	}
+
+	var weeklyCount int64
+	if err := db.Model(&models.Slot{}).
+		Where("student_id = ? AND status IN ?", studentID, counted).
+		Where("start_time >= ? AND start_time < ?", startOfWeek, endOfWeek).
+		Count(&weeklyCount).Error; err != nil {
+		return "", err
+	}
+	if weeklyCount >= 2 {
+		return "You have reached the maximum limit of 2 appointments per week.", nil
+	}
+	return "", nil
+}
+
+// addCalendarEvent adds the fields notification-service turns into an .ics attachment.
+// The sequence must grow with every change of the slot, which the slot version already does.
+func addCalendarEvent(data map[string]string, prefix string, slot models.Slot, sequence int) {
+	data[prefix+"slot_id"] = slot.ID
+	data[prefix+"start_time"] = slot.StartTime.UTC().Format(time.RFC3339)
+	data[prefix+"duration"] = strconv.Itoa(slot.Duration)
+	data[prefix+"sequence"] = strconv.Itoa(sequence)
 }
 
 // Helper function to get the start and end of a week for a given date
-// Assuming Monday is the first day of the week
+// Assuming Monday is the first day of the week. Boundaries are midnights in date's location.
 func getWeekRange(date time.Time) (time.Time, time.Time) {
 	// Find how many days we are past Monday (0 = Sunday, 1 = Monday, etc.)
 	weekday := int(date.Weekday())
@@ -79,7 +177,7 @@ func getWeekRange(date time.Time) (time.Time, time.Time) {
 	}
 
 	// Subtract days to get to Monday 00:00:00
-	startOfWeek := date.AddDate(0, 0, -weekday+1).Truncate(24 * time.Hour)
+	startOfWeek := scheduling.StartOfDay(date.AddDate(0, 0, -weekday+1))
 
 	// Add 7 days to get to next Monday 00:00:00 (which is the exclusive end of this week)
 	endOfWeek := startOfWeek.AddDate(0, 0, 7)
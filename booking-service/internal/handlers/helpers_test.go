@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// MockUserClient mocks the gRPC client; calls the handlers under test do not make fall through to the nil interface
+type MockUserClient struct {
+	userprofile.UserProfileServiceClient
+	mock.Mock
+}
+
+func (m *MockUserClient) GetBatchUserProfiles(ctx context.Context, in *userprofile.GetBatchUserProfilesRequest, opts ...grpc.CallOption) (*userprofile.GetBatchUserProfilesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetBatchUserProfilesResponse), args.Error(1)
+}
+
+func (m *MockUserClient) GetUserProfileByID(ctx context.Context, in *userprofile.GetUserProfileByIDRequest, opts ...grpc.CallOption) (*userprofile.GetUserProfileByIDResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetUserProfileByIDResponse), args.Error(1)
+}
+
+func (m *MockUserClient) GetConsentedMoodLogs(ctx context.Context, in *userprofile.GetConsentedMoodLogsRequest, opts ...grpc.CallOption) (*userprofile.GetConsentedMoodLogsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetConsentedMoodLogsResponse), args.Error(1)
+}
+
+func cancelAppointment(h *BookingHandler, slotID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/student/slots/:id/cancel", h.CancelAppointment)
+
+	req := httptest.NewRequest(http.MethodPost, "/student/slots/"+slotID+"/cancel", nil)
+	req.Header.Set("X-User-ID", "student-1")
+	req.Header.Set("X-User-Role", "student")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCancelAppointmentFailsWithoutContacts(t *testing.T) {
+	setupTestDB(t)
+	student := "student-1"
+	createSlot(t, "slot-1", models.StatusBooked, &student, time.Now().Add(24*time.Hour))
+
+	client := new(MockUserClient)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused")).Once()
+	// The user service answers, but without the student
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: []*userprofile.BasicUserProfile{
+			{Id: "psych-1", FullName: "Dr. Smith"},
+		}}, nil).Once()
+	h := &BookingHandler{UserClient: client}
+
+	for range 2 {
+		w := cancelAppointment(h, "slot-1")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+
+	var slot models.Slot
+	config.DB.First(&slot, "id = ?", "slot-1")
+	assert.Equal(t, models.StatusBooked, slot.Status)
+	var queued int64
+	config.DB.Model(&models.OutboxMessage{}).Count(&queued)
+	assert.Zero(t, queued)
+}
+
+func TestCancelAppointmentQueuesNotification(t *testing.T) {
+	setupTestDB(t)
+	student := "student-1"
+	createSlot(t, "slot-1", models.StatusBooked, &student, time.Now().Add(24*time.Hour))
+
+	client := new(MockUserClient)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: []*userprofile.BasicUserProfile{
+			{Id: "student-1", Email: "student@test.com"},
+			{Id: "psych-1", FullName: "Dr. Smith"},
+		}}, nil)
+	client.On("GetUserProfileByID", mock.Anything, mock.Anything).
+		Return(&userprofile.GetUserProfileByIDResponse{Id: "psych-1", FullName: "Dr. Smith"}, nil)
+	h := &BookingHandler{UserClient: client}
+
+	w := cancelAppointment(h, "slot-1")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var slot models.Slot
+	config.DB.First(&slot, "id = ?", "slot-1")
+	assert.Equal(t, models.StatusAvailable, slot.Status)
+	assert.Nil(t, slot.StudentID)
+
+	var queued []models.OutboxMessage
+	config.DB.Find(&queued)
+	require.Len(t, queued, 1)
+	assert.Contains(t, queued[0].Payload, "booking_cancellation")
+	assert.Contains(t, queued[0].Payload, "student@test.com")
+}
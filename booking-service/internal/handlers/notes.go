@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+)
+
+// revealNotes decrypts the given clinical fields of the slots in place and writes one access log
+// row per slot that had any of them. If the log cannot be written, the caller must not respond
+// with the decrypted data.
+func revealNotes(c *gin.Context, slots []models.Slot, fields ...string) error {
+	now := time.Now()
+	var entries []models.NoteAccessLog
+
+	for i := range slots {
+		var read []string
+		for _, field := range fields {
+			value := notecrypt.FieldOf(&slots[i], field)
+			if *value == "" {
+				continue
+			}
+			text, err := notecrypt.Open(field, *value)
+			if err != nil {
+				return err
+			}
+			*value = text
+			read = append(read, field)
+		}
+
+		if len(read) == 0 || slots[i].StudentID == nil {
+			continue
+		}
+		entries = append(entries, models.NoteAccessLog{
+			ID:         uuid.NewString(),
+			ReaderID:   c.GetHeader("X-User-ID"),
+			ReaderRole: c.GetHeader("X-User-Role"),
+			StudentID:  *slots[i].StudentID,
+			SlotID:     slots[i].ID,
+			Fields:     strings.Join(read, ","),
+			Endpoint:   c.FullPath(),
+			AccessedAt: now,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	return config.DB.Create(&entries).Error
+}
+
+// RotateNoteKey godoc
+// @Summary      Admin: Re-encrypt clinical notes with the active key
+// @Description  Re-encrypts session notes, questionnaire answers and recommendations that are not sealed with the active master key, including plain text stored before encryption. Safe to run repeatedly.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.NoteKeyRotationResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /admin/notes/rotate-key [post]
+func (h *BookingHandler) RotateNoteKey(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	count, err := notecrypt.Rotate(config.DB)
+	if err != nil {
+		log.Printf("Note key rotation stopped after %d values: %v", count, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Re-encryption failed, see service logs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NoteKeyRotationResponse{
+		ActiveKeyID: notecrypt.ActiveKeyID(),
+		Reencrypted: count,
+	})
+}
+
+// GetNoteAccessLog godoc
+// @Summary      Admin: Who read clinical notes
+// @Description  Returns the append-only log of decrypted note reads, newest first, optionally filtered by student or reader.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        student_id query string false "Only reads of this student's data"
+// @Param        reader_id  query string false "Only reads by this user"
+// @Param        limit      query int    false "Max entries (default 100, max 500)"
+// @Success      200 {array}  models.NoteAccessLog
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /admin/notes/access-log [get]
+func (h *BookingHandler) GetNoteAccessLog(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	query := config.DB.Order("accessed_at desc").Limit(limit)
+	if studentID := c.Query("student_id"); studentID != "" {
+		query = query.Where("student_id = ?", studentID)
+	}
+	if readerID := c.Query("reader_id"); readerID != "" {
+		query = query.Where("reader_id = ?", readerID)
+	}
+
+	entries := []models.NoteAccessLog{}
+	if err := query.Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
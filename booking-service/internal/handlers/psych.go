@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/questionnaire"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
 	"gorm.io/gorm/clause"
 )
@@ -28,7 +30,7 @@ import (
 // @Failure      403  {object}  models.ErrorResponse              "only psychologists can create slots"
 // @Failure      500  {object}  models.ErrorResponse              "database error"
 // @Router       /psychologist/slots [post]
-func CreateSlot(c *gin.Context) {
+func (h *BookingHandler) CreateSlot(c *gin.Context) {
 	psychologistID := c.GetHeader("X-User-ID")
 	role := c.GetHeader("X-User-Role")
 
@@ -47,10 +49,6 @@ func CreateSlot(c *gin.Context) {
 		input.Duration = 50
 	}
 
-	scheduleMap := make(map[int][]string)
-	for _, day := range input.Schedule {
-		scheduleMap[day.DayOfWeek] = day.StartTimes
-	}
 	currentDate, err := parseDate(input.StartDate)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -66,38 +64,15 @@ func CreateSlot(c *gin.Context) {
 		return
 	}
 
-	var slotsToCreate []models.Slot
-
-	for !currentDate.After(endDate) {
-
-		// Get the weekday of the current iteration (0=Sun, 1=Mon...)
-		currentWeekday := int(currentDate.Weekday())
-
-		// Check if we have times defined for this weekday
-		if times, exists := scheduleMap[currentWeekday]; exists {
-
-			// Loop through the specific times (09:00, 10:00, etc.)
-			for _, timeStr := range times {
-
-				// Combine Date + Time
-				slotTime, err := combineDateAndTime(currentDate, timeStr)
-				if err != nil {
-					continue // Skip invalid time formats
-				}
-
-				slotsToCreate = append(slotsToCreate, models.Slot{
-					ID:             uuid.NewString(),
-					PsychologistID: psychologistID,
-					StartTime:      slotTime,
-					Duration:       input.Duration,
-					Status:         models.StatusAvailable,
-					Version:        1,
-				})
-			}
-		}
-
-		// Move to next day
-		currentDate = currentDate.AddDate(0, 0, 1)
+	// Start times are wall-clock times in the psychologist's time zone
+	loc := h.userLocation(c.Request.Context(), psychologistID)
+	slotsToCreate := scheduling.GenerateSlots(psychologistID, currentDate, endDate, input.Schedule, input.Duration, nil, loc)
+	slotsToCreate, err = scheduling.ExcludeTimeOff(config.DB, psychologistID, slotsToCreate, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create slots",
+		})
+		return
 	}
 
 	if len(slotsToCreate) == 0 {
@@ -150,10 +125,12 @@ func (h *BookingHandler) GetMySchedule(c *gin.Context) {
 	var startTime time.Time
 	var endTime time.Time
 
+	// Days are the psychologist's own calendar days
+	loc := h.userLocation(c.Request.Context(), psychID)
 	if dateStr != "" {
-		startTime, _ = parseDate(dateStr)
+		startTime, _ = time.ParseInLocation("2006-01-02", dateStr, loc)
 	} else {
-		startTime = time.Now().Truncate(24 * time.Hour) // Default to today
+		startTime = scheduling.StartOfDay(time.Now().In(loc)) // Default to today
 	}
 
 	switch period {
@@ -175,6 +152,12 @@ func (h *BookingHandler) GetMySchedule(c *gin.Context) {
 		return
 	}
 
+	if err := revealNotes(c, slots, notecrypt.FieldAnswers); err != nil {
+		log.Printf("Failed to reveal notes: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read session data"})
+		return
+	}
+
 	var studentIDs []string
 	for _, s := range slots {
 		if s.Status != models.StatusAvailable && s.StudentID != nil {
@@ -198,6 +181,7 @@ func (h *BookingHandler) GetMySchedule(c *gin.Context) {
 
 	var response []models.PsychologistScheduleResponse
 	for _, s := range slots {
+		results, legacy := questionnaire.Decode(s.QuestionnaireAnswers)
 		studentName := ""
 		if s.StudentID != nil {
 			if name, ok := studentMap[*s.StudentID]; ok {
@@ -216,7 +200,9 @@ func (h *BookingHandler) GetMySchedule(c *gin.Context) {
 			PsychologistID:       s.PsychologistID,
 			StudentID:            s.StudentID,
 			StudentName:          studentName,
-			QuestionnaireAnswers: s.QuestionnaireAnswers,
+			Questionnaires:       results,
+			Scores:               questionnaire.Scores(results),
+			QuestionnaireAnswers: legacy,
 			PhoneNumber:          s.PhoneNumber,
 		})
 	}
@@ -287,60 +273,9 @@ func (h *BookingHandler) DeleteSlot(c *gin.Context) {
 	})
 }
 
-// AddSessionNote godoc
-// @Summary      Add private notes to a session
-// @Description  Psychologist writes private notes after a session. Hidden from the student.
-// @Tags         psychologist-slots
-// @Accept       json
-// @Produce      json
-// @Security     BearerAuth
-// @Param        id      path   string        true  "Slot ID"
-// @Param        request body   models.AddNoteInput  true  "The private notes"
-// @Success      200 {object} models.MessageResponse
-// @Router       /psychologist/slots/{id}/notes [put]
-func (h *BookingHandler) AddSessionNote(c *gin.Context) {
-	slotID := c.Param("id")
-	psychID := c.GetHeader("X-User-ID")
-	role := c.GetHeader("X-User-Role")
-
-	if role != "psychologist" {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can add notes"})
-		return
-	}
-
-	var input models.AddNoteInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
-		return
-	}
-
-	var slot models.Slot
-	if err := config.DB.First(&slot, "id = ?", slotID).Error; err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Slot not found"})
-		return
-	}
-
-	if slot.PsychologistID != psychID {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only add notes to your own sessions"})
-		return
-	}
-	if slot.Status != models.StatusBooked {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Cannot add notes to an unbooked slot"})
-		return
-	}
-
-	slot.PsychologistNotes = input.Notes
-	if err := config.DB.Save(&slot).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save notes"})
-		return
-	}
-
-	c.JSON(http.StatusOK, models.MessageResponse{Message: "Notes saved successfully"})
-}
-
 // GetStudentHistory godoc
 // @Summary      Get a student's session history
-// @Description  Psychologist views all past sessions and private notes for a specific student.
+// @Description  Psychologist views all past sessions for a specific student with the latest signed revision of each session note. Earlier revisions are available from the session note endpoints. Every note read is recorded in the access log.
 // @Tags         psychologist-slots
 // @Produce      json
 // @Security     BearerAuth
@@ -360,22 +295,81 @@ func (h *BookingHandler) GetStudentHistory(c *gin.Context) {
 	// Fetch all past bookings between THIS psychologist and THIS student
 	var slots []models.Slot
 	if err := config.DB.
-		Where("psychologist_id = ? AND student_id = ? AND status = ?", psychID, studentID, models.StatusBooked).
+		Where("psychologist_id = ? AND student_id = ? AND status IN ?", psychID, studentID,
+			[]string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow}).
 		Order("start_time desc").
 		Find(&slots).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
 		return
 	}
 
+	if err := revealNotes(c, slots, notecrypt.FieldNotes, notecrypt.FieldAnswers); err != nil {
+		log.Printf("Failed to reveal notes: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read session data"})
+		return
+	}
+
+	slotIDs := make([]string, 0, len(slots))
+	for _, s := range slots {
+		slotIDs = append(slotIDs, s.ID)
+	}
+
+	// Structured notes of these sessions, and the revision each was signed at
+	var notes []models.SessionNote
+	var signed []models.SessionNoteRevision
+	if len(slotIDs) > 0 {
+		if err := config.DB.Where("slot_id IN ?", slotIDs).Find(&notes).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+		if err := config.DB.
+			Joins("JOIN session_notes ON session_notes.id = session_note_revisions.note_id AND session_notes.signed_revision = session_note_revisions.revision").
+			Where("session_notes.slot_id IN ?", slotIDs).
+			Find(&signed).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+	}
+
+	notesByID := make(map[string]models.SessionNote, len(notes))
+	notesBySlot := make(map[string]models.SessionNote, len(notes))
+	for _, n := range notes {
+		notesByID[n.ID] = n
+		notesBySlot[n.SlotID] = n
+	}
+
+	if err := revealRevisions(c, notesByID, signed); err != nil {
+		log.Printf("Failed to reveal note revisions: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read session data"})
+		return
+	}
+	signedBySlot := make(map[string]models.SessionNoteRevision, len(signed))
+	for _, r := range signed {
+		signedBySlot[notesByID[r.NoteID].SlotID] = r
+	}
+
 	var history []models.StudentHistoryResponse
 	for _, s := range slots {
-		history = append(history, models.StudentHistoryResponse{
+		results, legacy := questionnaire.Decode(s.QuestionnaireAnswers)
+		entry := models.StudentHistoryResponse{
 			SlotID:               s.ID,
 			StartTime:            s.StartTime,
 			BookingType:          s.BookingType,
-			QuestionnaireAnswers: s.QuestionnaireAnswers,
+			Status:               s.Status,
+			Questionnaires:       results,
+			Scores:               questionnaire.Scores(results),
+			QuestionnaireAnswers: legacy,
 			PsychologistNotes:    s.PsychologistNotes,
-		})
+		}
+		if note, ok := notesBySlot[s.ID]; ok {
+			entry.NoteRevisions = note.LatestRevision
+			entry.HasUnsignedDraft = note.SignedRevision == nil
+			if rev, ok := signedBySlot[s.ID]; ok {
+				response := revisionResponse(note, rev)
+				entry.Note = &response
+			}
+		}
+		history = append(history, entry)
 	}
 
 	if len(history) == 0 {
@@ -397,6 +391,7 @@ func (h *BookingHandler) GetStudentHistory(c *gin.Context) {
 // @Failure      403  {object}  models.ErrorResponse "Not authorized"
 // @Failure      404  {object}  models.ErrorResponse "Slot not found"
 // @Failure      409  {object}  models.ErrorResponse "Slot is not booked"
+// @Failure      503  {object}  models.ErrorResponse "User service unavailable, nothing was changed"
 // @Router       /psychologist/slots/{id}/cancel [post]
 func (h *BookingHandler) CancelBookingByPsychologist(c *gin.Context) {
 	slotID := c.Param("id")
@@ -425,53 +420,50 @@ func (h *BookingHandler) CancelBookingByPsychologist(c *gin.Context) {
 	}
 
 	studentID := *slot.StudentID
+	contacts, err := h.lookupStudentAndPsych(c.Request.Context(), studentID, psychID)
+	if err != nil {
+		respondContactsUnavailable(c)
+		return
+	}
+
+	tx := config.DB.Begin()
 
 	// Atomically free the slot
-	result := config.DB.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", slot.ID, slot.Version).
-		Updates(map[string]interface{}{
-			"status":                models.StatusAvailable,
+	result := models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		models.StatusBooked, models.StatusAvailable, map[string]interface{}{
 			"student_id":            nil,
 			"booking_type":          "",
 			"questionnaire_answers": "",
 			"version":               slot.Version + 1,
 		})
 
-	if result.RowsAffected == 0 {
+	if result.Error != nil || result.RowsAffected == 0 {
+		tx.Rollback()
 		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Update failed, try again"})
 		return
 	}
 
-	logBookingAction(slot.ID, slot.PsychologistID, *slot.StudentID, "canceled_by_psychologist")
-
-	go func() {
-		resp, err := h.UserClient.GetBatchUserProfiles(context.Background(), &userprofile.GetBatchUserProfilesRequest{
-			Ids: []string{studentID, psychID},
-		})
+	msg := clients.NotificationMessage{
+		Type:    "booking_cancellation_by_psychologist",
+		ToEmail: contacts.StudentEmail,
+		Data: map[string]string{
+			"psychologist_name": contacts.PsychName,
+			"datetime":          slot.StartTime.In(contacts.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04"),
+		},
+	}
+	addCalendarEvent(msg.Data, "", slot, slot.Version+1)
+	if err := enqueueNotification(tx, msg); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
 
-		var studentEmail, psychName string
-		if err == nil {
-			for _, p := range resp.Profiles {
-				if p.Id == studentID {
-					studentEmail = p.Email
-				} else if p.Id == psychID {
-					psychName = p.FullName
-				}
-			}
-		}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
 
-		if studentEmail != "" {
-			msg := clients.NotificationMessage{
-				Type:    "booking_cancellation_by_psychologist",
-				ToEmail: studentEmail,
-				Data: map[string]string{
-					"psychologist_name": psychName,
-					"datetime":          slot.StartTime.Format("Monday, 02 Jan 2006 at 15:04"),
-				},
-			}
-			h.RabbitMQ.PublishNotification(msg)
-		}
-	}()
+	logBookingAction(slot.ID, slot.PsychologistID, studentID, "canceled_by_psychologist")
 
 	c.JSON(http.StatusOK, models.MessageResponse{Message: "Appointment canceled and student notified"})
 }
@@ -510,12 +502,20 @@ func (h *BookingHandler) AddRecommendation(c *gin.Context) {
 		return
 	}
 
-	if slot.PsychologistID != psychID || slot.Status != models.StatusBooked || slot.StudentID == nil {
+	if slot.PsychologistID != psychID || slot.StudentID == nil ||
+		(slot.Status != models.StatusBooked && slot.Status != models.StatusCompleted) {
 		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Cannot add recommendations to this slot"})
 		return
 	}
 
-	slot.StudentRecommendations = input.Recommendations
+	sealed, err := notecrypt.Seal(notecrypt.FieldRecommendations, input.Recommendations)
+	if err != nil {
+		log.Printf("Failed to encrypt recommendations: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	slot.StudentRecommendations = sealed
 	if err := config.DB.Save(&slot).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
 		return
@@ -527,10 +527,12 @@ func (h *BookingHandler) AddRecommendation(c *gin.Context) {
 		})
 
 		var studentEmail, psychName string
+		studentLoc := scheduling.LoadLocation("")
 		if err == nil {
 			for _, p := range resp.Profiles {
 				if p.Id == *slot.StudentID {
 					studentEmail = p.Email
+					studentLoc = scheduling.LoadLocation(p.TimeZone)
 				}
 				if p.Id == psychID {
 					psychName = p.FullName
@@ -544,7 +546,7 @@ func (h *BookingHandler) AddRecommendation(c *gin.Context) {
 				ToEmail: studentEmail,
 				Data: map[string]string{
 					"psychologist_name": psychName,
-					"date":              slot.StartTime.Format("02 Jan 2006"),
+					"date":              slot.StartTime.In(studentLoc).Format("02 Jan 2006"),
 				},
 			}
 			h.RabbitMQ.PublishNotification(msg)
@@ -619,14 +621,27 @@ func (h *BookingHandler) GetPsychologistStats(c *gin.Context) {
 
 	// 1. Total Sessions (Completed)
 	config.DB.Model(&models.Slot{}).
-		Where("psychologist_id = ? AND status = ? AND start_time < ?", psychID, models.StatusBooked, now).
+		Where("psychologist_id = ? AND status = ?", psychID, models.StatusCompleted).
 		Count(&stats.TotalSessions)
 
-	// 2. Upcoming Sessions
+	// 2. Upcoming Sessions, and past ones still waiting for attendance to be marked
 	config.DB.Model(&models.Slot{}).
 		Where("psychologist_id = ? AND status = ? AND start_time > ?", psychID, models.StatusBooked, now).
 		Count(&stats.UpcomingSessions)
 
+	config.DB.Model(&models.Slot{}).
+		Where("psychologist_id = ? AND status = ? AND start_time <= ?", psychID, models.StatusBooked, now).
+		Count(&stats.UnmarkedSessions)
+
+	// No-shows out of all sessions with recorded attendance
+	config.DB.Model(&models.Slot{}).
+		Where("psychologist_id = ? AND status = ?", psychID, models.StatusNoShow).
+		Count(&stats.NoShows)
+
+	if attended := stats.TotalSessions + stats.NoShows; attended > 0 {
+		stats.NoShowRate = (float64(stats.NoShows) / float64(attended)) * 100
+	}
+
 	// 3. Average Rating
 	config.DB.Model(&models.Slot{}).
 		Where("psychologist_id = ? AND rating > 0", psychID).
@@ -644,7 +659,7 @@ func (h *BookingHandler) GetPsychologistStats(c *gin.Context) {
 
 	// Count all 'canceled' actions
 	config.DB.Model(&models.BookingLog{}).
-		Where("psychologist_id = ? AND action IN ?", psychID, []string{"canceled_by_student", "canceled_by_psychologist"}).
+		Where("psychologist_id = ? AND action IN ?", psychID, []string{"canceled_by_student", "canceled_by_psychologist", "canceled_by_time_off", models.StatusCanceled}).
 		Count(&totalCancelled)
 
 	if totalBooked > 0 {
@@ -653,14 +668,14 @@ func (h *BookingHandler) GetPsychologistStats(c *gin.Context) {
 
 	// 5. Sessions this month
 	config.DB.Model(&models.Slot{}).
-		Where("psychologist_id = ? AND status = ? AND start_time >= ?", psychID, models.StatusBooked, startOfMonth).
+		Where("psychologist_id = ? AND status IN ? AND start_time >= ?", psychID, []string{models.StatusBooked, models.StatusCompleted}, startOfMonth).
 		Count(&stats.SessionsThisMonth)
 
 	// 6. Most Popular Format
 	var topFormat string
 	config.DB.Model(&models.Slot{}).
 		Select("booking_type").
-		Where("psychologist_id = ? AND status = ?", psychID, models.StatusBooked).
+		Where("psychologist_id = ? AND status IN ?", psychID, []string{models.StatusBooked, models.StatusCompleted}).
 		Group("booking_type").
 		Order("count(*) desc").
 		Limit(1).
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/questionnaire"
+	"gorm.io/gorm/clause"
+)
+
+// GetActiveQuestionnaires godoc
+// @Summary      Questionnaires to fill in when booking
+// @Description  Returns the latest version of every active questionnaire. Each must be answered in the confirm request.
+// @Tags         questionnaires
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.Questionnaire
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /questionnaires [get]
+func (h *BookingHandler) GetActiveQuestionnaires(c *gin.Context) {
+	active, err := questionnaire.Active(config.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, active)
+}
+
+// GetAllQuestionnaires godoc
+// @Summary      Admin: List questionnaire versions
+// @Description  Returns every version of every questionnaire, newest version first within each code.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.Questionnaire
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /admin/questionnaires [get]
+func (h *BookingHandler) GetAllQuestionnaires(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	all := []models.Questionnaire{}
+	if err := config.DB.Order("code asc, version desc").Find(&all).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, all)
+}
+
+// CreateQuestionnaireVersion godoc
+// @Summary      Admin: Publish a questionnaire version
+// @Description  Saves the questions as the next version of the code (version 1 for a new code). Earlier versions stay untouched so existing answers keep their meaning; new bookings use the new version.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.QuestionnaireInput true "Questionnaire definition"
+// @Success      201 {object} models.Questionnaire
+// @Failure      400 {object} models.ErrorResponse "Invalid definition"
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /admin/questionnaires [post]
+func (h *BookingHandler) CreateQuestionnaireVersion(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var input models.QuestionnaireInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	q := models.Questionnaire{
+		ID:        uuid.NewString(),
+		Code:      input.Code,
+		Title:     input.Title,
+		Intro:     input.Intro,
+		Questions: input.Questions,
+		Scored:    input.Scored,
+		Bands:     input.Bands,
+		Active:    true,
+	}
+	if err := questionnaire.Validate(q); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tx := config.DB.Begin()
+
+	// Lock the existing versions so two admins publishing at once do not get the same number
+	var latest models.Questionnaire
+	res := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", q.Code).
+		Order("version desc").
+		Limit(1).
+		Find(&latest)
+	if res.Error != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	q.Version = latest.Version + 1
+
+	if err := tx.Create(&q).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Another version was published at the same time. Please try again."})
+		return
+	}
+
+	// A new version of an inactive code is meant to be asked again
+	if err := tx.Model(&models.Questionnaire{}).Where("code = ?", q.Code).Update("active", true).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, q)
+}
+
+// SetQuestionnaireActive godoc
+// @Summary      Admin: Turn a questionnaire on or off
+// @Description  Inactive questionnaires are no longer asked at booking. Stored answers are kept.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        code    path string                          true "Questionnaire code"
+// @Param        request body models.QuestionnaireActiveInput true "Whether to ask it"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /admin/questionnaires/{code}/active [put]
+func (h *BookingHandler) SetQuestionnaireActive(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var input models.QuestionnaireActiveInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	res := config.DB.Model(&models.Questionnaire{}).Where("code = ?", c.Param("code")).Update("active", *input.Active)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Questionnaire not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Questionnaire updated"})
+}
+
+// evaluateSubmissions validates the student's answers against every active questionnaire.
+// It returns the HTTP status to respond with when the submission is rejected.
+func evaluateSubmissions(submissions []models.QuestionnaireSubmission) ([]models.QuestionnaireResult, int, error) {
+	active, err := questionnaire.Active(config.DB)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database error")
+	}
+
+	byID := make(map[string]models.QuestionnaireSubmission, len(submissions))
+	for _, s := range submissions {
+		byID[s.QuestionnaireID] = s
+	}
+
+	results := make([]models.QuestionnaireResult, 0, len(active))
+	for _, q := range active {
+		submission, ok := byID[q.ID]
+		if !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("please answer the %s questionnaire", q.Title)
+		}
+		delete(byID, q.ID)
+
+		result, err := questionnaire.Evaluate(q, submission.Answers)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		results = append(results, result)
+	}
+
+	// Anything left over is an old version or not asked any more
+	if len(byID) > 0 {
+		return nil, http.StatusConflict, fmt.Errorf("the questionnaires have changed, please reload and answer them again")
+	}
+	return results, 0, nil
+}
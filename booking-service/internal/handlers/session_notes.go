@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddSessionNote godoc
+// @Summary      Save a new revision of a session note
+// @Description  Psychologist writes the structured note for a session. Every save adds an immutable revision; earlier ones are kept. Hidden from the student. Fails once the note is signed.
+// @Tags         psychologist-notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path   string                   true  "Slot ID"
+// @Param        request body   models.SessionNoteInput  true  "Full content of the new revision"
+// @Success      200 {object} models.SessionNoteRevisionSummary
+// @Failure      400 {object} models.ErrorResponse "Invalid input or session not booked"
+// @Failure      403 {object} models.ErrorResponse "Not your session"
+// @Failure      404 {object} models.ErrorResponse "Slot not found"
+// @Failure      409 {object} models.ErrorResponse "Note is signed and locked, or saved by someone else at the same time"
+// @Failure      500 {object} models.ErrorResponse "Database error"
+// @Router       /psychologist/slots/{id}/notes [put]
+func (h *BookingHandler) AddSessionNote(c *gin.Context) {
+	slotID := c.Param("id")
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can add notes"})
+		return
+	}
+
+	var input models.SessionNoteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var slot models.Slot
+	if err := config.DB.First(&slot, "id = ?", slotID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Slot not found"})
+		return
+	}
+
+	if slot.PsychologistID != psychID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only add notes to your own sessions"})
+		return
+	}
+	if slot.StudentID == nil || (slot.Status != models.StatusBooked && slot.Status != models.StatusCompleted) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Notes can only be added to booked or completed sessions"})
+		return
+	}
+
+	rev := models.SessionNoteRevision{
+		ID:        uuid.NewString(),
+		RiskLevel: input.RiskLevel,
+		AuthorID:  psychID,
+	}
+	sections := map[string]string{
+		notecrypt.SectionPresentingConcern: input.PresentingConcern,
+		notecrypt.SectionInterventions:     input.Interventions,
+		notecrypt.SectionPlan:              input.Plan,
+		notecrypt.SectionRiskAssessment:    input.RiskAssessment,
+	}
+	for section, text := range sections {
+		sealed, err := notecrypt.Seal(section, text)
+		if err != nil {
+			log.Printf("Failed to encrypt note section %s: %v", section, err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save notes"})
+			return
+		}
+		*notecrypt.SectionOf(&rev, section) = sealed
+	}
+
+	tx := config.DB.Begin()
+
+	var note models.SessionNote
+	created := false
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("slot_id = ?", slot.ID).First(&note).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		note = models.SessionNote{
+			ID:             uuid.NewString(),
+			SlotID:         slot.ID,
+			PsychologistID: psychID,
+			StudentID:      *slot.StudentID,
+			LatestRevision: 1,
+		}
+		created = true
+		err = tx.Create(&note).Error
+	case err == nil && note.SignedRevision != nil:
+		tx.Rollback()
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "This note is signed and can no longer be edited"})
+		return
+	case err == nil:
+		note.LatestRevision++
+		err = tx.Model(&note).Update("latest_revision", note.LatestRevision).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		// A concurrent first save of the same note hits the unique slot_id
+		if created && config.DB.Where("slot_id = ?", slot.ID).First(&models.SessionNote{}).Error == nil {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The note was changed at the same time. Please try again."})
+			return
+		}
+		log.Printf("Failed to save session note of slot %s: %v", slot.ID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save notes"})
+		return
+	}
+
+	rev.NoteID = note.ID
+	rev.Revision = note.LatestRevision
+	if err := tx.Create(&rev).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save notes"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save notes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SessionNoteRevisionSummary{
+		Revision:  rev.Revision,
+		RiskLevel: rev.RiskLevel,
+		AuthorID:  rev.AuthorID,
+		CreatedAt: rev.CreatedAt,
+	})
+}
+
+// SignSessionNote godoc
+// @Summary      Sign a session note
+// @Description  Psychologist signs the latest revision of the note, which locks it against further edits.
+// @Tags         psychologist-notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path   string                true  "Slot ID"
+// @Param        request body   models.SignNoteInput  true  "Revision being signed, must be the latest"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse "Not your session"
+// @Failure      404 {object} models.ErrorResponse "No note for this session"
+// @Failure      409 {object} models.ErrorResponse "Already signed or not the latest revision"
+// @Router       /psychologist/slots/{id}/notes/sign [post]
+func (h *BookingHandler) SignSessionNote(c *gin.Context) {
+	note, ok := ownSessionNote(c)
+	if !ok {
+		return
+	}
+
+	var input models.SignNoteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if note.SignedRevision != nil {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "This note is already signed"})
+		return
+	}
+	if input.Revision != note.LatestRevision {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "A newer revision exists. Review it before signing."})
+		return
+	}
+
+	// Guarded on the revision, so a save that lands in between makes the signature fail instead of covering unseen text
+	res := config.DB.Model(&models.SessionNote{}).
+		Where("id = ? AND latest_revision = ? AND signed_revision IS NULL", note.ID, input.Revision).
+		Updates(map[string]interface{}{
+			"signed_revision": input.Revision,
+			"signed_at":       time.Now(),
+		})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The note changed while signing. Please review it and try again."})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Note signed and locked"})
+}
+
+// GetSessionNote godoc
+// @Summary      Get the current session note
+// @Description  Returns the latest revision of the note, signed or not. The read is recorded in the note access log.
+// @Tags         psychologist-notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Slot ID"
+// @Success      200 {object} models.SessionNoteResponse
+// @Failure      403 {object} models.ErrorResponse "Not your session"
+// @Failure      404 {object} models.ErrorResponse "No note for this session"
+// @Router       /psychologist/slots/{id}/notes [get]
+func (h *BookingHandler) GetSessionNote(c *gin.Context) {
+	note, ok := ownSessionNote(c)
+	if !ok {
+		return
+	}
+	respondWithRevision(c, note, note.LatestRevision)
+}
+
+// GetSessionNoteRevision godoc
+// @Summary      Get an earlier revision of a session note
+// @Description  Returns the content of one revision. The read is recorded in the note access log.
+// @Tags         psychologist-notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path string true "Slot ID"
+// @Param        revision path int    true "Revision number, starting at 1"
+// @Success      200 {object} models.SessionNoteResponse
+// @Failure      403 {object} models.ErrorResponse "Not your session"
+// @Failure      404 {object} models.ErrorResponse "No such revision"
+// @Router       /psychologist/slots/{id}/notes/revisions/{revision} [get]
+func (h *BookingHandler) GetSessionNoteRevision(c *gin.Context) {
+	note, ok := ownSessionNote(c)
+	if !ok {
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 || revision > note.LatestRevision {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Revision not found"})
+		return
+	}
+	respondWithRevision(c, note, revision)
+}
+
+// GetSessionNoteRevisions godoc
+// @Summary      List the revisions of a session note
+// @Description  Returns every revision without its content, newest first.
+// @Tags         psychologist-notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Slot ID"
+// @Success      200 {array}  models.SessionNoteRevisionSummary
+// @Failure      403 {object} models.ErrorResponse "Not your session"
+// @Failure      404 {object} models.ErrorResponse "No note for this session"
+// @Router       /psychologist/slots/{id}/notes/revisions [get]
+func (h *BookingHandler) GetSessionNoteRevisions(c *gin.Context) {
+	note, ok := ownSessionNote(c)
+	if !ok {
+		return
+	}
+
+	var revisions []models.SessionNoteRevision
+	if err := config.DB.
+		Select("revision", "risk_level", "author_id", "created_at").
+		Where("note_id = ?", note.ID).
+		Order("revision desc").
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	response := make([]models.SessionNoteRevisionSummary, 0, len(revisions))
+	for _, r := range revisions {
+		response = append(response, models.SessionNoteRevisionSummary{
+			Revision:  r.Revision,
+			RiskLevel: r.RiskLevel,
+			AuthorID:  r.AuthorID,
+			CreatedAt: r.CreatedAt,
+			Signed:    note.SignedRevision != nil && *note.SignedRevision == r.Revision,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ownSessionNote loads the note of the slot in the path if it belongs to the calling psychologist.
+// It writes the error response itself and returns false when the caller should stop.
+func ownSessionNote(c *gin.Context) (models.SessionNote, bool) {
+	var note models.SessionNote
+
+	if c.GetHeader("X-User-Role") != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can access session notes"})
+		return note, false
+	}
+
+	if err := config.DB.Where("slot_id = ?", c.Param("id")).First(&note).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "No note has been written for this session"})
+		return note, false
+	}
+
+	if note.PsychologistID != c.GetHeader("X-User-ID") {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only access notes of your own sessions"})
+		return note, false
+	}
+	return note, true
+}
+
+// respondWithRevision decrypts one revision, logs the read and returns it with the note
+func respondWithRevision(c *gin.Context, note models.SessionNote, revision int) {
+	var rev models.SessionNoteRevision
+	if err := config.DB.Where("note_id = ? AND revision = ?", note.ID, revision).First(&rev).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Revision not found"})
+		return
+	}
+
+	revisions := []models.SessionNoteRevision{rev}
+	if err := revealRevisions(c, map[string]models.SessionNote{note.ID: note}, revisions); err != nil {
+		log.Printf("Failed to reveal note revision: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read session note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SessionNoteResponse{
+		ID:             note.ID,
+		SlotID:         note.SlotID,
+		StudentID:      note.StudentID,
+		LatestRevision: note.LatestRevision,
+		SignedRevision: note.SignedRevision,
+		SignedAt:       note.SignedAt,
+		Revision:       revisionResponse(note, revisions[0]),
+	})
+}
+
+// revealRevisions decrypts the sections of the revisions in place and logs one read per revision.
+// notes must contain the note of every revision, keyed by note ID.
+func revealRevisions(c *gin.Context, notes map[string]models.SessionNote, revisions []models.SessionNoteRevision) error {
+	now := time.Now()
+	entries := make([]models.NoteAccessLog, 0, len(revisions))
+
+	for i := range revisions {
+		for _, section := range notecrypt.Sections {
+			value := notecrypt.SectionOf(&revisions[i], section)
+			text, err := notecrypt.Open(section, *value)
+			if err != nil {
+				return err
+			}
+			*value = text
+		}
+
+		note := notes[revisions[i].NoteID]
+		entries = append(entries, models.NoteAccessLog{
+			ID:         uuid.NewString(),
+			ReaderID:   c.GetHeader("X-User-ID"),
+			ReaderRole: c.GetHeader("X-User-Role"),
+			StudentID:  note.StudentID,
+			SlotID:     note.SlotID,
+			Fields:     strings.Join(notecrypt.Sections, ","),
+			Revision:   revisions[i].Revision,
+			Endpoint:   c.FullPath(),
+			AccessedAt: now,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	return config.DB.Create(&entries).Error
+}
+
+func revisionResponse(note models.SessionNote, rev models.SessionNoteRevision) models.SessionNoteRevisionResponse {
+	return models.SessionNoteRevisionResponse{
+		Revision:          rev.Revision,
+		PresentingConcern: rev.PresentingConcern,
+		Interventions:     rev.Interventions,
+		Plan:              rev.Plan,
+		RiskAssessment:    rev.RiskAssessment,
+		RiskLevel:         rev.RiskLevel,
+		AuthorID:          rev.AuthorID,
+		CreatedAt:         rev.CreatedAt,
+		Signed:            note.SignedRevision != nil && *note.SignedRevision == rev.Revision,
+	}
+}
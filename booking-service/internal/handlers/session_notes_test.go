@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTestKeys gives notecrypt a generated key; the first test to call it decides the key for the run
+func loadTestKeys(t *testing.T) {
+	t.Helper()
+	t.Setenv("NOTES_KEYS_DIR", t.TempDir())
+	require.NoError(t, notecrypt.LoadKeys())
+}
+
+func notesRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := &BookingHandler{}
+	r := gin.New()
+	r.PUT("/psychologist/slots/:id/notes", h.AddSessionNote)
+	r.GET("/psychologist/slots/:id/notes", h.GetSessionNote)
+	r.POST("/psychologist/slots/:id/notes/sign", h.SignSessionNote)
+	r.GET("/psychologist/slots/:id/notes/revisions", h.GetSessionNoteRevisions)
+	r.GET("/psychologist/slots/:id/notes/revisions/:revision", h.GetSessionNoteRevision)
+	return r
+}
+
+func psychRequest(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "psych-1")
+	req.Header.Set("X-User-Role", "psychologist")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSessionNoteRevisionsAndSigning(t *testing.T) {
+	setupTestDB(t)
+	loadTestKeys(t)
+	student := "student-1"
+	createSlot(t, "slot-1", models.StatusBooked, &student, time.Now().Add(-time.Hour))
+	r := notesRouter()
+	notes := "/psychologist/slots/slot-1/notes"
+
+	w := psychRequest(r, http.MethodPut, notes, `{"plan":"Sleep diary","risk_level":"none"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = psychRequest(r, http.MethodPut, notes, `{"plan":"Sleep diary, weekly follow-up","risk_level":"low"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var summary models.SessionNoteRevisionSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, 2, summary.Revision)
+
+	var note models.SessionNote
+	require.NoError(t, config.DB.First(&note, "slot_id = ?", "slot-1").Error)
+	assert.Equal(t, 2, note.LatestRevision)
+	var revisions []models.SessionNoteRevision
+	config.DB.Order("revision asc").Find(&revisions, "note_id = ?", note.ID)
+	require.Len(t, revisions, 2)
+	assert.NotContains(t, revisions[0].Plan, "Sleep diary", "sections are stored sealed")
+
+	// Signing needs the latest revision
+	assert.Equal(t, http.StatusConflict, psychRequest(r, http.MethodPost, notes+"/sign", `{"revision":1}`).Code)
+	assert.Equal(t, http.StatusOK, psychRequest(r, http.MethodPost, notes+"/sign", `{"revision":2}`).Code)
+	assert.Equal(t, http.StatusConflict, psychRequest(r, http.MethodPost, notes+"/sign", `{"revision":2}`).Code)
+
+	// A signed note is locked
+	w = psychRequest(r, http.MethodPut, notes, `{"plan":"Changed after signing","risk_level":"none"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var count int64
+	config.DB.Model(&models.SessionNoteRevision{}).Where("note_id = ?", note.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	var current models.SessionNoteResponse
+	w = psychRequest(r, http.MethodGet, notes, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+	assert.Equal(t, "Sleep diary, weekly follow-up", current.Revision.Plan)
+	assert.True(t, current.Revision.Signed)
+
+	var first models.SessionNoteResponse
+	w = psychRequest(r, http.MethodGet, notes+"/revisions/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, "Sleep diary", first.Revision.Plan)
+	assert.False(t, first.Revision.Signed)
+
+	assert.Equal(t, http.StatusNotFound, psychRequest(r, http.MethodGet, notes+"/revisions/3", "").Code)
+}
+
+func TestSessionNoteReadsAreLogged(t *testing.T) {
+	setupTestDB(t)
+	loadTestKeys(t)
+	student := "student-1"
+	createSlot(t, "slot-1", models.StatusBooked, &student, time.Now().Add(-time.Hour))
+	r := notesRouter()
+	notes := "/psychologist/slots/slot-1/notes"
+
+	require.Equal(t, http.StatusOK, psychRequest(r, http.MethodPut, notes, `{"plan":"one","risk_level":"none"}`).Code)
+	require.Equal(t, http.StatusOK, psychRequest(r, http.MethodPut, notes, `{"plan":"two","risk_level":"none"}`).Code)
+
+	// Saving and listing revisions reveal no content
+	require.Equal(t, http.StatusOK, psychRequest(r, http.MethodGet, notes+"/revisions", "").Code)
+	var logged int64
+	config.DB.Model(&models.NoteAccessLog{}).Count(&logged)
+	assert.Zero(t, logged)
+
+	require.Equal(t, http.StatusOK, psychRequest(r, http.MethodGet, notes, "").Code)
+	require.Equal(t, http.StatusOK, psychRequest(r, http.MethodGet, notes+"/revisions/1", "").Code)
+
+	var entries []models.NoteAccessLog
+	config.DB.Order("revision desc").Find(&entries)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].Revision)
+	assert.Equal(t, "/psychologist/slots/:id/notes", entries[0].Endpoint)
+	assert.Equal(t, 1, entries[1].Revision)
+	assert.Equal(t, "/psychologist/slots/:id/notes/revisions/:revision", entries[1].Endpoint)
+	for _, e := range entries {
+		assert.Equal(t, "psych-1", e.ReaderID)
+		assert.Equal(t, "psychologist", e.ReaderRole)
+		assert.Equal(t, "student-1", e.StudentID)
+		assert.Equal(t, "slot-1", e.SlotID)
+		assert.Equal(t, strings.Join(notecrypt.Sections, ","), e.Fields)
+	}
+
+	// Another psychologist is refused before anything is read
+	req := httptest.NewRequest(http.MethodGet, notes, nil)
+	req.Header.Set("X-User-ID", "psych-2")
+	req.Header.Set("X-User-Role", "psychologist")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	config.DB.Model(&models.NoteAccessLog{}).Count(&logged)
+	assert.Equal(t, int64(2), logged)
+}
+
+func TestAddSessionNoteDatabaseErrorIsNotAConflict(t *testing.T) {
+	setupTestDB(t)
+	loadTestKeys(t)
+	student := "student-1"
+	createSlot(t, "slot-1", models.StatusBooked, &student, time.Now().Add(-time.Hour))
+	require.NoError(t, config.DB.Migrator().DropTable(&models.SessionNote{}))
+
+	w := psychRequest(notesRouter(), http.MethodPut, "/psychologist/slots/slot-1/notes", `{"plan":"one","risk_level":"none"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -2,15 +2,19 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/notecrypt"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/questionnaire"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/risk"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
 )
 
@@ -40,17 +44,35 @@ func (h *BookingHandler) GetCalendarAvailability(c *gin.Context) {
 		return
 	}
 
-	startDate := fmt.Sprintf("%s-%s-01", year, month)
-	var availableDays []string
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	if errY != nil || errM != nil || m < 1 || m > 12 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid year or month",
+		})
+		return
+	}
+
+	// Days are grouped in the psychologist's time zone, the same one their schedule is defined in
+	loc := h.userLocation(c.Request.Context(), psychID)
+	monthStart := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, loc)
+	monthEnd := monthStart.AddDate(0, 1, 0)
 
-	// Postgres Query to find days with at least 1 free slot
+	var startTimes []time.Time
 	config.DB.Model(&models.Slot{}).
-		Select("TO_CHAR(start_time, 'YYYY-MM-DD')").
 		Where("psychologist_id = ?", psychID).
 		Where("status = ?", models.StatusAvailable).
-		Where("start_time >= ? AND start_time < (?::date + '1 month'::interval)", startDate, startDate).
-		Group("TO_CHAR(start_time, 'YYYY-MM-DD')").
-		Find(&availableDays)
+		Where("start_time >= ? AND start_time < ?", monthStart, monthEnd).
+		Order("start_time asc").
+		Pluck("start_time", &startTimes)
+
+	availableDays := []string{}
+	for _, t := range startTimes {
+		day := t.In(loc).Format("2006-01-02")
+		if len(availableDays) == 0 || availableDays[len(availableDays)-1] != day {
+			availableDays = append(availableDays, day)
+		}
+	}
 
 	c.JSON(http.StatusOK, models.CalendarAvailabilityResponse{
 		AvailableDates: availableDays,
@@ -82,8 +104,14 @@ func (h *BookingHandler) GetAvailableSlots(c *gin.Context) {
 		return
 	}
 
-	date, _ := parseDate(dateStr)
-	nextDay := date.Add(24 * time.Hour)
+	date, err := time.ParseInLocation("2006-01-02", dateStr, h.userLocation(c.Request.Context(), psychID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid date",
+		})
+		return
+	}
+	nextDay := date.AddDate(0, 0, 1)
 
 	var slots []models.Slot
 	if err := config.DB.
@@ -137,6 +165,7 @@ func (h *BookingHandler) GetAvailableSlots(c *gin.Context) {
 // @Param        request  body   models.BookSlotInput  true  "Booking details: type and answers"
 // @Success 200 {object} models.MessageResponse
 // @Failure      400  {object}  models.ErrorResponse "invalid request body"
+// @Failure      403  {object}  models.ErrorResponse "booking limit reached or restricted after repeated no-shows"
 // @Failure      404  {object}  models.ErrorResponse "slot not found"
 // @Failure      409  {object}  models.ErrorResponse "slot already booked or just booked"
 // @Failure      500  {object}  models.ErrorResponse "database error"
@@ -160,46 +189,34 @@ func (h *BookingHandler) ReserveSlot(c *gin.Context) {
 		return
 	}
 
-	// Calculate boundaries for the requested slot's date
-	startOfDay := slot.StartTime.Truncate(24 * time.Hour)
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	startOfWeek, endOfWeek := getWeekRange(slot.StartTime)
-
-	var dailyCount, weeklyCount int64
-
-	// Check Daily Limit (Max 1 per day)
-	config.DB.Model(&models.Slot{}).
-		Where("student_id = ? AND status IN ?", studentID, []string{models.StatusReserved, models.StatusBooked}).
-		Where("start_time >= ? AND start_time < ?", startOfDay, endOfDay).
-		Count(&dailyCount)
-
-	if dailyCount >= 1 {
+	restricted, err := noShowRestricted(config.DB, studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if restricted {
 		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Error: "You can only book 1 appointment per day.",
+			Error: "Booking is temporarily unavailable because of repeated missed sessions. Please contact the psychological service.",
 		})
 		return
 	}
 
-	// Check Weekly Limit (Max 2 per week)
-	config.DB.Model(&models.Slot{}).
-		Where("student_id = ? AND status IN ?", studentID, []string{models.StatusReserved, models.StatusBooked}).
-		Where("start_time >= ? AND start_time < ?", startOfWeek, endOfWeek).
-		Count(&weeklyCount)
-
-	if weeklyCount >= 2 {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{
-			Error: "You have reached the maximum limit of 2 appointments per week.",
-		})
+	// Daily and weekly limits are counted in the student's time zone
+	limit, err := bookingLimitReached(config.DB, studentID, slot.StartTime, h.userLocation(c.Request.Context(), studentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if limit != "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: limit})
 		return
 	}
 
 	now := time.Now()
 
 	// Optimistic Update to Reserved
-	res := config.DB.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", slot.ID, slot.Version).
-		Updates(map[string]interface{}{
-			"status":      models.StatusReserved,
+	res := models.TransitionSlot(config.DB.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		models.StatusAvailable, models.StatusReserved, map[string]interface{}{
 			"student_id":  studentID,
 			"reserved_at": now,
 			"version":     slot.Version + 1,
@@ -219,19 +236,20 @@ func (h *BookingHandler) ReserveSlot(c *gin.Context) {
 
 // ConfirmSlot godoc
 // @Summary      Confirm a booked appointment
-// @Description  Finalizes the reservation by submitting the questionnaire and student's phone number. Requires a previous 'reserve' action.
+// @Description  Finalizes the reservation by submitting the answers to every active questionnaire (see GET /questionnaires) and the student's phone number. Requires a previous 'reserve' action. Clients that predate questionnaires may send free-text "answers" instead while no questionnaire is active.
 // @Tags         student-booking
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id      path   string               true  "Slot ID (Must be currently 'reserved')"
-// @Param        request body   models.BookSlotInput true  "Booking details (type, phone, answers)"
+// @Param        request body   models.BookSlotInput true  "Booking details (type, phone, questionnaires)"
 // @Success      200 {object}   models.MessageResponse
-// @Failure      400 {object}   models.ErrorResponse "Invalid request body"
+// @Failure      400 {object}   models.ErrorResponse "Invalid request body or questionnaire answers"
 // @Failure      403 {object}   models.ErrorResponse "Not authorized or no active reservation"
 // @Failure      404 {object}   models.ErrorResponse "Slot not found"
-// @Failure      409 {object}   models.ErrorResponse "Reservation expired or conflict"
+// @Failure      409 {object}   models.ErrorResponse "Reservation expired, conflict or outdated questionnaire version"
 // @Failure      500 {object}   models.ErrorResponse "Database or gRPC error"
+// @Failure      503 {object}   models.ErrorResponse "User service unavailable, nothing was changed"
 // @Router       /student/slots/{id}/confirm [post]
 func (h *BookingHandler) ConfirmSlot(c *gin.Context) {
 	slotID := c.Param("id")
@@ -257,59 +275,119 @@ func (h *BookingHandler) ConfirmSlot(c *gin.Context) {
 		return
 	}
 
-	if slot.ReservedAt != nil && time.Since(*slot.ReservedAt) > 15*time.Minute {
+	if slot.HoldExpiresAt != nil {
+		// Held for this student from the waitlist
+		if time.Now().After(*slot.HoldExpiresAt) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error: "Your waitlist hold has expired"})
+			return
+		}
+	} else if slot.ReservedAt != nil && time.Since(*slot.ReservedAt) > 15*time.Minute {
 		c.JSON(http.StatusConflict, models.ErrorResponse{
 			Error: "Your reservation has expired"})
 		return
 	}
 
-	res := config.DB.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", slot.ID, slot.Version).
-		Updates(map[string]interface{}{
-			"status":                models.StatusBooked,
+	results, status, err := evaluateSubmissions(input.Questionnaires)
+	if err != nil {
+		c.JSON(status, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	encoded, err := questionnaire.Encode(results)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if encoded == "" {
+		// Older clients still send free text, stored the way it was before questionnaires
+		encoded = strings.TrimSpace(input.Answers)
+	}
+	riskMatches := risk.Evaluate(results)
+
+	answers, err := notecrypt.Seal(notecrypt.FieldAnswers, encoded)
+	if err != nil {
+		log.Printf("Failed to encrypt questionnaire answers: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	contacts, err := h.lookupStudentAndPsych(c.Request.Context(), studentID, slot.PsychologistID)
+	if err != nil {
+		respondContactsUnavailable(c)
+		return
+	}
+
+	tx := config.DB.Begin()
+
+	res := models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		models.StatusReserved, models.StatusBooked, map[string]interface{}{
+			"hold_expires_at":       nil,
 			"booking_type":          input.BookingType,
-			"questionnaire_answers": input.Answers,
+			"questionnaire_answers": answers,
 			"phone_number":          input.PhoneNumber,
 			"version":               slot.Version + 1,
 		})
 
-	if res.RowsAffected == 0 {
+	if res.Error != nil || res.RowsAffected == 0 {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error: "Failed to confirm booking"})
 		return
 	}
 
-	go func() {
-		grpcResp, err := h.UserClient.GetBatchUserProfiles(context.Background(), &userprofile.GetBatchUserProfilesRequest{
-			Ids: []string{studentID, slot.PsychologistID},
-		})
-
-		var studentEmail, psychName string
-		if err == nil {
-			for _, p := range grpcResp.Profiles {
-				if p.Id == studentID {
-					studentEmail = p.Email
-				} else if p.Id == slot.PsychologistID {
-					psychName = p.FullName
-				}
-			}
+	if slot.HoldExpiresAt != nil {
+		if err := fulfillHold(tx, slot.ID, studentID); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to confirm booking"})
+			return
 		}
+	}
 
-		formattedDate := slot.StartTime.Format("Monday, 02 Jan 2006 at 15:04")
+	msg := clients.NotificationMessage{
+		Type:    "booking_confirmation",
+		ToEmail: contacts.StudentEmail,
+		Data: map[string]string{
+			"psychologist_name": contacts.PsychName,
+			"datetime":          slot.StartTime.In(contacts.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04"),
+			"format":            input.BookingType,
+		},
+	}
+	addCalendarEvent(msg.Data, "", slot, slot.Version+1)
+	if err := enqueueNotification(tx, msg); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to confirm booking"})
+		return
+	}
 
-		msg := clients.NotificationMessage{
-			Type:    "booking_confirmation",
-			ToEmail: studentEmail,
-			Data: map[string]string{
-				"psychologist_name": psychName,
-				"datetime":          formattedDate,
-				"format":            input.BookingType,
-			},
+	// Risky answers are escalated by user-service to the on-duty psychologist
+	for _, m := range riskMatches {
+		event := clients.UserEventMessage{
+			Type:        "risk_signal",
+			StudentID:   studentID,
+			SlotID:      slot.ID,
+			Rule:        m.Rule.Name,
+			Description: m.Rule.Description,
+			Severity:    m.Rule.Severity,
+			Detail:      m.Detail,
+		}
+		if err := enqueueUserEvent(tx, event); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to confirm booking"})
+			return
 		}
+	}
 
-		h.RabbitMQ.PublishNotification(msg)
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to confirm booking"})
+		return
+	}
 
-		_, err = h.UserClient.UpdateUserPhone(context.Background(), &userprofile.UpdateUserPhoneRequest{
+	go func() {
+		_, err := h.UserClient.UpdateUserPhone(context.Background(), &userprofile.UpdateUserPhoneRequest{
 			Id:    studentID,
 			Phone: input.PhoneNumber,
 		})
@@ -345,7 +423,7 @@ func (h *BookingHandler) GetMyAppointments(c *gin.Context) {
 	// Fetch slots from DB booked by this student
 	var slots []models.Slot
 	if err := config.DB.
-		Where("student_id = ? AND status = ?", studentID, models.StatusBooked).
+		Where("student_id = ? AND status IN ?", studentID, []string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow, models.StatusCanceled}).
 		Order("start_time asc").
 		Find(&slots).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -359,6 +437,12 @@ func (h *BookingHandler) GetMyAppointments(c *gin.Context) {
 		return
 	}
 
+	if err := revealNotes(c, slots, notecrypt.FieldAnswers, notecrypt.FieldRecommendations); err != nil {
+		log.Printf("Failed to reveal notes: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read appointment data"})
+		return
+	}
+
 	// Extract unique Psychologist IDs to fetch their names
 	var psychIDs []string
 	uniquePsychs := make(map[string]bool)
@@ -384,6 +468,7 @@ func (h *BookingHandler) GetMyAppointments(c *gin.Context) {
 
 	var response []models.StudentAppointmentResponse
 	for _, s := range slots {
+		results, legacy := questionnaire.Decode(s.QuestionnaireAnswers)
 		psychName := "Unknown Specialist"
 		if name, ok := psychMap[s.PsychologistID]; ok {
 			psychName = name
@@ -396,7 +481,9 @@ func (h *BookingHandler) GetMyAppointments(c *gin.Context) {
 			BookingType:            s.BookingType,
 			PsychologistID:         s.PsychologistID,
 			PsychologistName:       psychName,
-			QuestionnaireAnswers:   s.QuestionnaireAnswers,
+			Status:                 s.Status,
+			Questionnaires:         results,
+			QuestionnaireAnswers:   legacy,
 			StudentRecommendations: s.StudentRecommendations,
 		})
 	}
@@ -417,6 +504,7 @@ func (h *BookingHandler) GetMyAppointments(c *gin.Context) {
 // @Failure      404  {object}  models.ErrorResponse   "Slot not found"
 // @Failure      409  {object}  models.ErrorResponse   "Slot is not booked"
 // @Failure      500  {object}  models.ErrorResponse   "Database error"
+// @Failure      503  {object}  models.ErrorResponse   "User service unavailable, nothing was changed"
 // @Router       /student/slots/{id}/cancel [post]
 func (h *BookingHandler) CancelAppointment(c *gin.Context) {
 	slotID := c.Param("id")
@@ -454,11 +542,17 @@ func (h *BookingHandler) CancelAppointment(c *gin.Context) {
 	}
 
 	psychID := slot.PsychologistID
+	contacts, err := h.lookupStudentAndPsych(c.Request.Context(), studentID, psychID)
+	if err != nil {
+		respondContactsUnavailable(c)
+		return
+	}
+	candidates := h.waitlistCandidates(c.Request.Context(), slot)
+
+	tx := config.DB.Begin()
 
-	result := config.DB.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", slot.ID, slot.Version).
-		Updates(map[string]interface{}{
-			"status":                models.StatusAvailable,
+	result := models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		models.StatusBooked, models.StatusAvailable, map[string]interface{}{
 			"student_id":            nil,
 			"booking_type":          "",
 			"questionnaire_answers": "",
@@ -466,6 +560,7 @@ func (h *BookingHandler) CancelAppointment(c *gin.Context) {
 		})
 
 	if result.Error != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error: "Database error",
 		})
@@ -473,45 +568,47 @@ func (h *BookingHandler) CancelAppointment(c *gin.Context) {
 	}
 
 	if result.RowsAffected == 0 {
+		tx.Rollback()
 		c.JSON(http.StatusConflict, models.ErrorResponse{
 			Error: "Could not cancel. Please try again.",
 		})
 		return
 	}
 
-	logBookingAction(slot.ID, slot.PsychologistID, *slot.StudentID, "canceled_by_student")
-
-	go func() {
-		resp, err := h.UserClient.GetBatchUserProfiles(context.Background(), &userprofile.GetBatchUserProfilesRequest{
-			Ids: []string{studentID, psychID},
+	msg := clients.NotificationMessage{
+		Type:    "booking_cancellation",
+		ToEmail: contacts.StudentEmail,
+		Data: map[string]string{
+			"psychologist_name": contacts.PsychName,
+			"datetime":          slot.StartTime.In(contacts.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04"),
+		},
+	}
+	addCalendarEvent(msg.Data, "", slot, slot.Version+1)
+	if err := enqueueNotification(tx, msg); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Database error",
 		})
+		return
+	}
 
-		var studentEmail, psychName string
-		if err == nil {
-			for _, p := range resp.Profiles {
-				if p.Id == studentID {
-					studentEmail = p.Email
-				} else if p.Id == psychID {
-					psychName = p.FullName
-				}
-			}
-		}
+	// The freed slot is held for the first student on the waitlist for that day
+	if err := promoteWaitlist(tx, slot.ID, candidates); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Database error",
+		})
+		return
+	}
 
-		if studentEmail != "" {
-			msg := clients.NotificationMessage{
-				Type:    "booking_cancellation",
-				ToEmail: studentEmail,
-				Data: map[string]string{
-					"psychologist_name": psychName,
-					"datetime":          slot.StartTime.Format("Monday, 02 Jan 2006 at 15:04"),
-				},
-			}
-			h.RabbitMQ.PublishNotification(msg)
-		}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Database error",
+		})
+		return
+	}
 
-		dateStr := slot.StartTime.Format("2006-01-02")
-		h.notifyWaitlist(psychID, dateStr, psychName)
-	}()
+	logBookingAction(slot.ID, slot.PsychologistID, *slot.StudentID, "canceled_by_student")
 
 	c.JSON(http.StatusOK, models.MessageResponse{
 		Message: "Appointment successfully canceled",
@@ -533,6 +630,7 @@ func (h *BookingHandler) CancelAppointment(c *gin.Context) {
 // @Failure      404      {object}  models.ErrorResponse    "Slot not found"
 // @Failure      409      {object}  models.ErrorResponse    "New slot already booked or race condition"
 // @Failure      500      {object}  models.ErrorResponse    "Database error"
+// @Failure      503      {object}  models.ErrorResponse    "User service unavailable, nothing was changed"
 // @Router       /student/slots/{id}/reschedule [post]
 func (h *BookingHandler) RescheduleAppointment(c *gin.Context) {
 	oldSlotID := c.Param("id")
@@ -555,47 +653,48 @@ func (h *BookingHandler) RescheduleAppointment(c *gin.Context) {
 		return
 	}
 
-	// START TRANSACTION
-	tx := config.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	// Fetch Old Slot
 	var oldSlot models.Slot
-	if err := tx.First(&oldSlot, "id = ?", oldSlotID).Error; err != nil {
-		tx.Rollback()
+	if err := config.DB.First(&oldSlot, "id = ?", oldSlotID).Error; err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Old slot not found"})
 		return
 	}
 
 	if oldSlot.Status != models.StatusBooked || oldSlot.StudentID == nil || *oldSlot.StudentID != studentID {
-		tx.Rollback()
 		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only reschedule your own active appointments"})
 		return
 	}
 
 	// 2. Fetch New Slot
 	var newSlot models.Slot
-	if err := tx.First(&newSlot, "id = ?", input.NewSlotID).Error; err != nil {
-		tx.Rollback()
+	if err := config.DB.First(&newSlot, "id = ?", input.NewSlotID).Error; err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "New slot not found"})
 		return
 	}
 
 	if newSlot.Status != models.StatusAvailable {
-		tx.Rollback()
 		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The requested new slot is no longer available"})
 		return
 	}
 
+	contacts, err := h.lookupStudentAndPsych(c.Request.Context(), studentID, newSlot.PsychologistID)
+	if err != nil {
+		respondContactsUnavailable(c)
+		return
+	}
+	candidates := h.waitlistCandidates(c.Request.Context(), oldSlot)
+
+	// START TRANSACTION: both writes check the version read above
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
 	// Free up the Old Slot
-	res1 := tx.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", oldSlot.ID, oldSlot.Version).
-		Updates(map[string]interface{}{
-			"status":                models.StatusAvailable,
+	res1 := models.TransitionSlot(tx.Where("id = ? AND version = ?", oldSlot.ID, oldSlot.Version),
+		models.StatusBooked, models.StatusAvailable, map[string]interface{}{
 			"student_id":            nil,
 			"booking_type":          "",
 			"questionnaire_answers": "",
@@ -609,10 +708,8 @@ func (h *BookingHandler) RescheduleAppointment(c *gin.Context) {
 	}
 
 	// Book the New Slot (Transferring the data from the old one)
-	res2 := tx.Model(&models.Slot{}).
-		Where("id = ? AND version = ?", newSlot.ID, newSlot.Version).
-		Updates(map[string]interface{}{
-			"status":                models.StatusBooked,
+	res2 := models.TransitionSlot(tx.Where("id = ? AND version = ?", newSlot.ID, newSlot.Version),
+		models.StatusAvailable, models.StatusBooked, map[string]interface{}{
 			"student_id":            studentID,
 			"booking_type":          oldSlot.BookingType,
 			"questionnaire_answers": oldSlot.QuestionnaireAnswers,
@@ -625,44 +722,35 @@ func (h *BookingHandler) RescheduleAppointment(c *gin.Context) {
 		return
 	}
 
-	// COMMIT TRANSACTION
-	if err := tx.Commit().Error; err != nil {
+	msg := clients.NotificationMessage{
+		Type:    "booking_reschedule",
+		ToEmail: contacts.StudentEmail,
+		Data: map[string]string{
+			"psychologist_name": contacts.PsychName,
+			"datetime":          newSlot.StartTime.In(contacts.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04"),
+			"format":            oldSlot.BookingType,
+		},
+	}
+	addCalendarEvent(msg.Data, "", newSlot, newSlot.Version+1)
+	addCalendarEvent(msg.Data, "old_", oldSlot, oldSlot.Version+1)
+	if err := enqueueNotification(tx, msg); err != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Transaction failed"})
 		return
 	}
 
-	go func() {
-		resp, err := h.UserClient.GetBatchUserProfiles(context.Background(), &userprofile.GetBatchUserProfilesRequest{
-			Ids: []string{studentID, newSlot.PsychologistID},
-		})
-
-		var studentEmail, psychName string
-		if err == nil {
-			for _, p := range resp.Profiles {
-				if p.Id == studentID {
-					studentEmail = p.Email
-				} else if p.Id == newSlot.PsychologistID {
-					psychName = p.FullName
-				}
-			}
-		}
-
-		if studentEmail != "" {
-			msg := clients.NotificationMessage{
-				Type:    "booking_reschedule",
-				ToEmail: studentEmail,
-				Data: map[string]string{
-					"psychologist_name": psychName,
-					"datetime":          newSlot.StartTime.Format("Monday, 02 Jan 2006 at 15:04"),
-					"format":            oldSlot.BookingType,
-				},
-			}
-			h.RabbitMQ.PublishNotification(msg)
-		}
+	// The freed old slot is held for the first student on the waitlist for that day
+	if err := promoteWaitlist(tx, oldSlot.ID, candidates); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Transaction failed"})
+		return
+	}
 
-		oldDateStr := oldSlot.StartTime.Format("2006-01-02")
-		h.notifyWaitlist(oldSlot.PsychologistID, oldDateStr, psychName)
-	}()
+	// COMMIT TRANSACTION
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Transaction failed"})
+		return
+	}
 
 	c.JSON(http.StatusOK, models.MessageResponse{
 		Message: "Appointment successfully rescheduled",
@@ -679,7 +767,7 @@ func (h *BookingHandler) RescheduleAppointment(c *gin.Context) {
 // @Param        id      path   string            true  "Slot ID"
 // @Param        request body   models.RateSessionInput  true  "Rating and Review"
 // @Success      200 {object} models.MessageResponse
-// @Failure      400 {object} models.ErrorResponse "Invalid rating (must be 1-5) or session not finished"
+// @Failure      400 {object} models.ErrorResponse "Invalid rating (must be 1-5) or session not completed"
 // @Failure      403 {object} models.ErrorResponse "Not authorized"
 // @Failure      404 {object} models.ErrorResponse "Slot not found"
 // @Failure      409 {object} models.ErrorResponse "Session already rated"
@@ -706,14 +794,14 @@ func (h *BookingHandler) RateSession(c *gin.Context) {
 		return
 	}
 
-	if slot.Status != models.StatusBooked || slot.StudentID == nil || *slot.StudentID != studentID {
-		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only rate your own booked sessions"})
+	if slot.StudentID == nil || *slot.StudentID != studentID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only rate your own sessions"})
 		return
 	}
 
-	// Time Check
-	if time.Now().Before(slot.StartTime) {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "You cannot rate a session that hasn't happened yet"})
+	// Only sessions the psychologist marked as completed can be rated
+	if slot.Status != models.StatusCompleted {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "You can only rate a session once it has been marked as completed"})
 		return
 	}
 
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetStudentMood godoc
+// @Summary      Get a student's mood timeline
+// @Description  Returns the mood entries the student shared with the psychologist, oldest first. Requires a session with the student that is booked or has taken place (completed or no-show) and the student's consent, which they can revoke at any time. The range defaults to the last 30 days.
+// @Tags         psychologist-slots
+// @Produce      json
+// @Security     BearerAuth
+// @Param        student_id path  string true  "Student ID"
+// @Param        from       query string false "First day, YYYY-MM-DD"
+// @Param        to         query string false "Last day, YYYY-MM-DD (default: today)"
+// @Success      200 {object} models.StudentMoodResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse "No session or no consent"
+// @Router       /psychologist/students/{student_id}/mood [get]
+func (h *BookingHandler) GetStudentMood(c *gin.Context) {
+	studentID := c.Param("student_id")
+	psychID := c.GetHeader("X-User-ID")
+
+	if c.GetHeader("X-User-Role") != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Access denied"})
+		return
+	}
+
+	// Past sessions count too: reviewing the mood logs after a session is the point
+	var sessions int64
+	err := config.DB.Model(&models.Slot{}).
+		Where("psychologist_id = ? AND student_id = ? AND status IN ?", psychID, studentID,
+			[]string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow}).
+		Count(&sessions).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if sessions == 0 {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Mood history is only available for students with a session with you"})
+		return
+	}
+
+	to := c.DefaultQuery("to", time.Now().Format("2006-01-02"))
+	from := c.Query("from")
+	if from == "" {
+		end, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "to must be a date in YYYY-MM-DD format"})
+			return
+		}
+		from = end.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+
+	res, err := h.UserClient.GetConsentedMoodLogs(c.Request.Context(), &userprofile.GetConsentedMoodLogsRequest{
+		StudentId:      studentID,
+		PsychologistId: psychID,
+		From:           from,
+		To:             to,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied:
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "The student has not shared their mood logs with you"})
+		case codes.InvalidArgument:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: status.Convert(err).Message()})
+		default:
+			log.Printf("Failed to fetch mood logs from user-service: %v", err)
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{Error: "Failed to load mood logs"})
+		}
+		return
+	}
+
+	entries := make([]models.StudentMoodEntry, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		entries = append(entries, models.StudentMoodEntry{
+			ID:       e.Id,
+			LoggedAt: e.LoggedAt,
+			Date:     e.Date,
+			Mood:     e.Mood,
+			Score:    int(e.Score),
+			Note:     e.Note,
+			Tags:     e.Tags,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.StudentMoodResponse{
+		StudentID:   studentID,
+		From:        from,
+		To:          to,
+		NotesShared: res.NotesShared,
+		Entries:     entries,
+	})
+}
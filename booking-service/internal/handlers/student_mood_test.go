@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getStudentMood(h *BookingHandler, studentID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/psychologist/students/:student_id/mood", h.GetStudentMood)
+
+	req := httptest.NewRequest(http.MethodGet, "/psychologist/students/"+studentID+"/mood", nil)
+	req.Header.Set("X-User-ID", "psych-1")
+	req.Header.Set("X-User-Role", "psychologist")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetStudentMoodNeedsASession(t *testing.T) {
+	setupTestDB(t)
+	client := new(MockUserClient)
+	client.On("GetConsentedMoodLogs", mock.Anything, mock.Anything).
+		Return(&userprofile.GetConsentedMoodLogsResponse{Entries: []*userprofile.MoodEntry{{Id: "entry-1", Mood: "Sad", Score: 3}}}, nil)
+	h := &BookingHandler{UserClient: client}
+
+	now := time.Now()
+	for i, status := range []string{models.StatusBooked, models.StatusCompleted, models.StatusNoShow, models.StatusCanceled, models.StatusReserved} {
+		student := "student-" + status
+		createSlot(t, "slot-"+status, status, &student, now.Add(time.Duration(i)*time.Hour))
+	}
+
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{models.StatusBooked, http.StatusOK},
+		// Reviewing the logs after the session is the point
+		{models.StatusCompleted, http.StatusOK},
+		{models.StatusNoShow, http.StatusOK},
+		{models.StatusCanceled, http.StatusForbidden},
+		{models.StatusReserved, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			w := getStudentMood(h, "student-"+tt.status)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+
+	assert.Equal(t, http.StatusForbidden, getStudentMood(h, "student-unknown").Code)
+	client.AssertNumberOfCalls(t, "GetConsentedMoodLogs", 3)
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
+	"gorm.io/gorm"
+)
+
+// CreateTemplate godoc
+// @Summary      Create a recurring availability template
+// @Description  Saves a weekly schedule pattern. Slots are generated from it immediately and kept generated a few weeks ahead by a background worker.
+// @Tags         psychologist-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.AvailabilityTemplateInput true "Template configuration"
+// @Success      201 {object} models.AvailabilityTemplate
+// @Failure      400 {object} models.ErrorResponse "validation error"
+// @Failure      403 {object} models.ErrorResponse "only psychologists can create templates"
+// @Failure      500 {object} models.ErrorResponse "database error"
+// @Router       /psychologist/templates [post]
+func (h *BookingHandler) CreateTemplate(c *gin.Context) {
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can create templates"})
+		return
+	}
+
+	var input models.AvailabilityTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := validateTemplateInput(&input); err != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err})
+		return
+	}
+
+	tpl := models.AvailabilityTemplate{
+		ID:             uuid.NewString(),
+		PsychologistID: psychID,
+		Name:           input.Name,
+		Duration:       input.Duration,
+		StartDate:      input.StartDate,
+		EndDate:        input.EndDate,
+		Schedule:       input.Schedule,
+		TimeZone:       h.userLocation(c.Request.Context(), psychID).String(),
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tpl).Error; err != nil {
+			return err
+		}
+		_, err := scheduling.MaterializeTemplate(tx, &tpl, scheduling.Horizon())
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tpl)
+}
+
+// GetMyTemplates godoc
+// @Summary      List availability templates
+// @Description  Returns all recurring availability templates of the logged-in psychologist.
+// @Tags         psychologist-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.AvailabilityTemplate
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /psychologist/templates [get]
+func (h *BookingHandler) GetMyTemplates(c *gin.Context) {
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Access denied"})
+		return
+	}
+
+	var templates []models.AvailabilityTemplate
+	if err := config.DB.Where("psychologist_id = ?", psychID).Order("created_at asc").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// UpdateTemplate godoc
+// @Summary      Update an availability template
+// @Description  Replaces the template's pattern and regenerates its future available slots. Reserved and booked slots are never touched.
+// @Tags         psychologist-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path string                           true "Template ID"
+// @Param        request body models.AvailabilityTemplateInput true "Template configuration"
+// @Success      200 {object} models.AvailabilityTemplate
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /psychologist/templates/{id} [put]
+func (h *BookingHandler) UpdateTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can update templates"})
+		return
+	}
+
+	var input models.AvailabilityTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := validateTemplateInput(&input); err != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err})
+		return
+	}
+
+	var tpl models.AvailabilityTemplate
+	if err := config.DB.First(&tpl, "id = ?", templateID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Template not found"})
+		return
+	}
+	if tpl.PsychologistID != psychID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only update your own templates"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := scheduling.ResetTemplate(tx, &tpl); err != nil {
+			return err
+		}
+
+		tpl.Name = input.Name
+		tpl.Duration = input.Duration
+		tpl.StartDate = input.StartDate
+		tpl.EndDate = input.EndDate
+		tpl.Schedule = input.Schedule
+		tpl.TimeZone = h.userLocation(c.Request.Context(), psychID).String()
+		if err := tx.Save(&tpl).Error; err != nil {
+			return err
+		}
+
+		_, err := scheduling.MaterializeTemplate(tx, &tpl, scheduling.Horizon())
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tpl)
+}
+
+// DeleteTemplate godoc
+// @Summary      Delete an availability template
+// @Description  Deletes the template and its future available slots. Reserved and booked slots are kept.
+// @Tags         psychologist-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Template ID"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /psychologist/templates/{id} [delete]
+func (h *BookingHandler) DeleteTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can delete templates"})
+		return
+	}
+
+	var tpl models.AvailabilityTemplate
+	if err := config.DB.First(&tpl, "id = ?", templateID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Template not found"})
+		return
+	}
+	if tpl.PsychologistID != psychID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only delete your own templates"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := scheduling.ResetTemplate(tx, &tpl); err != nil {
+			return err
+		}
+		return tx.Delete(&tpl).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Template successfully deleted"})
+}
+
+// validateTemplateInput applies defaults and returns a user-facing error, or "" if the input is valid
+func validateTemplateInput(input *models.AvailabilityTemplateInput) string {
+	if input.Duration == 0 {
+		input.Duration = 50
+	}
+
+	startDate, err := parseDate(input.StartDate)
+	if err != nil {
+		return "Invalid start_date"
+	}
+	if input.EndDate != "" {
+		endDate, err := parseDate(input.EndDate)
+		if err != nil {
+			return "Invalid end_date"
+		}
+		if endDate.Before(startDate) {
+			return "end_date must not be before start_date"
+		}
+	}
+
+	for _, day := range input.Schedule {
+		if day.DayOfWeek < 0 || day.DayOfWeek > 6 {
+			return "day_of_week must be between 0 (Sunday) and 6 (Saturday)"
+		}
+	}
+	return ""
+}
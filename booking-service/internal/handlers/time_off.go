@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"gorm.io/gorm"
+)
+
+// CreateTimeOff godoc
+// @Summary      Block a date range as time off
+// @Description  Removes free and reserved slots and waitlists in the range and prevents new slots there. Slots that have already started are not touched. Booked sessions in the range are marked canceled. Every student with a booked or reserved slot in the range is notified; with action "reschedule" they are also offered the nearest free slot.
+// @Tags         psychologist-time-off
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.TimeOffInput true "Date range (inclusive, in the psychologist's time zone)"
+// @Success      201 {object} models.TimeOffResponse
+// @Failure      400 {object} models.ErrorResponse "validation error"
+// @Failure      403 {object} models.ErrorResponse "only psychologists can take time off"
+// @Failure      409 {object} models.ErrorResponse "a booking changed concurrently, retry"
+// @Failure      500 {object} models.ErrorResponse "database error"
+// @Failure      503 {object} models.ErrorResponse "students to notify could not be resolved"
+// @Router       /psychologist/time-off [post]
+func (h *BookingHandler) CreateTimeOff(c *gin.Context) {
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only psychologists can take time off"})
+		return
+	}
+
+	var input models.TimeOffInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if input.Action == "" {
+		input.Action = models.TimeOffCancel
+	}
+
+	timeOff := models.TimeOff{
+		ID:             uuid.NewString(),
+		PsychologistID: psychID,
+		StartDate:      input.StartDate,
+		EndDate:        input.EndDate,
+		Reason:         input.Reason,
+		Action:         input.Action,
+	}
+
+	loc := h.userLocation(c.Request.Context(), psychID)
+	rangeStart, rangeEnd, err := scheduling.TimeOffRange(timeOff, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid start_date or end_date"})
+		return
+	}
+	if !rangeEnd.After(rangeStart) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "end_date must not be before start_date"})
+		return
+	}
+
+	// Sessions that already started are left alone even when the range began earlier, so they can
+	// still be marked completed or no-show and nobody is told a past session was canceled
+	from := rangeStart
+	if now := time.Now(); now.After(from) {
+		from = now
+	}
+
+	// Students with a booked or reserved slot inside the range are notified; their contacts are fetched before the transaction
+	var affected []models.Slot
+	if err := config.DB.
+		Where("psychologist_id = ? AND status IN ? AND student_id IS NOT NULL", psychID, []string{models.StatusBooked, models.StatusReserved}).
+		Where("start_time >= ? AND start_time < ?", from, rangeEnd).
+		Order("start_time asc").
+		Find(&affected).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	contacts, err := h.lookupBookedStudents(c.Request.Context(), psychID, affected)
+	if err != nil {
+		respondContactsUnavailable(c)
+		return
+	}
+
+	response := models.TimeOffResponse{TimeOff: timeOff}
+
+	tx := config.DB.Begin()
+
+	if err := tx.Create(&timeOff).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save time off"})
+		return
+	}
+
+	removed := tx.
+		Where("psychologist_id = ? AND status = ?", psychID, models.StatusAvailable).
+		Where("start_time >= ? AND start_time < ?", from, rangeEnd).
+		Delete(&models.Slot{})
+	if removed.Error != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	response.RemovedSlots = int(removed.RowsAffected)
+
+	// Nothing will free up on these days, so their waitlists (including removed holds) are dropped
+	if err := tx.Where("psychologist_id = ? AND date >= ? AND date <= ?", psychID, timeOff.StartDate, timeOff.EndDate).
+		Delete(&models.WaitlistEntry{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	var offered []string
+	for _, slot := range affected {
+		var res *gorm.DB
+		if slot.Status == models.StatusBooked {
+			// The session cannot take place. The slot stays as canceled so the booking remains in the history.
+			res = models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+				models.StatusBooked, models.StatusCanceled, map[string]interface{}{
+					"version": slot.Version + 1,
+				})
+		} else {
+			// A reservation in progress or a waitlist hold; it was never confirmed, so nothing is kept
+			res = tx.Where("id = ? AND status = ? AND version = ?", slot.ID, models.StatusReserved, slot.Version).
+				Delete(&models.Slot{})
+		}
+		if res.Error != nil || res.RowsAffected == 0 {
+			tx.Rollback()
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: "A booking changed while processing. Please try again."})
+			return
+		}
+		if slot.Status == models.StatusBooked {
+			response.CanceledBookings++
+		} else {
+			response.RemovedSlots++
+		}
+
+		student := contacts[*slot.StudentID]
+
+		var suggestion *models.Slot
+		if input.Action == models.TimeOffReschedule {
+			suggestion, err = nearestFreeSlot(tx, psychID, slot.StartTime, rangeStart, rangeEnd, offered)
+			if err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+				return
+			}
+		}
+
+		msg := clients.NotificationMessage{
+			Type:    "booking_cancellation_by_psychologist",
+			ToEmail: student.StudentEmail,
+			Data: map[string]string{
+				"psychologist_name": student.PsychName,
+				"datetime":          slot.StartTime.In(student.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04"),
+			},
+		}
+		if suggestion != nil {
+			// Not held for the student: they reserve it through the normal booking flow
+			offered = append(offered, suggestion.ID)
+			response.RescheduleOffers++
+			msg.Type = "booking_reschedule_offer"
+			msg.Data["suggested_datetime"] = suggestion.StartTime.In(student.StudentLocation).Format("Monday, 02 Jan 2006 at 15:04")
+			msg.Data["suggested_slot_id"] = suggestion.ID
+		}
+		if slot.Status == models.StatusBooked {
+			// Only a confirmed booking was sent a calendar invite to withdraw
+			addCalendarEvent(msg.Data, "", slot, slot.Version+1)
+		}
+		if err := enqueueNotification(tx, msg); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+	}
+
+	// A slot reserved after the read above would be cleared without its student hearing about it
+	var reserved int64
+	if err := tx.Model(&models.Slot{}).
+		Where("psychologist_id = ? AND status = ?", psychID, models.StatusReserved).
+		Where("start_time >= ? AND start_time < ?", from, rangeEnd).
+		Count(&reserved).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if reserved > 0 {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "A booking changed while processing. Please try again."})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	for _, slot := range affected {
+		if slot.Status == models.StatusBooked {
+			logBookingAction(slot.ID, psychID, *slot.StudentID, "canceled_by_time_off")
+		}
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetMyTimeOff godoc
+// @Summary      List time-off blocks
+// @Description  Returns the logged-in psychologist's time-off blocks, newest first.
+// @Tags         psychologist-time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.TimeOff
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /psychologist/time-off [get]
+func (h *BookingHandler) GetMyTimeOff(c *gin.Context) {
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Access denied"})
+		return
+	}
+
+	var blocks []models.TimeOff
+	if err := config.DB.Where("psychologist_id = ?", psychID).Order("start_date desc").Find(&blocks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, blocks)
+}
+
+// DeleteTimeOff godoc
+// @Summary      Remove a time-off block
+// @Description  Allows slots to be created in the range again. Removed slots and canceled bookings are not restored.
+// @Tags         psychologist-time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Time-off ID"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /psychologist/time-off/{id} [delete]
+func (h *BookingHandler) DeleteTimeOff(c *gin.Context) {
+	psychID := c.GetHeader("X-User-ID")
+	role := c.GetHeader("X-User-Role")
+
+	if role != "psychologist" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Access denied"})
+		return
+	}
+
+	var block models.TimeOff
+	if err := config.DB.First(&block, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Time off not found"})
+		return
+	}
+	if block.PsychologistID != psychID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only delete your own time off"})
+		return
+	}
+
+	if err := config.DB.Delete(&block).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Time off removed"})
+}
+
+// lookupBookedStudents resolves contacts for every student in slots, keyed by student ID.
+// Like lookupStudentAndPsych it fails when a student cannot be resolved, so nobody loses a session unnoticed.
+func (h *BookingHandler) lookupBookedStudents(ctx context.Context, psychID string, slots []models.Slot) (map[string]bookingContacts, error) {
+	contacts := make(map[string]bookingContacts)
+	if len(slots) == 0 {
+		return contacts, nil
+	}
+
+	ids := []string{psychID}
+	for _, s := range slots {
+		ids = append(ids, *s.StudentID)
+	}
+
+	resp, err := h.UserClient.GetBatchUserProfiles(ctx, &userprofile.GetBatchUserProfilesRequest{Ids: ids})
+	if err != nil {
+		log.Printf("Failed to fetch profiles for time off notifications: %v", err)
+		return nil, fmt.Errorf("%w: %v", errContactsUnavailable, err)
+	}
+
+	var psychName string
+	for _, p := range resp.Profiles {
+		if p.Id == psychID {
+			psychName = p.FullName
+		}
+	}
+	for _, p := range resp.Profiles {
+		if p.Id == psychID {
+			continue
+		}
+		contacts[p.Id] = bookingContacts{
+			StudentEmail:    p.Email,
+			PsychName:       psychName,
+			StudentLocation: scheduling.LoadLocation(p.TimeZone),
+		}
+	}
+	for _, s := range slots {
+		if contacts[*s.StudentID].StudentEmail == "" {
+			log.Printf("No email for student %s, cannot notify about time off", *s.StudentID)
+			return nil, fmt.Errorf("%w: no email for student %s", errContactsUnavailable, *s.StudentID)
+		}
+	}
+	return contacts, nil
+}
+
+// nearestFreeSlot finds the psychologist's future available slot closest to around, outside [rangeStart, rangeEnd)
+// and not already offered to someone else. It returns nil if there is none.
+func nearestFreeSlot(tx *gorm.DB, psychID string, around, rangeStart, rangeEnd time.Time, exclude []string) (*models.Slot, error) {
+	query := func() *gorm.DB {
+		q := tx.Where("psychologist_id = ? AND status = ?", psychID, models.StatusAvailable)
+		if len(exclude) > 0 {
+			q = q.Where("id NOT IN ?", exclude)
+		}
+		return q
+	}
+
+	var candidates []models.Slot
+
+	var before []models.Slot
+	if err := query().Where("start_time > ? AND start_time < ?", time.Now(), rangeStart).
+		Order("start_time desc").Limit(1).Find(&before).Error; err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, before...)
+
+	var after []models.Slot
+	if err := query().Where("start_time >= ?", rangeEnd).
+		Order("start_time asc").Limit(1).Find(&after).Error; err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, after...)
+
+	var best *models.Slot
+	for i := range candidates {
+		if best == nil || absDuration(candidates[i].StartTime.Sub(around)) < absDuration(best.StartTime.Sub(around)) {
+			best = &candidates[i]
+		}
+	}
+	return best, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
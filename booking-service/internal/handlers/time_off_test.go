@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createTimeOff(h *BookingHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/psychologist/time-off", h.CreateTimeOff)
+
+	req := httptest.NewRequest(http.MethodPost, "/psychologist/time-off", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "psych-1")
+	req.Header.Set("X-User-Role", "psychologist")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// timeOffDays is a two-day block starting the day after tomorrow, in UTC like the test psychologist
+func timeOffDays() (time.Time, string) {
+	first := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 2)
+	body := `{"start_date":"` + first.Format("2006-01-02") + `","end_date":"` + first.AddDate(0, 0, 1).Format("2006-01-02") + `","action":"reschedule"}`
+	return first, body
+}
+
+func TestCreateTimeOffNotifiesReservedAndBookedStudents(t *testing.T) {
+	setupTestDB(t)
+	first, body := timeOffDays()
+	booked, holding := "student-1", "student-2"
+	createSlot(t, "booked", models.StatusBooked, &booked, first.Add(10*time.Hour))
+	createSlot(t, "reserved", models.StatusReserved, &holding, first.Add(12*time.Hour))
+	createSlot(t, "free-inside", models.StatusAvailable, nil, first.Add(34*time.Hour))
+	createSlot(t, "free-after", models.StatusAvailable, nil, first.AddDate(0, 0, 3).Add(10*time.Hour))
+
+	client := new(MockUserClient)
+	client.On("GetUserProfileByID", mock.Anything, mock.Anything).
+		Return(&userprofile.GetUserProfileByIDResponse{Id: "psych-1", TimeZone: "UTC"}, nil)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: []*userprofile.BasicUserProfile{
+			{Id: "psych-1", FullName: "Dr. Smith"},
+			{Id: "student-1", Email: "booked@test.com"},
+			{Id: "student-2", Email: "holding@test.com"},
+		}}, nil)
+
+	w := createTimeOff(&BookingHandler{UserClient: client}, body)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response models.TimeOffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.RemovedSlots, "the free and the reserved slot")
+	assert.Equal(t, 1, response.CanceledBookings)
+	assert.Equal(t, 1, response.RescheduleOffers)
+
+	var slots []models.Slot
+	config.DB.Order("start_time asc").Find(&slots)
+	require.Len(t, slots, 2)
+	assert.Equal(t, "booked", slots[0].ID)
+	assert.Equal(t, models.StatusCanceled, slots[0].Status)
+	assert.Equal(t, "free-after", slots[1].ID)
+
+	// The one free slot goes to the earlier session, the reservation is only canceled
+	var queued []models.OutboxMessage
+	config.DB.Find(&queued)
+	require.Len(t, queued, 2)
+	sent := map[string]clients.NotificationMessage{}
+	for _, q := range queued {
+		var msg clients.NotificationMessage
+		require.NoError(t, json.Unmarshal([]byte(q.Payload), &msg))
+		sent[msg.ToEmail] = msg
+	}
+	assert.Equal(t, "booking_reschedule_offer", sent["booked@test.com"].Type)
+	assert.Equal(t, "free-after", sent["booked@test.com"].Data["suggested_slot_id"])
+	assert.Equal(t, "booked", sent["booked@test.com"].Data["slot_id"])
+
+	held := sent["holding@test.com"]
+	assert.Equal(t, "booking_cancellation_by_psychologist", held.Type)
+	assert.Equal(t, "Dr. Smith", held.Data["psychologist_name"])
+	assert.NotContains(t, held.Data, "slot_id", "no calendar invite to withdraw")
+}
+
+func TestCreateTimeOffFailsWithoutContacts(t *testing.T) {
+	setupTestDB(t)
+	first, body := timeOffDays()
+	holding := "student-2"
+	createSlot(t, "reserved", models.StatusReserved, &holding, first.Add(12*time.Hour))
+
+	client := new(MockUserClient)
+	client.On("GetUserProfileByID", mock.Anything, mock.Anything).
+		Return(&userprofile.GetUserProfileByIDResponse{Id: "psych-1", TimeZone: "UTC"}, nil)
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused")).Once()
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: []*userprofile.BasicUserProfile{
+			{Id: "psych-1", FullName: "Dr. Smith"},
+		}}, nil).Once()
+	h := &BookingHandler{UserClient: client}
+
+	for range 2 {
+		w := createTimeOff(h, body)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+
+	var blocks, slots, queued int64
+	config.DB.Model(&models.TimeOff{}).Count(&blocks)
+	config.DB.Model(&models.Slot{}).Where("status = ?", models.StatusReserved).Count(&slots)
+	config.DB.Model(&models.OutboxMessage{}).Count(&queued)
+	assert.Zero(t, blocks)
+	assert.Equal(t, int64(1), slots)
+	assert.Zero(t, queued)
+}
+
+func TestCreateTimeOffLeavesPastSessionsAlone(t *testing.T) {
+	setupTestDB(t)
+	// The block started yesterday; the session an hour ago already took place
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	student := "student-1"
+	createSlot(t, "past", models.StatusBooked, &student, time.Now().Add(-time.Hour))
+
+	client := new(MockUserClient)
+	client.On("GetUserProfileByID", mock.Anything, mock.Anything).
+		Return(&userprofile.GetUserProfileByIDResponse{Id: "psych-1", TimeZone: "UTC"}, nil)
+
+	w := createTimeOff(&BookingHandler{UserClient: client},
+		`{"start_date":"`+yesterday+`","end_date":"`+tomorrow+`","action":"reschedule"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response models.TimeOffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Zero(t, response.CanceledBookings)
+
+	var slot models.Slot
+	require.NoError(t, config.DB.First(&slot, "id = ?", "past").Error)
+	assert.Equal(t, models.StatusBooked, slot.Status, "it can still be marked completed or no-show")
+	var queued int64
+	config.DB.Model(&models.OutboxMessage{}).Count(&queued)
+	assert.Zero(t, queued)
+}
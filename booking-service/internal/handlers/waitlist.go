@@ -13,7 +13,7 @@ import (
 
 // JoinWaitlist godoc
 // @Summary      Join a waitlist
-// @Description  Student joins a waitlist for a specific psychologist on a specific date.
+// @Description  Student joins a waitlist for a specific psychologist on a specific date. When a slot of that day frees up, it is held for the first student in line, who gets an email with a confirm link.
 // @Tags         student-waitlist
 // @Accept       json
 // @Produce      json
@@ -21,6 +21,7 @@ import (
 // @Param        request body models.JoinWaitlistInput true "Waitlist details"
 // @Success      201 {object} models.MessageResponse
 // @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse "Booking restricted after repeated no-shows"
 // @Failure      409 {object} models.ErrorResponse "Already on waitlist"
 // @Router       /student/waitlist [post]
 func (h *BookingHandler) JoinWaitlist(c *gin.Context) {
@@ -38,6 +39,18 @@ func (h *BookingHandler) JoinWaitlist(c *gin.Context) {
 		return
 	}
 
+	restricted, err := noShowRestricted(config.DB, studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if restricted {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: "Booking is temporarily unavailable because of repeated missed sessions. Please contact the psychological service.",
+		})
+		return
+	}
+
 	// Basic date validation
 	if _, err := parseDate(input.Date); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid date format. Use YYYY-MM-DD"})
@@ -62,7 +75,7 @@ func (h *BookingHandler) JoinWaitlist(c *gin.Context) {
 
 // GetMyWaitlist godoc
 // @Summary      View my waitlists
-// @Description  Student sees all the dates and psychologists they are waiting for.
+// @Description  Student sees all the dates and psychologists they are waiting for, with their position in line or the slot currently held for them.
 // @Tags         student-waitlist
 // @Produce      json
 // @Security     BearerAuth
@@ -105,13 +118,30 @@ func (h *BookingHandler) GetMyWaitlist(c *gin.Context) {
 		if val, ok := psychMap[e.PsychologistID]; ok {
 			name = val
 		}
-		response = append(response, models.WaitlistResponse{
+		item := models.WaitlistResponse{
 			ID:               e.ID,
 			PsychologistID:   e.PsychologistID,
 			PsychologistName: name,
 			Date:             e.Date,
+			Status:           e.Status,
 			CreatedAt:        e.CreatedAt,
-		})
+		}
+
+		if e.Status == models.WaitlistOffered && e.SlotID != nil {
+			var slot models.Slot
+			if err := config.DB.First(&slot, "id = ?", *e.SlotID).Error; err == nil {
+				item.SlotID = e.SlotID
+				item.HoldExpiresAt = slot.HoldExpiresAt
+			}
+		} else {
+			var ahead int64
+			config.DB.Model(&models.WaitlistEntry{}).
+				Where("psychologist_id = ? AND date = ? AND status = ? AND created_at < ?", e.PsychologistID, e.Date, models.WaitlistWaiting, e.CreatedAt).
+				Count(&ahead)
+			item.Position = int(ahead) + 1
+		}
+
+		response = append(response, item)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -119,7 +149,7 @@ func (h *BookingHandler) GetMyWaitlist(c *gin.Context) {
 
 // LeaveWaitlist godoc
 // @Summary      Leave a waitlist
-// @Description  Student removes themselves from a specific waitlist entry.
+// @Description  Student removes themselves from a specific waitlist entry. A slot held for them is passed on to the next student in line.
 // @Tags         student-waitlist
 // @Produce      json
 // @Security     BearerAuth
@@ -130,16 +160,45 @@ func (h *BookingHandler) LeaveWaitlist(c *gin.Context) {
 	entryID := c.Param("id")
 	studentID := c.GetHeader("X-User-ID")
 
-	// Delete where ID matches AND StudentID matches
-	res := config.DB.Where("id = ? AND student_id = ?", entryID, studentID).Delete(&models.WaitlistEntry{})
+	var entry models.WaitlistEntry
+	if err := config.DB.Where("id = ? AND student_id = ?", entryID, studentID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Waitlist entry not found or unauthorized"})
+		return
+	}
+
+	// Giving up a held slot passes it on to the next student in line
+	var held *models.Slot
+	var candidates []waitlistCandidate
+	if entry.Status == models.WaitlistOffered && entry.SlotID != nil {
+		var slot models.Slot
+		if err := config.DB.First(&slot, "id = ?", *entry.SlotID).Error; err == nil && slot.HoldExpiresAt != nil {
+			held = &slot
+			candidates = h.waitlistCandidates(c.Request.Context(), slot)
+		}
+	}
+
+	tx := config.DB.Begin()
 
-	if res.Error != nil {
+	if held != nil {
+		released, err := releaseHold(tx, *held)
+		if err == nil && released {
+			err = promoteWaitlist(tx, held.ID, candidates)
+		}
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+	}
+
+	if err := tx.Delete(&entry).Error; err != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
 		return
 	}
 
-	if res.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Waitlist entry not found or unauthorized"})
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
 		return
 	}
 
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// waitlistHoldDuration is how long a waitlisted student has to confirm a held slot: WAITLIST_HOLD_MINUTES (default 120)
+func waitlistHoldDuration() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("WAITLIST_HOLD_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 120
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// waitlistConfirmURL is the frontend page where the student confirms a held slot
+func waitlistConfirmURL(slotID string) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "http://localhost:5173"
+	}
+	return strings.TrimRight(base, "/") + "/slots/" + slotID + "/confirm"
+}
+
+// waitlistCandidate is a student waiting for a freed slot, with what the hold email needs
+type waitlistCandidate struct {
+	Entry    models.WaitlistEntry
+	Contacts bookingContacts
+}
+
+// waitlistCandidates lists the students waiting for the slot's day (in the psychologist's time zone), first in line first.
+// It calls the user service, so it runs before the transaction that frees the slot.
+// If the user service cannot be reached nobody is offered the slot and it stays open for normal booking.
+func (h *BookingHandler) waitlistCandidates(ctx context.Context, slot models.Slot) []waitlistCandidate {
+	if !slot.StartTime.After(time.Now()) {
+		return nil
+	}
+
+	psych, err := h.UserClient.GetUserProfileByID(ctx, &userprofile.GetUserProfileByIDRequest{Id: slot.PsychologistID})
+	if err != nil {
+		log.Printf("Failed to fetch psychologist for waitlist promotion: %v", err)
+		return nil
+	}
+	psychLocation := scheduling.LoadLocation(psych.TimeZone)
+	dateStr := slot.StartTime.In(psychLocation).Format("2006-01-02")
+
+	var entries []models.WaitlistEntry
+	if err := config.DB.
+		Where("psychologist_id = ? AND date = ? AND status = ?", slot.PsychologistID, dateStr, models.WaitlistWaiting).
+		Order("created_at asc").
+		Find(&entries).Error; err != nil {
+		log.Printf("Failed to load waitlist for slot %s: %v", slot.ID, err)
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var studentIDs []string
+	for _, e := range entries {
+		studentIDs = append(studentIDs, e.StudentID)
+	}
+	resp, err := h.UserClient.GetBatchUserProfiles(ctx, &userprofile.GetBatchUserProfilesRequest{Ids: studentIDs})
+	if err != nil {
+		log.Printf("Failed to fetch waitlisted students: %v", err)
+		return nil
+	}
+	profiles := make(map[string]*userprofile.BasicUserProfile)
+	for _, p := range resp.Profiles {
+		profiles[p.Id] = p
+	}
+
+	var candidates []waitlistCandidate
+	for _, e := range entries {
+		p := profiles[e.StudentID]
+		if p == nil || p.Email == "" {
+			// A hold nobody hears about would only block the slot
+			continue
+		}
+		candidates = append(candidates, waitlistCandidate{
+			Entry: e,
+			Contacts: bookingContacts{
+				StudentEmail:    p.Email,
+				StudentLocation: scheduling.LoadLocation(p.TimeZone),
+				PsychName:       psych.FullName,
+				PsychLocation:   psychLocation,
+			},
+		})
+	}
+	return candidates
+}
+
+// promoteWaitlist holds a freed slot for the first candidate who is still waiting and may book it under the
+// no-show rule and the daily and weekly limits, and queues their "waitlist_hold" email.
+// Nothing happens if the slot is not available any more or nobody qualifies.
+func promoteWaitlist(tx *gorm.DB, slotID string, candidates []waitlistCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var slot models.Slot
+	if err := tx.First(&slot, "id = ?", slotID).Error; err != nil {
+		return err
+	}
+	if slot.Status != models.StatusAvailable || !slot.StartTime.After(time.Now()) {
+		return nil
+	}
+
+	for _, candidate := range candidates {
+		// Skip entries that left the line or are being offered another slot right now
+		var entry models.WaitlistEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("id = ? AND status = ?", candidate.Entry.ID, models.WaitlistWaiting).
+			First(&entry).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		restricted, err := noShowRestricted(tx, entry.StudentID)
+		if err != nil {
+			return err
+		}
+		if restricted {
+			continue
+		}
+		limit, err := bookingLimitReached(tx, entry.StudentID, slot.StartTime, candidate.Contacts.StudentLocation)
+		if err != nil {
+			return err
+		}
+		if limit != "" {
+			// They stay in line for a later slot
+			continue
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(waitlistHoldDuration())
+		if expiresAt.After(slot.StartTime) {
+			expiresAt = slot.StartTime
+		}
+
+		res := models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+			models.StatusAvailable, models.StatusReserved, map[string]interface{}{
+				"student_id":      entry.StudentID,
+				"reserved_at":     now,
+				"hold_expires_at": expiresAt,
+				"version":         slot.Version + 1,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil // Someone reserved it in the meantime
+		}
+
+		if err := tx.Model(&entry).Updates(map[string]interface{}{
+			"status":  models.WaitlistOffered,
+			"slot_id": slot.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		loc := candidate.Contacts.StudentLocation
+		return enqueueNotification(tx, clients.NotificationMessage{
+			Type:    "waitlist_hold",
+			ToEmail: candidate.Contacts.StudentEmail,
+			Data: map[string]string{
+				"psychologist_name": candidate.Contacts.PsychName,
+				"datetime":          slot.StartTime.In(loc).Format("Monday, 02 Jan 2006 at 15:04"),
+				"expires_at":        expiresAt.In(loc).Format("02 Jan 2006 at 15:04"),
+				"confirm_url":       waitlistConfirmURL(slot.ID),
+			},
+		})
+	}
+	return nil
+}
+
+// releaseHold frees a reserved slot and, if it was a waitlist hold, drops the student's entry
+// so that promoteWaitlist moves on to the next student in line.
+func releaseHold(tx *gorm.DB, slot models.Slot) (bool, error) {
+	res := models.TransitionSlot(tx.Where("id = ? AND version = ?", slot.ID, slot.Version),
+		models.StatusReserved, models.StatusAvailable, map[string]interface{}{
+			"student_id":            nil,
+			"reserved_at":           nil,
+			"hold_expires_at":       nil,
+			"booking_type":          "",
+			"questionnaire_answers": "",
+			"version":               slot.Version + 1,
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+
+	if slot.HoldExpiresAt != nil && slot.StudentID != nil {
+		err := tx.Where("slot_id = ? AND student_id = ?", slot.ID, *slot.StudentID).
+			Delete(&models.WaitlistEntry{}).Error
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// fulfillHold removes the waitlist entry of a student who confirmed the slot held for them
+func fulfillHold(tx *gorm.DB, slotID, studentID string) error {
+	return tx.Where("slot_id = ? AND student_id = ?", slotID, studentID).Delete(&models.WaitlistEntry{}).Error
+}
+
+// ReleaseExpiredReservation frees a reservation that was not confirmed in time.
+// An expired waitlist hold is passed on to the next student in line.
+func (h *BookingHandler) ReleaseExpiredReservation(ctx context.Context, slot models.Slot) (bool, error) {
+	candidates := h.waitlistCandidates(ctx, slot)
+
+	released := false
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		ok, err := releaseHold(tx, slot)
+		if err != nil || !ok {
+			return err
+		}
+		released = true
+		return promoteWaitlist(tx, slot.ID, candidates)
+	})
+	return released && err == nil, err
+}
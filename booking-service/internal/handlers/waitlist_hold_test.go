@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// waitlistClient answers for psych-1 and the given students, all in UTC
+func waitlistClient(students ...string) *MockUserClient {
+	client := new(MockUserClient)
+	client.On("GetUserProfileByID", mock.Anything, mock.Anything).
+		Return(&userprofile.GetUserProfileByIDResponse{Id: "psych-1", FullName: "Dr. Smith", TimeZone: "UTC"}, nil)
+
+	var profiles []*userprofile.BasicUserProfile
+	for _, id := range students {
+		profiles = append(profiles, &userprofile.BasicUserProfile{Id: id, Email: id + "@test.com", TimeZone: "UTC"})
+	}
+	client.On("GetBatchUserProfiles", mock.Anything, mock.Anything).
+		Return(&userprofile.GetBatchUserProfilesResponse{Profiles: profiles}, nil)
+	return client
+}
+
+func joinWaitlist(t *testing.T, studentID, date string, joined time.Time) {
+	t.Helper()
+	require.NoError(t, config.DB.Create(&models.WaitlistEntry{
+		ID:             "entry-" + studentID,
+		StudentID:      studentID,
+		PsychologistID: "psych-1",
+		Date:           date,
+		CreatedAt:      joined,
+	}).Error)
+}
+
+func TestPromoteWaitlistSkipsStudentsOverLimits(t *testing.T) {
+	setupTestDB(t)
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	date := day.Format("2006-01-02")
+	start := day.Add(10 * time.Hour)
+	createSlot(t, "free", models.StatusAvailable, nil, start)
+
+	// First in line already has a session that day
+	busy, missed := "student-busy", "student-missed"
+	createSlot(t, "busy-booked", models.StatusBooked, &busy, day.Add(14*time.Hour))
+	// Second in line missed too many sessions
+	for i := 1; i <= 3; i++ {
+		createSlot(t, "missed-"+string(rune('0'+i)), models.StatusNoShow, &missed, time.Now().AddDate(0, 0, -i))
+	}
+
+	joined := time.Now().Add(-time.Hour)
+	joinWaitlist(t, busy, date, joined)
+	joinWaitlist(t, missed, date, joined.Add(time.Minute))
+	joinWaitlist(t, "student-next", date, joined.Add(2*time.Minute))
+
+	var slot models.Slot
+	config.DB.First(&slot, "id = ?", "free")
+	h := &BookingHandler{UserClient: waitlistClient(busy, missed, "student-next")}
+	candidates := h.waitlistCandidates(context.Background(), slot)
+	require.Len(t, candidates, 3)
+
+	require.NoError(t, config.DB.Transaction(func(tx *gorm.DB) error {
+		return promoteWaitlist(tx, slot.ID, candidates)
+	}))
+
+	config.DB.First(&slot, "id = ?", "free")
+	assert.Equal(t, models.StatusReserved, slot.Status)
+	require.NotNil(t, slot.StudentID)
+	assert.Equal(t, "student-next", *slot.StudentID)
+	require.NotNil(t, slot.HoldExpiresAt)
+
+	var entries []models.WaitlistEntry
+	config.DB.Order("created_at asc").Find(&entries)
+	require.Len(t, entries, 3)
+	assert.Equal(t, models.WaitlistWaiting, entries[0].Status)
+	assert.Equal(t, models.WaitlistWaiting, entries[1].Status)
+	assert.Equal(t, models.WaitlistOffered, entries[2].Status)
+
+	var queued []models.OutboxMessage
+	config.DB.Find(&queued)
+	require.Len(t, queued, 1)
+	assert.Equal(t, "waitlist_hold", queued[0].Type)
+	assert.Contains(t, queued[0].Payload, "student-next@test.com")
+}
+
+func TestReleaseExpiredReservationPassesHoldOn(t *testing.T) {
+	setupTestDB(t)
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	date := day.Format("2006-01-02")
+
+	first := "student-first"
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, config.DB.Create(&models.Slot{
+		ID:             "held",
+		PsychologistID: "psych-1",
+		StudentID:      &first,
+		StartTime:      day.Add(10 * time.Hour),
+		Duration:       50,
+		Status:         models.StatusReserved,
+		HoldExpiresAt:  &expired,
+	}).Error)
+	joinWaitlist(t, first, date, time.Now().Add(-2*time.Hour))
+	held := "held"
+	config.DB.Model(&models.WaitlistEntry{}).Where("student_id = ?", first).
+		Updates(map[string]interface{}{"status": models.WaitlistOffered, "slot_id": held})
+	joinWaitlist(t, "student-second", date, time.Now().Add(-time.Hour))
+
+	var slot models.Slot
+	config.DB.First(&slot, "id = ?", "held")
+	h := &BookingHandler{UserClient: waitlistClient("student-second")}
+
+	released, err := h.ReleaseExpiredReservation(context.Background(), slot)
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	config.DB.First(&slot, "id = ?", "held")
+	assert.Equal(t, models.StatusReserved, slot.Status)
+	require.NotNil(t, slot.StudentID)
+	assert.Equal(t, "student-second", *slot.StudentID)
+
+	var remaining []models.WaitlistEntry
+	config.DB.Find(&remaining)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "student-second", remaining[0].StudentID)
+	assert.Equal(t, models.WaitlistOffered, remaining[0].Status)
+
+	// The stale copy of the slot cannot be released twice
+	released, err = h.ReleaseExpiredReservation(context.Background(), models.Slot{ID: "held", PsychologistID: "psych-1", StartTime: slot.StartTime})
+	require.NoError(t, err)
+	assert.False(t, released)
+}
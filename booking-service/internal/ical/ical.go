@@ -0,0 +1,52 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/proto/icaltext"
+)
+
+const stampLayout = "20060102T150405Z"
+
+// Event is a single VEVENT. Times are written in UTC, so no VTIMEZONE is needed.
+type Event struct {
+	UID         string // stable per slot, e.g. "<slot id>@kbtu-care"
+	Start       time.Time
+	Duration    time.Duration
+	Summary     string
+	Description string
+	Sequence    int // bumped on every change so calendars replace older copies
+}
+
+// Feed renders a VCALENDAR subscription feed with the given events
+func Feed(name string, events []Event) string {
+	var b strings.Builder
+	icaltext.WriteLine(&b, "BEGIN:VCALENDAR")
+	icaltext.WriteLine(&b, "VERSION:2.0")
+	icaltext.WriteLine(&b, "PRODID:-//KBTU Care//Booking Service//EN")
+	icaltext.WriteLine(&b, "CALSCALE:GREGORIAN")
+	icaltext.WriteLine(&b, "METHOD:PUBLISH")
+	icaltext.WriteLine(&b, "X-WR-CALNAME:"+icaltext.Escape(name))
+	icaltext.WriteLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+
+	now := time.Now().UTC().Format(stampLayout)
+	for _, e := range events {
+		icaltext.WriteLine(&b, "BEGIN:VEVENT")
+		icaltext.WriteLine(&b, "UID:"+e.UID)
+		icaltext.WriteLine(&b, "DTSTAMP:"+now)
+		icaltext.WriteLine(&b, "DTSTART:"+e.Start.UTC().Format(stampLayout))
+		icaltext.WriteLine(&b, "DTEND:"+e.Start.Add(e.Duration).UTC().Format(stampLayout))
+		icaltext.WriteLine(&b, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		icaltext.WriteLine(&b, "SUMMARY:"+icaltext.Escape(e.Summary))
+		if e.Description != "" {
+			icaltext.WriteLine(&b, "DESCRIPTION:"+icaltext.Escape(e.Description))
+		}
+		icaltext.WriteLine(&b, "STATUS:CONFIRMED")
+		icaltext.WriteLine(&b, "END:VEVENT")
+	}
+
+	icaltext.WriteLine(&b, "END:VCALENDAR")
+	return b.String()
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AvailabilityTemplate is a psychologist's recurring weekly schedule.
+// The materializer worker keeps slots generated from it a few weeks ahead.
+type AvailabilityTemplate struct {
+	ID             string        `gorm:"type:uuid;primary_key" json:"id"`
+	PsychologistID string        `gorm:"type:uuid;not null;index" json:"psychologist_id"`
+	Name           string        `gorm:"type:varchar(100)" json:"name"`               // e.g. "Spring semester"
+	Duration       int           `gorm:"default:50" json:"duration"`                  // in minutes
+	StartDate      string        `gorm:"type:varchar(10);not null" json:"start_date"` // "2026-02-20"
+	EndDate        string        `gorm:"type:varchar(10)" json:"end_date,omitempty"`  // empty means open-ended
+	Schedule       []DaySchedule `gorm:"serializer:json;type:jsonb" json:"schedule"`  // weekly pattern
+	TimeZone       string        `gorm:"type:varchar(64)" json:"time_zone"`           // psychologist's zone the start times are in
+	GeneratedUntil string        `gorm:"type:varchar(10)" json:"generated_until"`     // last date slots were generated for
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -12,18 +12,66 @@ type DaySchedule struct {
 	StartTimes []string `json:"start_times" binding:"required"` // ["09:00", "10:00", "14:00"]
 }
 
+type AvailabilityTemplateInput struct {
+	Name      string        `json:"name"`
+	StartDate string        `json:"start_date" binding:"required"` // "2026-02-20"
+	EndDate   string        `json:"end_date"`                      // optional, "2026-06-30"
+	Duration  int           `json:"duration"`                      // 50 (default)
+	Schedule  []DaySchedule `json:"schedule" binding:"required,min=1,dive"`
+}
+
+type TimeOffInput struct {
+	StartDate string `json:"start_date" binding:"required"` // "2026-03-01"
+	EndDate   string `json:"end_date" binding:"required"`   // "2026-03-14", inclusive
+	Reason    string `json:"reason" binding:"omitempty,max=255"`
+	Action    string `json:"action" binding:"omitempty,oneof=cancel reschedule"` // for booked sessions, default "cancel"
+}
+
 type BookSlotInput struct {
-	BookingType string `json:"booking_type" binding:"required,oneof=online offline"`
-	Answers     string `json:"answers"`
-	PhoneNumber string `json:"phone_number" binding:"required"`
+	BookingType    string                    `json:"booking_type" binding:"required,oneof=online offline"`
+	Questionnaires []QuestionnaireSubmission `json:"questionnaires" binding:"dive"`                               // one per active questionnaire, see GET /questionnaires
+	Answers        string                    `json:"answers,omitempty" binding:"max=2000"`                        // Deprecated: free-text answers from before questionnaires, kept when none are active
+	PhoneNumber    string                    `json:"phone_number" binding:"required,e164" example:"+77011234567"` // E.164, also used for SMS notifications
+}
+
+// QuestionnaireSubmission answers one questionnaire version. Answers are keyed by question ID:
+// a string for single choice and text, a list of strings for multi choice, a number for scales.
+type QuestionnaireSubmission struct {
+	QuestionnaireID string                 `json:"questionnaire_id" binding:"required"`
+	Answers         map[string]interface{} `json:"answers" swaggertype:"object"`
+}
+
+// QuestionnaireInput creates a new version of the questionnaire with this code
+type QuestionnaireInput struct {
+	Code      string      `json:"code" binding:"required,max=50" example:"intake"`
+	Title     string      `json:"title" binding:"required,max=255" example:"Intake"`
+	Intro     string      `json:"intro"`
+	Questions []Question  `json:"questions" binding:"required,min=1"`
+	Scored    bool        `json:"scored"`
+	Bands     []ScoreBand `json:"bands"`
+}
+
+// QuestionnaireActiveInput turns asking a questionnaire at booking on or off
+type QuestionnaireActiveInput struct {
+	Active *bool `json:"active" binding:"required"`
 }
 
 type RescheduleInput struct {
 	NewSlotID string `json:"new_slot_id" binding:"required"`
 }
 
-type AddNoteInput struct {
-	Notes string `json:"notes" binding:"required"`
+// SessionNoteInput is a full new revision of a session note. Sections left empty are saved empty.
+type SessionNoteInput struct {
+	PresentingConcern string `json:"presenting_concern" example:"Exam stress, poor sleep for two weeks"`
+	Interventions     string `json:"interventions" example:"Psychoeducation on sleep hygiene, breathing exercise"`
+	Plan              string `json:"plan" example:"Follow-up in one week, sleep diary"`
+	RiskAssessment    string `json:"risk_assessment" example:"No suicidal ideation reported"`
+	RiskLevel         string `json:"risk_level" binding:"required,oneof=none low moderate high" example:"none"`
+}
+
+// SignNoteInput names the revision being signed, so a psychologist never signs text they have not seen
+type SignNoteInput struct {
+	Revision int `json:"revision" binding:"required,min=1" example:"3"`
 }
 
 type JoinWaitlistInput struct {
@@ -35,6 +83,11 @@ type RecommendationInput struct {
 	Recommendations string `json:"recommendations" binding:"required"`
 }
 
+// AttendanceInput records what happened to a booked session once it has started
+type AttendanceInput struct {
+	Status string `json:"status" binding:"required,oneof=completed no_show canceled" example:"completed"`
+}
+
 type RateSessionInput struct {
 	Rating int    `json:"rating" binding:"required,min=1,max=5"` // Must be 1-5
 	Review string `json:"review" binding:"omitempty,max=500"`    // Optional text review
@@ -31,43 +31,57 @@ type CalendarAvailabilityResponse struct {
 }
 
 type PsychologistScheduleResponse struct {
-	ID                   string    `json:"id"`
-	StartTime            time.Time `json:"start_time"`
-	Duration             int       `json:"duration"`
-	Status               string    `json:"status"`
-	BookingType          string    `json:"booking_type"`
-	PsychologistID       string    `json:"psychologist_id"`
-	StudentID            *string   `json:"student_id,omitempty"`
-	StudentName          string    `json:"student_name"`
-	QuestionnaireAnswers string    `json:"questionnaire_answers,omitempty"`
-	PhoneNumber          string    `json:"phone_number,omitempty"`
+	ID                   string                `json:"id"`
+	StartTime            time.Time             `json:"start_time"`
+	Duration             int                   `json:"duration"`
+	Status               string                `json:"status"`
+	BookingType          string                `json:"booking_type"`
+	PsychologistID       string                `json:"psychologist_id"`
+	StudentID            *string               `json:"student_id,omitempty"`
+	StudentName          string                `json:"student_name"`
+	Questionnaires       []QuestionnaireResult `json:"questionnaires,omitempty"`
+	Scores               []QuestionnaireScore  `json:"scores,omitempty"`                // PHQ-9, GAD-7 and other scored questionnaires
+	QuestionnaireAnswers string                `json:"questionnaire_answers,omitempty"` // free text of bookings made before questionnaires
+	PhoneNumber          string                `json:"phone_number,omitempty"`
 }
 
 type StudentAppointmentResponse struct {
-	ID                     string    `json:"id"`
-	StartTime              time.Time `json:"start_time"`
-	Duration               int       `json:"duration"`
-	BookingType            string    `json:"booking_type"`
-	PsychologistID         string    `json:"psychologist_id"`
-	PsychologistName       string    `json:"psychologist_name"`
-	QuestionnaireAnswers   string    `json:"questionnaire_answers,omitempty"`
-	StudentRecommendations string    `json:"student_recommendations,omitempty"`
+	ID                     string                `json:"id"`
+	StartTime              time.Time             `json:"start_time"`
+	Duration               int                   `json:"duration"`
+	BookingType            string                `json:"booking_type"`
+	PsychologistID         string                `json:"psychologist_id"`
+	PsychologistName       string                `json:"psychologist_name"`
+	Status                 string                `json:"status"` // booked, completed, no_show or canceled
+	Questionnaires         []QuestionnaireResult `json:"questionnaires,omitempty"`
+	QuestionnaireAnswers   string                `json:"questionnaire_answers,omitempty"` // free text of bookings made before questionnaires
+	StudentRecommendations string                `json:"student_recommendations,omitempty"`
 }
 
 type StudentHistoryResponse struct {
-	SlotID               string    `json:"slot_id"`
-	StartTime            time.Time `json:"start_time"`
-	BookingType          string    `json:"booking_type"`
-	QuestionnaireAnswers string    `json:"questionnaire_answers"`
-	PsychologistNotes    string    `json:"psychologist_notes"`
+	SlotID               string                       `json:"slot_id"`
+	StartTime            time.Time                    `json:"start_time"`
+	BookingType          string                       `json:"booking_type"`
+	Status               string                       `json:"status"` // booked (not marked yet), completed or no_show
+	Questionnaires       []QuestionnaireResult        `json:"questionnaires,omitempty"`
+	Scores               []QuestionnaireScore         `json:"scores,omitempty"`
+	QuestionnaireAnswers string                       `json:"questionnaire_answers,omitempty"` // free text of bookings made before questionnaires
+	PsychologistNotes    string                       `json:"psychologist_notes,omitempty"`    // free-text notes written before structured notes existed
+	Note                 *SessionNoteRevisionResponse `json:"note,omitempty"`                  // latest signed revision
+	NoteRevisions        int                          `json:"note_revisions,omitempty"`        // all revisions, fetch earlier ones by number
+	HasUnsignedDraft     bool                         `json:"has_unsigned_draft"`              // a note exists but is not signed yet
 }
 
 type WaitlistResponse struct {
-	ID               string    `json:"id"`
-	PsychologistID   string    `json:"psychologist_id"`
-	PsychologistName string    `json:"psychologist_name"`
-	Date             string    `json:"date"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               string     `json:"id"`
+	PsychologistID   string     `json:"psychologist_id"`
+	PsychologistName string     `json:"psychologist_name"`
+	Date             string     `json:"date"`
+	Status           string     `json:"status"`                    // waiting or offered
+	Position         int        `json:"position,omitempty"`        // 1 = next in line, only while waiting
+	SlotID           *string    `json:"slot_id,omitempty"`         // held slot to confirm, only while offered
+	HoldExpiresAt    *time.Time `json:"hold_expires_at,omitempty"` // confirm before this time
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 type AnonymousReviewResponse struct {
@@ -88,10 +102,84 @@ type AdminReviewResponse struct {
 }
 
 type PsychologistStats struct {
-	TotalSessions     int64   `json:"total_sessions"`
+	TotalSessions     int64   `json:"total_sessions"` // completed sessions
 	UpcomingSessions  int64   `json:"upcoming_sessions"`
+	NoShows           int64   `json:"no_shows"`
+	NoShowRate        float64 `json:"no_show_rate"`      // % of attended-or-missed sessions the student missed
+	UnmarkedSessions  int64   `json:"unmarked_sessions"` // past booked sessions whose attendance is not recorded yet
 	AverageRating     float64 `json:"average_rating"`
 	CancellationRate  float64 `json:"cancellation_rate"`   // % of bookings cancelled by students
 	MostCommonBooking string  `json:"most_common_booking"` // "online" or "offline"
 	SessionsThisMonth int64   `json:"sessions_this_month"`
 }
+
+// TimeOffResponse summarizes what creating a time-off block did to the schedule
+type TimeOffResponse struct {
+	TimeOff          TimeOff `json:"time_off"`
+	RemovedSlots     int     `json:"removed_slots" example:"12"`    // free or reserved slots deleted
+	CanceledBookings int     `json:"canceled_bookings" example:"2"` // booked sessions canceled
+	RescheduleOffers int     `json:"reschedule_offers" example:"1"` // students of canceled or removed reserved slots who got a suggested slot
+}
+
+// CalendarFeedResponse carries the secret subscription URL. Anyone with the URL can read the calendar.
+type CalendarFeedResponse struct {
+	URL string `json:"url" example:"http://localhost:8080/api/v1/calendar/ics/3f9a.ics"`
+}
+
+// NoteKeyRotationResponse reports the outcome of re-encrypting clinical text with the active master key
+type NoteKeyRotationResponse struct {
+	ActiveKeyID string `json:"active_key_id" example:"2026-10"`
+	Reencrypted int    `json:"reencrypted" example:"42"` // values rewritten by this run
+}
+
+// SessionNoteRevisionResponse is the decrypted content of one note revision
+type SessionNoteRevisionResponse struct {
+	Revision          int       `json:"revision" example:"2"`
+	PresentingConcern string    `json:"presenting_concern"`
+	Interventions     string    `json:"interventions"`
+	Plan              string    `json:"plan"`
+	RiskAssessment    string    `json:"risk_assessment"`
+	RiskLevel         string    `json:"risk_level" example:"low"`
+	AuthorID          string    `json:"author_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	Signed            bool      `json:"signed"` // true for the revision the note was signed at
+}
+
+// SessionNoteResponse is a note with the content of one of its revisions
+type SessionNoteResponse struct {
+	ID             string                      `json:"id"`
+	SlotID         string                      `json:"slot_id"`
+	StudentID      string                      `json:"student_id"`
+	LatestRevision int                         `json:"latest_revision" example:"2"`
+	SignedRevision *int                        `json:"signed_revision,omitempty"`
+	SignedAt       *time.Time                  `json:"signed_at,omitempty"`
+	Revision       SessionNoteRevisionResponse `json:"revision"`
+}
+
+// SessionNoteRevisionSummary lists a revision without its content
+type SessionNoteRevisionSummary struct {
+	Revision  int       `json:"revision" example:"1"`
+	RiskLevel string    `json:"risk_level" example:"none"`
+	AuthorID  string    `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Signed    bool      `json:"signed"`
+}
+
+// StudentMoodResponse is the mood timeline a student shared with the psychologist
+type StudentMoodResponse struct {
+	StudentID   string             `json:"student_id"`
+	From        string             `json:"from" example:"2026-02-01"`
+	To          string             `json:"to" example:"2026-03-02"`
+	NotesShared bool               `json:"notes_shared"` // journal notes are included only if the student chose to share them
+	Entries     []StudentMoodEntry `json:"entries"`
+}
+
+type StudentMoodEntry struct {
+	ID       string   `json:"id"`
+	LoggedAt string   `json:"logged_at" example:"2026-03-02T21:15:00+05:00"`
+	Date     string   `json:"date" example:"2026-03-02"`
+	Mood     string   `json:"mood" example:"Anxiously"`
+	Score    int      `json:"score" example:"2"`
+	Note     string   `json:"note,omitempty"`
+	Tags     []string `json:"tags,omitempty" example:"sleep,exams"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CalendarFeed is a user's secret iCal subscription. Only the token hash is stored,
+// the URL is shown once when the feed is created or rotated.
+type CalendarFeed struct {
+	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Role      string    `gorm:"type:varchar(20);not null" json:"role"` // student or psychologist, decides which slots are served
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex" json:"-"` // sha256 hex
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// NoteAccessLog records one read of decrypted clinical data. The table is append-only:
+// a database trigger rejects updates and deletes.
+type NoteAccessLog struct {
+	ID         string    `gorm:"type:uuid;primary_key" json:"id"`
+	ReaderID   string    `gorm:"type:uuid;not null;index" json:"reader_id"`
+	ReaderRole string    `gorm:"type:varchar(20);not null" json:"reader_role"` // student, psychologist or admin
+	StudentID  string    `gorm:"type:uuid;not null;index" json:"student_id"`   // whose data was read
+	SlotID     string    `gorm:"type:uuid;not null;index" json:"slot_id"`
+	Fields     string    `gorm:"not null" json:"fields" example:"psychologist_notes,questionnaire_answers"`
+	Revision   int       `json:"revision,omitempty"` // session note revision, when a note was read
+	Endpoint   string    `json:"endpoint" example:"/api/v1/psychologist/students/:student_id/history"`
+	AccessedAt time.Time `gorm:"not null;index" json:"accessed_at"`
+}
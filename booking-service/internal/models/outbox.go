@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"
+)
+
+// OutboxMessage is a RabbitMQ message persisted in the same transaction as the
+// slot change that caused it. The relay worker publishes it later.
+type OutboxMessage struct {
+	ID      string `gorm:"type:uuid;primary_key" json:"id"`
+	Queue   string `gorm:"type:varchar(100);not null" json:"queue"`              // e.g. "notifications_queue"
+	Type    string `gorm:"type:varchar(100);not null" json:"type"`               // e.g. "booking_confirmation"
+	Payload string `gorm:"type:text;not null" json:"payload"`                    // JSON body as it will be published
+	Status  string `gorm:"default:'pending';index:idx_outbox_due" json:"status"` // pending, sent, failed
+
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_due" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
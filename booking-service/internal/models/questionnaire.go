@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Question types
+const (
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+	QuestionScale        = "scale"
+	QuestionText         = "text"
+)
+
+// Questionnaire is one version of a question set shown when a student confirms a booking.
+// Versions are never edited: a change is saved as a new version with the same code, so
+// stored answers always refer to the exact questions that were asked. The latest active
+// version of every code is asked at booking.
+type Questionnaire struct {
+	ID        string      `gorm:"type:uuid;primary_key" json:"id"`
+	Code      string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_questionnaire_version" json:"code" example:"phq9"`
+	Version   int         `gorm:"not null;uniqueIndex:idx_questionnaire_version" json:"version" example:"1"`
+	Title     string      `gorm:"not null" json:"title" example:"PHQ-9"`
+	Intro     string      `gorm:"type:text" json:"intro,omitempty" example:"Over the last 2 weeks, how often have you been bothered by the following problems?"`
+	Questions []Question  `gorm:"serializer:json;type:jsonb" json:"questions"`
+	Scored    bool        `gorm:"default:false" json:"scored"`                       // sum of option scores and scale values
+	Bands     []ScoreBand `gorm:"serializer:json;type:jsonb" json:"bands,omitempty"` // severity labels for scored questionnaires
+	Active    bool        `gorm:"default:true;index" json:"active"`                  // inactive codes are not asked at booking
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Question is one item of a questionnaire. Options are used by choice questions, Min and Max by scales.
+type Question struct {
+	ID       string           `json:"id" example:"q1"`
+	Text     string           `json:"text" example:"Little interest or pleasure in doing things"`
+	Type     string           `json:"type" example:"single_choice"` // single_choice, multi_choice, scale, text
+	Required bool             `json:"required"`
+	Options  []QuestionOption `json:"options,omitempty"`
+	Min      int              `json:"min,omitempty"`
+	Max      int              `json:"max,omitempty"`
+}
+
+type QuestionOption struct {
+	Value string `json:"value" example:"several_days"`
+	Label string `json:"label" example:"Several days"`
+	Score int    `json:"score" example:"1"`
+}
+
+// ScoreBand labels scores from Min up to the next band's Min
+type ScoreBand struct {
+	Min   int    `json:"min" example:"10"`
+	Label string `json:"label" example:"moderate"`
+}
+
+// QuestionnaireResult is a completed questionnaire as stored with the booking. It keeps
+// the question texts so the answers stay readable whatever happens to the definition.
+type QuestionnaireResult struct {
+	QuestionnaireID string   `json:"questionnaire_id"`
+	Code            string   `json:"code" example:"phq9"`
+	Version         int      `json:"version" example:"1"`
+	Title           string   `json:"title" example:"PHQ-9"`
+	Answers         []Answer `json:"answers"`
+	Score           *int     `json:"score,omitempty" example:"12"`
+	Severity        string   `json:"severity,omitempty" example:"moderate"`
+}
+
+// Answer holds a string for single choice and text, a list of strings for multi choice and a number for scales
+type Answer struct {
+	QuestionID string      `json:"question_id" example:"q1"`
+	Question   string      `json:"question"`
+	Type       string      `json:"type"`
+	Value      interface{} `json:"value" swaggertype:"object"`
+}
+
+// QuestionnaireScore is the outcome of a scored questionnaire
+type QuestionnaireScore struct {
+	Code     string `json:"code" example:"gad7"`
+	Title    string `json:"title" example:"GAD-7"`
+	Score    int    `json:"score" example:"6"`
+	Severity string `json:"severity,omitempty" example:"mild"`
+}
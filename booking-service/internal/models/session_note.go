@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Risk levels a psychologist can record in a session note
+const (
+	RiskNone     = "none"
+	RiskLow      = "low"
+	RiskModerate = "moderate"
+	RiskHigh     = "high"
+)
+
+// SessionNote is the psychologist's clinical record of one session. The content lives in
+// SessionNoteRevision rows: every save adds a revision and existing ones are never changed.
+// Signing locks the note, after which no revisions can be added.
+type SessionNote struct {
+	ID             string     `gorm:"type:uuid;primary_key" json:"id"`
+	SlotID         string     `gorm:"type:uuid;not null;uniqueIndex" json:"slot_id"`
+	PsychologistID string     `gorm:"type:uuid;not null;index" json:"psychologist_id"`
+	StudentID      string     `gorm:"type:uuid;not null;index" json:"student_id"`
+	LatestRevision int        `gorm:"not null" json:"latest_revision"`
+	SignedRevision *int       `json:"signed_revision,omitempty"` // nil while the note is a draft
+	SignedAt       *time.Time `json:"signed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// SessionNoteRevision is one immutable version of a note. The text sections are stored sealed by notecrypt.
+type SessionNoteRevision struct {
+	ID                string    `gorm:"type:uuid;primary_key" json:"-"`
+	NoteID            string    `gorm:"type:uuid;not null;uniqueIndex:idx_note_revision" json:"-"`
+	Revision          int       `gorm:"not null;uniqueIndex:idx_note_revision" json:"revision"`
+	PresentingConcern string    `gorm:"type:text" json:"-"`
+	Interventions     string    `gorm:"type:text" json:"-"`
+	Plan              string    `gorm:"type:text" json:"-"`
+	RiskAssessment    string    `gorm:"type:text" json:"-"`
+	RiskLevel         string    `gorm:"type:varchar(20);not null" json:"risk_level"` // none, low, moderate, high
+	AuthorID          string    `gorm:"type:uuid;not null" json:"author_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
@@ -1,32 +1,84 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 const (
 	StatusAvailable = "available"
 	StatusReserved  = "reserved"
 	StatusBooked    = "booked"
+	StatusCompleted = "completed" // the session took place
+	StatusNoShow    = "no_show"   // the student did not come
+	StatusCanceled  = "canceled"  // the session was called off and the time is not offered again
 )
 
+// slotTransitions lists where a slot may go from each state. Completed and canceled are final.
+// A booked slot canceled ahead of time goes back to available so another student can take it;
+// the canceled state is for sessions whose time cannot be reused (time off, called off on the day).
+// An available slot is booked directly only when an existing booking is moved onto it.
+var slotTransitions = map[string][]string{
+	StatusAvailable: {StatusReserved, StatusBooked},
+	StatusReserved:  {StatusAvailable, StatusBooked},
+	StatusBooked:    {StatusAvailable, StatusCompleted, StatusNoShow, StatusCanceled},
+	StatusNoShow:    {StatusCompleted}, // correction when the student turned up late
+}
+
+// CanTransition reports whether a slot in state from may be moved to state to
+func CanTransition(from, to string) bool {
+	for _, next := range slotTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrTransition is returned by TransitionSlot for a move slotTransitions does not allow
+var ErrTransition = errors.New("slot status change not allowed")
+
+// TransitionSlot moves the slots selected by db (e.g. tx.Where("id = ? AND version = ?", ...))
+// from one status to another, writing the other updates with it. Every status change goes
+// through here so the state machine is enforced in one place. Only rows still in from are
+// touched, so a concurrent change shows up as zero RowsAffected.
+func TransitionSlot(db *gorm.DB, from, to string, updates map[string]interface{}) *gorm.DB {
+	db = db.Model(&Slot{})
+	if !CanTransition(from, to) {
+		db.AddError(fmt.Errorf("%w: %s to %s", ErrTransition, from, to))
+		return db
+	}
+
+	values := map[string]interface{}{"status": to}
+	for column, value := range updates {
+		values[column] = value
+	}
+	return db.Where("status = ?", from).Updates(values)
+}
+
 type Slot struct {
 	ID             string    `gorm:"type:uuid;primary_key" json:"id"`
 	PsychologistID string    `gorm:"type:uuid;not null;uniqueIndex:idx_psych_time" json:"psychologist_id"`
 	StartTime      time.Time `gorm:"not null;uniqueIndex:idx_psych_time" json:"start_time"`
 	Duration       int       `gorm:"default:50" json:"duration"` // in minutes
 
-	Status     string     `gorm:"default:'available';index" json:"status"`  // available, reserved, booked
-	ReservedAt *time.Time `json:"reserved_at,omitempty"`                    // When the 20-min lock started
-	StudentID  *string    `gorm:"type:uuid;default:null" json:"student_id"` // Nullable
+	TemplateID *string `gorm:"type:uuid;index" json:"template_id,omitempty"` // Set when generated from an AvailabilityTemplate
 
-	BookingType string `gorm:"default:null" json:"booking_type"` // "online" or "offline"
-
-	QuestionnaireAnswers string `gorm:"type:text" json:"questionnaire_answers"`
+	Status        string     `gorm:"default:'available';index" json:"status"`  // available, reserved, booked, completed, no_show, canceled
+	ReservedAt    *time.Time `json:"reserved_at,omitempty"`                    // When the 20-min lock started
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`                // Set instead of the 20-min lock when the slot is held for a waitlisted student
+	StudentID     *string    `gorm:"type:uuid;default:null" json:"student_id"` // Nullable
 
-	PsychologistNotes string `gorm:"type:text" json:"psychologist_notes,omitempty"`
+	BookingType string `gorm:"default:null" json:"booking_type"` // "online" or "offline"
 
-	StudentRecommendations string `gorm:"type:text" json:"student_recommendations,omitempty"`
+	// Clinical text is stored sealed by the notecrypt package and never serialized with the slot.
+	// Handlers decrypt it explicitly, which records the read in NoteAccessLog.
+	QuestionnaireAnswers   string `gorm:"type:text" json:"-"`
+	PsychologistNotes      string `gorm:"type:text" json:"-"`
+	StudentRecommendations string `gorm:"type:text" json:"-"`
 
 	Rating int    `gorm:"default:0" json:"rating,omitempty"` // 1 to 5 stars (0 means unrated)
 	Review string `gorm:"type:text" json:"review,omitempty"` // Written feedback
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var statuses = []string{StatusAvailable, StatusReserved, StatusBooked, StatusCompleted, StatusNoShow, StatusCanceled}
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{StatusAvailable, StatusReserved}: true,
+		{StatusAvailable, StatusBooked}:   true, // reschedule
+		{StatusReserved, StatusAvailable}: true,
+		{StatusReserved, StatusBooked}:    true,
+		{StatusBooked, StatusAvailable}:   true,
+		{StatusBooked, StatusCompleted}:   true,
+		{StatusBooked, StatusNoShow}:      true,
+		{StatusBooked, StatusCanceled}:    true,
+		{StatusNoShow, StatusCompleted}:   true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			assert.Equal(t, allowed[[2]string{from, to}], CanTransition(from, to), "%s -> %s", from, to)
+		}
+	}
+	assert.False(t, CanTransition("", StatusBooked))
+}
+
+func TestTransitionSlot(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Slot{}))
+
+	student := "student-1"
+	db.Create(&Slot{ID: "slot-1", PsychologistID: "psych-1", StartTime: time.Now(), Status: StatusBooked, StudentID: &student, Version: 3})
+
+	load := func() Slot {
+		var slot Slot
+		db.First(&slot, "id = ?", "slot-1")
+		return slot
+	}
+
+	// Not in the table: nothing is written
+	res := TransitionSlot(db.Where("id = ?", "slot-1"), StatusBooked, StatusReserved, nil)
+	assert.ErrorIs(t, res.Error, ErrTransition)
+	assert.Equal(t, StatusBooked, load().Status)
+
+	// The slot is not in the expected state any more
+	res = TransitionSlot(db.Where("id = ?", "slot-1"), StatusReserved, StatusAvailable, nil)
+	assert.NoError(t, res.Error)
+	assert.Zero(t, res.RowsAffected)
+
+	// Allowed, with the other updates written alongside
+	res = TransitionSlot(db.Where("id = ? AND version = ?", "slot-1", 3), StatusBooked, StatusNoShow, map[string]interface{}{"version": 4})
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(1), res.RowsAffected)
+	slot := load()
+	assert.Equal(t, StatusNoShow, slot.Status)
+	assert.Equal(t, 4, slot.Version)
+
+	// Completed is final
+	res = TransitionSlot(db.Where("id = ?", "slot-1"), StatusNoShow, StatusCompleted, nil)
+	require.NoError(t, res.Error)
+	res = TransitionSlot(db.Where("id = ?", "slot-1"), StatusCompleted, StatusAvailable, nil)
+	assert.ErrorIs(t, res.Error, ErrTransition)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// What happens to booked sessions that fall into a time-off block
+const (
+	TimeOffCancel     = "cancel"     // cancel and notify the student
+	TimeOffReschedule = "reschedule" // cancel and offer the nearest free slot
+)
+
+// TimeOff blocks a psychologist's calendar from StartDate to EndDate (inclusive),
+// as days in the psychologist's time zone. No slots are generated inside it.
+type TimeOff struct {
+	ID             string `gorm:"type:uuid;primary_key" json:"id"`
+	PsychologistID string `gorm:"type:uuid;not null;index" json:"psychologist_id"`
+	StartDate      string `gorm:"type:varchar(10);not null" json:"start_date"` // "2026-03-01"
+	EndDate        string `gorm:"type:varchar(10);not null" json:"end_date"`   // "2026-03-14"
+	Reason         string `gorm:"type:varchar(255)" json:"reason"`             // e.g. "Vacation", never shown to students
+	Action         string `gorm:"type:varchar(20)" json:"action"`              // cancel or reschedule
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,12 +2,21 @@ package models
 
 import "time"
 
+const (
+	WaitlistWaiting = "waiting" // in line for the date
+	WaitlistOffered = "offered" // currently holds a freed slot, see SlotID
+)
+
+// WaitlistEntry is a student's place in line for a psychologist's day. The line is ordered by CreatedAt.
 type WaitlistEntry struct {
 	ID             string `gorm:"type:uuid;primary_key" json:"id"`
 	StudentID      string `gorm:"type:uuid;not null;uniqueIndex:idx_waitlist" json:"student_id"`
-	PsychologistID string `gorm:"type:uuid;not null;uniqueIndex:idx_waitlist" json:"psychologist_id"`
+	PsychologistID string `gorm:"type:uuid;not null;uniqueIndex:idx_waitlist;index:idx_waitlist_line" json:"psychologist_id"`
+
+	Date string `gorm:"not null;uniqueIndex:idx_waitlist;index:idx_waitlist_line" json:"date"`
 
-	Date string `gorm:"not null;uniqueIndex:idx_waitlist" json:"date"`
+	Status string  `gorm:"default:'waiting'" json:"status"`    // waiting, offered
+	SlotID *string `gorm:"type:uuid" json:"slot_id,omitempty"` // The held slot while offered
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `gorm:"index:idx_waitlist_line" json:"created_at"`
 }
@@ -0,0 +1,270 @@
+// Package notecrypt encrypts clinical text (legacy notes, questionnaire answers and
+// recommendations on slots, and the sections of session note revisions) before it is
+// written to the database.
+//
+// Every value gets its own random data key (DEK). The text is sealed with the DEK using
+// AES-256-GCM, and the DEK is sealed with a master key (KEK) that never enters the database.
+// Master keys are read from NOTES_KEYS_DIR, one file per key containing 32 random bytes in
+// base64 (e.g. `head -c 32 /dev/urandom | base64 > keys/notes/2026-10.key`). The file name
+// without extension is the key ID. New values are sealed with NOTES_ACTIVE_KID, or with the
+// newest key by name if it is not set. If the directory holds no keys on first start, one
+// named after the current month is generated into it, so it must be writable until then.
+// Back that key up: without it every note sealed with it is lost.
+//
+// Rotation:
+//  1. Add the new key file, point NOTES_ACTIVE_KID at it and restart booking-service.
+//  2. Call POST /admin/notes/rotate-key to re-encrypt every stored value with the new key.
+//  3. Once it reports nothing left to re-encrypt, delete the old key file.
+package notecrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields that are stored encrypted. The names are the slot column names and are bound into
+// the ciphertext, so a value cannot be copied from one field into another.
+const (
+	FieldNotes           = "psychologist_notes"
+	FieldAnswers         = "questionnaire_answers"
+	FieldRecommendations = "student_recommendations"
+)
+
+// Sections of a session note revision, stored encrypted in the same way
+const (
+	SectionPresentingConcern = "presenting_concern"
+	SectionInterventions     = "interventions"
+	SectionPlan              = "plan"
+	SectionRiskAssessment    = "risk_assessment"
+)
+
+// Fields lists every encrypted slot column
+var Fields = []string{FieldNotes, FieldAnswers, FieldRecommendations}
+
+// Sections lists every encrypted session note revision column
+var Sections = []string{SectionPresentingConcern, SectionInterventions, SectionPlan, SectionRiskAssessment}
+
+// prefix marks a sealed value: enc:v1:<kid>:<sealed DEK>:<sealed text>
+const prefix = "enc:v1:"
+
+type keyRing struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+var (
+	ring     *keyRing
+	ringErr  error
+	ringOnce sync.Once
+)
+
+// LoadKeys reads the master keys. It is safe to call more than once.
+func LoadKeys() error {
+	ringOnce.Do(func() {
+		ring, ringErr = loadKeyRing(os.Getenv("NOTES_KEYS_DIR"), os.Getenv("NOTES_ACTIVE_KID"))
+	})
+	return ringErr
+}
+
+// ActiveKeyID is the key new values are sealed with
+func ActiveKeyID() string {
+	if LoadKeys() != nil {
+		return ""
+	}
+	return ring.activeKID
+}
+
+func loadKeyRing(dir, activeKID string) (*keyRing, error) {
+	if dir == "" {
+		// Unlike signing keys, a throwaway key would make stored notes unreadable after a restart
+		return nil, errors.New("NOTES_KEYS_DIR is not set")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.key"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		path, err := generateKeyFile(dir, time.Now().UTC().Format("2006-01"))
+		if err != nil {
+			return nil, fmt.Errorf("no *.key files found in %s and generating one failed: %w", dir, err)
+		}
+		log.Printf("No note encryption keys found. Generated %s", path)
+		files = []string{path}
+	}
+
+	r := &keyRing{keys: make(map[string][]byte)}
+	var newest string
+	for _, f := range files {
+		kid := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		if strings.Contains(kid, ":") {
+			return nil, fmt.Errorf("key ID %q must not contain ':'", kid)
+		}
+
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%s must hold 32 bytes in base64", f)
+		}
+
+		r.keys[kid] = key
+		newest = kid
+	}
+
+	if activeKID == "" {
+		activeKID = newest
+	}
+	if _, ok := r.keys[activeKID]; !ok {
+		return nil, fmt.Errorf("NOTES_ACTIVE_KID %q not found in %s", activeKID, dir)
+	}
+	r.activeKID = activeKID
+	return r, nil
+}
+
+// generateKeyFile writes 32 random bytes in base64 to dir/<kid>.key, readable only by this user
+func generateKeyFile(dir, kid string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, kid+".key")
+	// O_EXCL so a key that already exists is never overwritten
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// Seal encrypts the text of the given field with a fresh data key. Empty text stays empty.
+func Seal(field, text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	if err := LoadKeys(); err != nil {
+		return "", err
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+
+	wrapped, err := gcmSeal(ring.keys[ring.activeKID], dek, []byte(ring.activeKID))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := gcmSeal(dek, []byte(text), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + ring.activeKID + ":" +
+		base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value written by Seal. Values stored before encryption was introduced
+// are returned unchanged until the next key rotation encrypts them.
+func Open(field, value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	if err := LoadKeys(); err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	kek, ok := ring.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("master key %q is not loaded", parts[0])
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	dek, err := gcmOpen(kek, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	text, err := gcmOpen(dek, sealed, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return string(text), nil
+}
+
+// IsCurrent reports whether the value is empty or already sealed with the active key
+func IsCurrent(value string) bool {
+	if value == "" {
+		return true
+	}
+	return LoadKeys() == nil && strings.HasPrefix(value, prefix+ring.activeKID+":")
+}
+
+// gcmSeal returns nonce || ciphertext
+func gcmSeal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func gcmOpen(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,163 @@
+package notecrypt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// useKeys loads dir as the process-wide key ring, bypassing the sync.Once
+func useKeys(t *testing.T, dir, activeKID string) {
+	t.Helper()
+	r, err := loadKeyRing(dir, activeKID)
+	require.NoError(t, err)
+	ring, ringErr = r, nil
+	ringOnce.Do(func() {})
+}
+
+func newKey(t *testing.T, dir, kid string) {
+	t.Helper()
+	_, err := generateKeyFile(dir, kid)
+	require.NoError(t, err)
+}
+
+func TestLoadKeyRingGeneratesKeyInEmptyDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "notes")
+
+	r, err := loadKeyRing(dir, "")
+	require.NoError(t, err)
+	assert.Len(t, r.keys, 1)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.key"))
+	require.Len(t, files, 1)
+	info, _ := os.Stat(files[0])
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The next start reuses the generated key instead of making another
+	again, err := loadKeyRing(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, r.keys, again.keys)
+}
+
+func TestLoadKeyRingRequiresDir(t *testing.T) {
+	_, err := loadKeyRing("", "")
+	assert.Error(t, err)
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	newKey(t, dir, "2026-01")
+	useKeys(t, dir, "")
+
+	sealed, err := Seal(FieldNotes, "Discussed exam anxiety")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, prefix+"2026-01:"))
+	assert.NotContains(t, sealed, "exam")
+
+	text, err := Open(FieldNotes, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "Discussed exam anxiety", text)
+
+	// Every value gets its own data key
+	again, _ := Seal(FieldNotes, "Discussed exam anxiety")
+	assert.NotEqual(t, sealed, again)
+
+	empty, err := Seal(FieldNotes, "")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// Plain text from before encryption passes through
+	legacy, err := Open(FieldNotes, "written before encryption")
+	require.NoError(t, err)
+	assert.Equal(t, "written before encryption", legacy)
+}
+
+func TestOpenFailsWithWrongKey(t *testing.T) {
+	dir := t.TempDir()
+	newKey(t, dir, "2026-01")
+	useKeys(t, dir, "")
+
+	sealed, err := Seal(FieldNotes, "secret")
+	require.NoError(t, err)
+
+	// The value is bound to its field
+	_, err = Open(FieldRecommendations, sealed)
+	assert.Error(t, err)
+
+	// A different key under the same ID cannot unwrap the data key
+	other := t.TempDir()
+	newKey(t, other, "2026-01")
+	useKeys(t, other, "")
+	_, err = Open(FieldNotes, sealed)
+	assert.Error(t, err)
+
+	// A key that is not loaded at all
+	missing := t.TempDir()
+	newKey(t, missing, "2026-02")
+	useKeys(t, missing, "")
+	_, err = Open(FieldNotes, sealed)
+	assert.ErrorContains(t, err, "2026-01")
+}
+
+func TestRotateReencryptsWithActiveKey(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Slot{}, &models.SessionNoteRevision{}))
+
+	dir := t.TempDir()
+	newKey(t, dir, "2026-01")
+	useKeys(t, dir, "")
+
+	notes, _ := Seal(FieldNotes, "old notes")
+	plan, _ := Seal(SectionPlan, "weekly sessions")
+	db.Create(&models.Slot{ID: "slot-1", PsychologistID: "psych-1", StartTime: time.Now(), PsychologistNotes: notes})
+	db.Create(&models.Slot{ID: "slot-2", PsychologistID: "psych-1", StartTime: time.Now().Add(time.Hour), StudentRecommendations: "plain legacy text"})
+	db.Create(&models.SessionNoteRevision{ID: "rev-1", NoteID: "note-1", Revision: 1, Plan: plan, RiskLevel: "none", AuthorID: "psych-1"})
+
+	newKey(t, dir, "2026-02")
+	useKeys(t, dir, "2026-02")
+
+	n, err := Rotate(db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	var slot1, slot2 models.Slot
+	db.First(&slot1, "id = ?", "slot-1")
+	db.First(&slot2, "id = ?", "slot-2")
+	var rev models.SessionNoteRevision
+	db.First(&rev, "id = ?", "rev-1")
+
+	for field, value := range map[string]string{
+		FieldNotes:           slot1.PsychologistNotes,
+		FieldRecommendations: slot2.StudentRecommendations,
+		SectionPlan:          rev.Plan,
+	} {
+		assert.True(t, strings.HasPrefix(value, prefix+"2026-02:"), field)
+	}
+
+	text, _ := Open(FieldNotes, slot1.PsychologistNotes)
+	assert.Equal(t, "old notes", text)
+	text, _ = Open(FieldRecommendations, slot2.StudentRecommendations)
+	assert.Equal(t, "plain legacy text", text)
+	text, _ = Open(SectionPlan, rev.Plan)
+	assert.Equal(t, "weekly sessions", text)
+
+	// Nothing left to do, and the old key can go
+	n, err = Rotate(db)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.key")))
+	useKeys(t, dir, "")
+	text, err = Open(FieldNotes, slot1.PsychologistNotes)
+	require.NoError(t, err)
+	assert.Equal(t, "old notes", text)
+}
@@ -0,0 +1,117 @@
+package notecrypt
+
+import (
+	"fmt"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// FieldOf points at the slot attribute stored in the given encrypted column
+func FieldOf(slot *models.Slot, field string) *string {
+	switch field {
+	case FieldNotes:
+		return &slot.PsychologistNotes
+	case FieldAnswers:
+		return &slot.QuestionnaireAnswers
+	case FieldRecommendations:
+		return &slot.StudentRecommendations
+	}
+	return nil
+}
+
+// SectionOf points at the revision attribute stored in the given encrypted column
+func SectionOf(rev *models.SessionNoteRevision, section string) *string {
+	switch section {
+	case SectionPresentingConcern:
+		return &rev.PresentingConcern
+	case SectionInterventions:
+		return &rev.Interventions
+	case SectionPlan:
+		return &rev.Plan
+	case SectionRiskAssessment:
+		return &rev.RiskAssessment
+	}
+	return nil
+}
+
+// Rotate re-encrypts every stored value that is not sealed with the active key, including
+// plain text written before encryption was introduced, and returns how many were rewritten.
+// Values are only decrypted in memory to be sealed again, so no access is logged. Note
+// revisions get new ciphertext too; their content does not change.
+func Rotate(db *gorm.DB) (int, error) {
+	if err := LoadKeys(); err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, field := range Fields {
+		n, err := rotateColumn(db, &models.Slot{}, field)
+		rewritten += n
+		if err != nil {
+			return rewritten, err
+		}
+	}
+	for _, section := range Sections {
+		n, err := rotateColumn(db, &models.SessionNoteRevision{}, section)
+		rewritten += n
+		if err != nil {
+			return rewritten, err
+		}
+	}
+	return rewritten, nil
+}
+
+// rotateColumn walks the table in primary key order, so rows rewritten along the way are not visited twice
+func rotateColumn(db *gorm.DB, model interface{}, column string) (int, error) {
+	type row struct {
+		ID    string
+		Value string
+	}
+
+	rewritten := 0
+	lastID := ""
+	for {
+		query := db.Model(model).
+			Select("id, " + column + " AS value").
+			Where(column + " <> ''").
+			Order("id").
+			Limit(200)
+		if lastID != "" {
+			query = query.Where("id > ?", lastID)
+		}
+
+		var rows []row
+		if err := query.Scan(&rows).Error; err != nil {
+			return rewritten, err
+		}
+		if len(rows) == 0 {
+			return rewritten, nil
+		}
+
+		for _, r := range rows {
+			if IsCurrent(r.Value) {
+				continue
+			}
+
+			text, err := Open(column, r.Value)
+			if err != nil {
+				return rewritten, fmt.Errorf("%s of %s: %w", column, r.ID, err)
+			}
+			sealed, err := Seal(column, text)
+			if err != nil {
+				return rewritten, err
+			}
+
+			// Only replace the value that was read. If it changed meanwhile, the new one is already current.
+			res := db.Model(model).
+				Where("id = ? AND "+column+" = ?", r.ID, r.Value).
+				UpdateColumn(column, sealed)
+			if res.Error != nil {
+				return rewritten, res.Error
+			}
+			rewritten += int(res.RowsAffected)
+		}
+		lastID = rows[len(rows)-1].ID
+	}
+}
@@ -0,0 +1,262 @@
+// Package questionnaire validates and scores the questionnaires students fill in when confirming a booking.
+package questionnaire
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+const maxTextAnswer = 2000
+
+// Active returns the latest version of every questionnaire that is currently asked at booking
+func Active(db *gorm.DB) ([]models.Questionnaire, error) {
+	var all []models.Questionnaire
+	if err := db.Where("active = ?", true).Order("code asc, version desc").Find(&all).Error; err != nil {
+		return nil, err
+	}
+
+	latest := make([]models.Questionnaire, 0, len(all))
+	for _, q := range all {
+		if len(latest) == 0 || latest[len(latest)-1].Code != q.Code {
+			latest = append(latest, q)
+		}
+	}
+	return latest, nil
+}
+
+// Evaluate checks the answers against the questions and scores them if the questionnaire is scored
+func Evaluate(q models.Questionnaire, answers map[string]interface{}) (models.QuestionnaireResult, error) {
+	result := models.QuestionnaireResult{
+		QuestionnaireID: q.ID,
+		Code:            q.Code,
+		Version:         q.Version,
+		Title:           q.Title,
+	}
+
+	known := make(map[string]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		known[question.ID] = true
+	}
+	for id := range answers {
+		if !known[id] {
+			return result, fmt.Errorf("%s: unknown question %q", q.Title, id)
+		}
+	}
+
+	score := 0
+	for _, question := range q.Questions {
+		raw, ok := answers[question.ID]
+		if !ok || raw == nil || raw == "" {
+			if question.Required {
+				return result, fmt.Errorf("%s: question %q is required", q.Title, question.ID)
+			}
+			continue
+		}
+
+		value, points, err := checkAnswer(question, raw)
+		if err != nil {
+			return result, fmt.Errorf("%s: question %q: %w", q.Title, question.ID, err)
+		}
+		// An empty selection or whitespace-only text is no answer at all
+		empty := false
+		switch v := value.(type) {
+		case []string:
+			empty = len(v) == 0
+		case string:
+			empty = v == ""
+		}
+		if empty {
+			if question.Required {
+				return result, fmt.Errorf("%s: question %q is required", q.Title, question.ID)
+			}
+			continue
+		}
+
+		score += points
+		result.Answers = append(result.Answers, models.Answer{
+			QuestionID: question.ID,
+			Question:   question.Text,
+			Type:       question.Type,
+			Value:      value,
+		})
+	}
+
+	if q.Scored {
+		result.Score = &score
+		result.Severity = severity(q.Bands, score)
+	}
+	return result, nil
+}
+
+// checkAnswer validates one answer and returns it in its stored form with the points it scores
+func checkAnswer(question models.Question, raw interface{}) (interface{}, int, error) {
+	switch question.Type {
+	case models.QuestionSingleChoice:
+		choice, ok := raw.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("expected one option value")
+		}
+		option, ok := findOption(question, choice)
+		if !ok {
+			return nil, 0, fmt.Errorf("%q is not an option", choice)
+		}
+		return choice, option.Score, nil
+
+	case models.QuestionMultiChoice:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, 0, fmt.Errorf("expected a list of option values")
+		}
+		chosen := make([]string, 0, len(items))
+		seen := make(map[string]bool)
+		points := 0
+		for _, item := range items {
+			choice, ok := item.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("expected a list of option values")
+			}
+			option, ok := findOption(question, choice)
+			if !ok {
+				return nil, 0, fmt.Errorf("%q is not an option", choice)
+			}
+			if seen[choice] {
+				continue
+			}
+			seen[choice] = true
+			chosen = append(chosen, choice)
+			points += option.Score
+		}
+		return chosen, points, nil
+
+	case models.QuestionScale:
+		number, ok := raw.(float64)
+		if !ok || number != math.Trunc(number) {
+			return nil, 0, fmt.Errorf("expected a whole number")
+		}
+		value := int(number)
+		if value < question.Min || value > question.Max {
+			return nil, 0, fmt.Errorf("must be between %d and %d", question.Min, question.Max)
+		}
+		return value, value, nil
+
+	case models.QuestionText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("expected text")
+		}
+		text = strings.TrimSpace(text)
+		if len(text) > maxTextAnswer {
+			return nil, 0, fmt.Errorf("must be at most %d characters", maxTextAnswer)
+		}
+		return text, 0, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported question type %q", question.Type)
+}
+
+func findOption(question models.Question, value string) (models.QuestionOption, bool) {
+	for _, option := range question.Options {
+		if option.Value == value {
+			return option, true
+		}
+	}
+	return models.QuestionOption{}, false
+}
+
+// severity returns the label of the highest band the score reaches
+func severity(bands []models.ScoreBand, score int) string {
+	label := ""
+	for _, band := range bands {
+		if score >= band.Min {
+			label = band.Label
+		}
+	}
+	return label
+}
+
+// Validate checks a new questionnaire definition before it is stored
+func Validate(q models.Questionnaire) error {
+	ids := make(map[string]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		if question.ID == "" || question.Text == "" {
+			return fmt.Errorf("every question needs an id and a text")
+		}
+		if ids[question.ID] {
+			return fmt.Errorf("question id %q is used twice", question.ID)
+		}
+		ids[question.ID] = true
+
+		switch question.Type {
+		case models.QuestionSingleChoice, models.QuestionMultiChoice:
+			if len(question.Options) < 2 {
+				return fmt.Errorf("question %q needs at least two options", question.ID)
+			}
+			values := make(map[string]bool, len(question.Options))
+			for _, option := range question.Options {
+				if option.Value == "" || values[option.Value] {
+					return fmt.Errorf("question %q has an empty or repeated option value", question.ID)
+				}
+				values[option.Value] = true
+			}
+		case models.QuestionScale:
+			if question.Min >= question.Max {
+				return fmt.Errorf("question %q needs min below max", question.ID)
+			}
+		case models.QuestionText:
+		default:
+			return fmt.Errorf("question %q has unknown type %q", question.ID, question.Type)
+		}
+	}
+
+	for i := 1; i < len(q.Bands); i++ {
+		if q.Bands[i].Min <= q.Bands[i-1].Min {
+			return fmt.Errorf("bands must be in increasing order of min")
+		}
+	}
+	if len(q.Bands) > 0 && !q.Scored {
+		return fmt.Errorf("bands only apply to scored questionnaires")
+	}
+	return nil
+}
+
+// Encode serializes completed questionnaires for Slot.QuestionnaireAnswers
+func Encode(results []models.QuestionnaireResult) (string, error) {
+	if len(results) == 0 {
+		return "", nil
+	}
+	body, err := json.Marshal(results)
+	return string(body), err
+}
+
+// Decode reads Slot.QuestionnaireAnswers. Bookings made before questionnaires existed hold
+// free text, which is returned as legacy.
+func Decode(stored string) (results []models.QuestionnaireResult, legacy string) {
+	if stored == "" {
+		return nil, ""
+	}
+	if strings.HasPrefix(stored, "[") && json.Unmarshal([]byte(stored), &results) == nil {
+		return results, ""
+	}
+	return nil, stored
+}
+
+// Scores lists the outcome of every scored questionnaire in the results
+func Scores(results []models.QuestionnaireResult) []models.QuestionnaireScore {
+	var scores []models.QuestionnaireScore
+	for _, r := range results {
+		if r.Score == nil {
+			continue
+		}
+		scores = append(scores, models.QuestionnaireScore{
+			Code:     r.Code,
+			Title:    r.Title,
+			Score:    *r.Score,
+			Severity: r.Severity,
+		})
+	}
+	return scores
+}
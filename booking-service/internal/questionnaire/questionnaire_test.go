@@ -0,0 +1,195 @@
+package questionnaire
+
+import (
+	"testing"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var yesNo = []models.QuestionOption{
+	{Value: "yes", Label: "Yes", Score: 1},
+	{Value: "no", Label: "No", Score: 0},
+}
+
+var intake = models.Questionnaire{
+	ID:    "intake-1",
+	Code:  "intake",
+	Title: "Intake",
+	Questions: []models.Question{
+		{ID: "reason", Text: "What brings you here?", Type: models.QuestionText, Required: true},
+		{ID: "first", Text: "First visit?", Type: models.QuestionSingleChoice, Required: true, Options: yesNo},
+		{ID: "topics", Text: "Topics", Type: models.QuestionMultiChoice, Options: []models.QuestionOption{
+			{Value: "sleep", Label: "Sleep", Score: 2},
+			{Value: "exams", Label: "Exams", Score: 3},
+		}},
+		{ID: "stress", Text: "Stress level", Type: models.QuestionScale, Min: 0, Max: 10},
+		{ID: "other", Text: "Anything else?", Type: models.QuestionText},
+	},
+	Scored: true,
+	Bands:  []models.ScoreBand{{Min: 0, Label: "low"}, {Min: 8, Label: "high"}},
+}
+
+func TestEvaluateScoresAnswers(t *testing.T) {
+	result, err := Evaluate(intake, map[string]interface{}{
+		"reason": "  exam stress  ",
+		"first":  "yes",
+		"topics": []interface{}{"sleep", "exams", "sleep"},
+		"stress": float64(4),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "intake", result.Code)
+	require.NotNil(t, result.Score)
+	assert.Equal(t, 1+2+3+4, *result.Score)
+	assert.Equal(t, "high", result.Severity)
+
+	require.Len(t, result.Answers, 4)
+	assert.Equal(t, "exam stress", result.Answers[0].Value)
+	assert.Equal(t, []string{"sleep", "exams"}, result.Answers[2].Value)
+	assert.Equal(t, 4, result.Answers[3].Value)
+}
+
+func TestEvaluateRejectsInvalidAnswers(t *testing.T) {
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{"reason": "exam stress", "first": "no"}
+	}
+
+	tests := []struct {
+		name   string
+		change func(map[string]interface{})
+	}{
+		{"missing required text", func(a map[string]interface{}) { delete(a, "reason") }},
+		{"empty required text", func(a map[string]interface{}) { a["reason"] = "" }},
+		{"whitespace-only required text", func(a map[string]interface{}) { a["reason"] = " \n\t " }},
+		{"missing required choice", func(a map[string]interface{}) { a["first"] = nil }},
+		{"unknown option", func(a map[string]interface{}) { a["first"] = "maybe" }},
+		{"choice given as a list", func(a map[string]interface{}) { a["first"] = []interface{}{"yes"} }},
+		{"unknown multi option", func(a map[string]interface{}) { a["topics"] = []interface{}{"money"} }},
+		{"scale out of range", func(a map[string]interface{}) { a["stress"] = float64(11) }},
+		{"scale not whole", func(a map[string]interface{}) { a["stress"] = 2.5 }},
+		{"scale as text", func(a map[string]interface{}) { a["stress"] = "5" }},
+		{"text too long", func(a map[string]interface{}) { a["other"] = string(make([]byte, maxTextAnswer+1)) + "x" }},
+		{"unknown question", func(a map[string]interface{}) { a["q99"] = "yes" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := valid()
+			tt.change(answers)
+			_, err := Evaluate(intake, answers)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEvaluateSkipsEmptyOptionalAnswers(t *testing.T) {
+	result, err := Evaluate(intake, map[string]interface{}{
+		"reason": "exam stress",
+		"first":  "no",
+		"topics": []interface{}{},
+		"other":  "   ",
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Answers, 2)
+	assert.Equal(t, 0, *result.Score)
+	assert.Equal(t, "low", result.Severity)
+}
+
+func TestEvaluateUnscored(t *testing.T) {
+	q := intake
+	q.Scored = false
+	q.Bands = nil
+
+	result, err := Evaluate(q, map[string]interface{}{"reason": "exam stress", "first": "yes"})
+	require.NoError(t, err)
+	assert.Nil(t, result.Score)
+	assert.Empty(t, result.Severity)
+}
+
+func TestValidate(t *testing.T) {
+	question := func(id, typ string) models.Question {
+		return models.Question{ID: id, Text: "Question " + id, Type: typ, Options: yesNo, Min: 1, Max: 5}
+	}
+
+	tests := []struct {
+		name  string
+		q     models.Questionnaire
+		valid bool
+	}{
+		{"standard screeners", screeners[0], true},
+		{"every type", models.Questionnaire{Questions: []models.Question{
+			question("a", models.QuestionSingleChoice),
+			question("b", models.QuestionMultiChoice),
+			question("c", models.QuestionScale),
+			question("d", models.QuestionText),
+		}}, true},
+		{"question without text", models.Questionnaire{Questions: []models.Question{{ID: "a", Type: models.QuestionText}}}, false},
+		{"repeated id", models.Questionnaire{Questions: []models.Question{question("a", models.QuestionText), question("a", models.QuestionText)}}, false},
+		{"one option", models.Questionnaire{Questions: []models.Question{{ID: "a", Text: "A", Type: models.QuestionSingleChoice, Options: yesNo[:1]}}}, false},
+		{"repeated option", models.Questionnaire{Questions: []models.Question{{ID: "a", Text: "A", Type: models.QuestionMultiChoice, Options: []models.QuestionOption{yesNo[0], yesNo[0]}}}}, false},
+		{"scale min not below max", models.Questionnaire{Questions: []models.Question{{ID: "a", Text: "A", Type: models.QuestionScale, Min: 5, Max: 5}}}, false},
+		{"unknown type", models.Questionnaire{Questions: []models.Question{question("a", "slider")}}, false},
+		{"bands out of order", models.Questionnaire{Scored: true, Questions: []models.Question{question("a", models.QuestionScale)},
+			Bands: []models.ScoreBand{{Min: 5, Label: "high"}, {Min: 0, Label: "low"}}}, false},
+		{"bands on unscored", models.Questionnaire{Questions: []models.Question{question("a", models.QuestionScale)},
+			Bands: []models.ScoreBand{{Min: 0, Label: "low"}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.q)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	twelve := 12
+	results := []models.QuestionnaireResult{
+		{Code: "phq9", Title: "PHQ-9", Score: &twelve, Severity: "moderate"},
+		{Code: "intake", Title: "Intake"},
+	}
+
+	assert.Equal(t, []models.QuestionnaireScore{{Code: "phq9", Title: "PHQ-9", Score: 12, Severity: "moderate"}}, Scores(results))
+	assert.Nil(t, Scores(nil))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	twelve := 12
+	results := []models.QuestionnaireResult{{Code: "phq9", Title: "PHQ-9", Score: &twelve}}
+
+	encoded, err := Encode(results)
+	require.NoError(t, err)
+	decoded, legacy := Decode(encoded)
+	assert.Empty(t, legacy)
+	assert.Equal(t, "phq9", decoded[0].Code)
+
+	decoded, legacy = Decode("Feeling anxious before exams")
+	assert.Nil(t, decoded)
+	assert.Equal(t, "Feeling anxious before exams", legacy)
+}
+
+func TestSeedScreenersInactive(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Questionnaire{}))
+
+	require.NoError(t, SeedScreeners(db))
+	require.NoError(t, SeedScreeners(db))
+
+	var count int64
+	db.Model(&models.Questionnaire{}).Count(&count)
+	assert.Equal(t, int64(len(screeners)), count)
+
+	active, err := Active(db)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
@@ -0,0 +1,113 @@
+package questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// frequency is the answer scale shared by PHQ-9 and GAD-7
+var frequency = []models.QuestionOption{
+	{Value: "not_at_all", Label: "Not at all", Score: 0},
+	{Value: "several_days", Label: "Several days", Score: 1},
+	{Value: "more_than_half", Label: "More than half the days", Score: 2},
+	{Value: "nearly_every_day", Label: "Nearly every day", Score: 3},
+}
+
+func frequencyItems(texts ...string) []models.Question {
+	questions := make([]models.Question, 0, len(texts))
+	for i, text := range texts {
+		questions = append(questions, models.Question{
+			ID:       fmt.Sprintf("q%d", i+1),
+			Text:     text,
+			Type:     models.QuestionSingleChoice,
+			Required: true,
+			Options:  frequency,
+		})
+	}
+	return questions
+}
+
+// Standard screeners, created as version 1 on first start. They are seeded inactive so clients
+// that do not send questionnaires yet can still book; an admin turns them on with
+// PUT /admin/questionnaires/{code}/active once the frontend asks them.
+var screeners = []models.Questionnaire{
+	{
+		Code:  "phq9",
+		Title: "PHQ-9",
+		Intro: "Over the last 2 weeks, how often have you been bothered by any of the following problems?",
+		Questions: frequencyItems(
+			"Little interest or pleasure in doing things",
+			"Feeling down, depressed, or hopeless",
+			"Trouble falling or staying asleep, or sleeping too much",
+			"Feeling tired or having little energy",
+			"Poor appetite or overeating",
+			"Feeling bad about yourself, or that you are a failure or have let yourself or your family down",
+			"Trouble concentrating on things, such as reading or watching television",
+			"Moving or speaking so slowly that other people could have noticed, or being so fidgety or restless that you have been moving around a lot more than usual",
+			"Thoughts that you would be better off dead, or of hurting yourself in some way",
+		),
+		Scored: true,
+		Bands: []models.ScoreBand{
+			{Min: 0, Label: "minimal"},
+			{Min: 5, Label: "mild"},
+			{Min: 10, Label: "moderate"},
+			{Min: 15, Label: "moderately_severe"},
+			{Min: 20, Label: "severe"},
+		},
+	},
+	{
+		Code:  "gad7",
+		Title: "GAD-7",
+		Intro: "Over the last 2 weeks, how often have you been bothered by the following problems?",
+		Questions: frequencyItems(
+			"Feeling nervous, anxious, or on edge",
+			"Not being able to stop or control worrying",
+			"Worrying too much about different things",
+			"Trouble relaxing",
+			"Being so restless that it is hard to sit still",
+			"Becoming easily annoyed or irritable",
+			"Feeling afraid, as if something awful might happen",
+		),
+		Scored: true,
+		Bands: []models.ScoreBand{
+			{Min: 0, Label: "minimal"},
+			{Min: 5, Label: "mild"},
+			{Min: 10, Label: "moderate"},
+			{Min: 15, Label: "severe"},
+		},
+	},
+}
+
+// SeedScreeners stores the standard screeners unless a questionnaire with the same code exists
+func SeedScreeners(db *gorm.DB) error {
+	for _, s := range screeners {
+		var existing models.Questionnaire
+		err := db.Where("code = ?", s.Code).First(&existing).Error
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		s.ID = uuid.NewString()
+		s.Version = 1
+		// Active has a column default of true, which a false in Create would not override
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&s).Error; err != nil {
+				return err
+			}
+			return tx.Model(&s).Update("active", false).Error
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Seeded questionnaire %s (inactive)", s.Code)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package risk checks completed questionnaires for answers that need a psychologist's attention
+// straight away. Matches are sent to user-service, which raises and escalates the alerts.
+package risk
+
+import (
+	"fmt"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+)
+
+// Severities, in increasing order of urgency
+const (
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Rule matches one questionnaire code. A rule with a QuestionID triggers on any of the listed
+// answers to that question, otherwise it triggers when the total score reaches MinScore.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    string
+	Code        string
+	QuestionID  string
+	Answers     []string
+	MinScore    int
+}
+
+// Rules are checked against every confirmed booking
+var Rules = []Rule{
+	{
+		Name:        "phq9_self_harm",
+		Description: "Reported thoughts of self-harm or of being better off dead (PHQ-9 item 9)",
+		Severity:    SeverityCritical,
+		Code:        "phq9",
+		QuestionID:  "q9",
+		Answers:     []string{"several_days", "more_than_half", "nearly_every_day"},
+	},
+	{
+		Name:        "phq9_severe",
+		Description: "Severe depression score on PHQ-9",
+		Severity:    SeverityHigh,
+		Code:        "phq9",
+		MinScore:    20,
+	},
+	{
+		Name:        "gad7_severe",
+		Description: "Severe anxiety score on GAD-7",
+		Severity:    SeverityHigh,
+		Code:        "gad7",
+		MinScore:    15,
+	},
+}
+
+// Match is a rule that triggered, with a short non-clinical summary of why
+type Match struct {
+	Rule   Rule
+	Detail string
+}
+
+// Evaluate returns the rules triggered by the completed questionnaires
+func Evaluate(results []models.QuestionnaireResult) []Match {
+	var matches []Match
+	for _, rule := range Rules {
+		for _, result := range results {
+			if result.Code != rule.Code {
+				continue
+			}
+			if detail, ok := check(rule, result); ok {
+				matches = append(matches, Match{Rule: rule, Detail: detail})
+			}
+		}
+	}
+	return matches
+}
+
+func check(rule Rule, result models.QuestionnaireResult) (string, bool) {
+	if rule.QuestionID == "" {
+		if result.Score == nil || *result.Score < rule.MinScore {
+			return "", false
+		}
+		return fmt.Sprintf("%s score %d (%s)", result.Title, *result.Score, result.Severity), true
+	}
+
+	for _, answer := range result.Answers {
+		if answer.QuestionID != rule.QuestionID {
+			continue
+		}
+		value, _ := answer.Value.(string)
+		for _, trigger := range rule.Answers {
+			if value == trigger {
+				return fmt.Sprintf("%s %s answered %q", result.Title, answer.QuestionID, value), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func score(n int) *int { return &n }
+
+func phq9(q9 string, total int) models.QuestionnaireResult {
+	return models.QuestionnaireResult{
+		Code:    "phq9",
+		Title:   "PHQ-9",
+		Answers: []models.Answer{{QuestionID: "q1", Value: "not_at_all"}, {QuestionID: "q9", Value: q9}},
+		Score:   score(total),
+	}
+}
+
+func gad7(total int) models.QuestionnaireResult {
+	return models.QuestionnaireResult{Code: "gad7", Title: "GAD-7", Score: score(total)}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []models.QuestionnaireResult
+		rules   []string
+	}{
+		{"nothing answered", nil, nil},
+		{"mild phq9 without self-harm", []models.QuestionnaireResult{phq9("not_at_all", 8)}, nil},
+		{"self-harm several days", []models.QuestionnaireResult{phq9("several_days", 6)}, []string{"phq9_self_harm"}},
+		{"self-harm nearly every day", []models.QuestionnaireResult{phq9("nearly_every_day", 9)}, []string{"phq9_self_harm"}},
+		{"phq9 just below severe", []models.QuestionnaireResult{phq9("not_at_all", 19)}, nil},
+		{"phq9 severe", []models.QuestionnaireResult{phq9("not_at_all", 20)}, []string{"phq9_severe"}},
+		{"phq9 severe with self-harm", []models.QuestionnaireResult{phq9("more_than_half", 24)}, []string{"phq9_self_harm", "phq9_severe"}},
+		{"gad7 just below severe", []models.QuestionnaireResult{gad7(14)}, nil},
+		{"gad7 severe", []models.QuestionnaireResult{gad7(15)}, []string{"gad7_severe"}},
+		{"gad7 without a score", []models.QuestionnaireResult{{Code: "gad7", Title: "GAD-7"}}, nil},
+		{"severe score on another questionnaire", []models.QuestionnaireResult{{Code: "intake", Score: score(30)}}, nil},
+		{"self-harm answer given as a number", []models.QuestionnaireResult{{
+			Code:    "phq9",
+			Answers: []models.Answer{{QuestionID: "q9", Value: 3}},
+		}}, nil},
+		{"phq9 and gad7 together", []models.QuestionnaireResult{phq9("several_days", 21), gad7(18)}, []string{"phq9_self_harm", "phq9_severe", "gad7_severe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []string
+			for _, m := range Evaluate(tt.results) {
+				rules = append(rules, m.Rule.Name)
+				assert.NotEmpty(t, m.Detail)
+			}
+			assert.Equal(t, tt.rules, rules)
+		})
+	}
+}
+
+func TestEvaluateDetail(t *testing.T) {
+	matches := Evaluate([]models.QuestionnaireResult{phq9("nearly_every_day", 22)})
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, `PHQ-9 q9 answered "nearly_every_day"`, matches[0].Detail)
+		assert.Equal(t, SeverityCritical, matches[0].Rule.Severity)
+		assert.Contains(t, matches[1].Detail, "score 22")
+	}
+}
@@ -0,0 +1,214 @@
+package scheduling
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const dateLayout = "2006-01-02"
+
+// DefaultTimeZone is used for users who have not picked a time zone yet
+const DefaultTimeZone = "Asia/Almaty"
+
+// LoadLocation resolves an IANA time zone name, falling back to DefaultTimeZone
+func LoadLocation(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// StartOfDay returns midnight of t's calendar day in t's location
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// GenerateSlots expands a weekly pattern into slots for every day between from and to (inclusive).
+// Start times such as "09:00" are wall-clock times in loc. Invalid time strings are skipped.
+func GenerateSlots(psychID string, from, to time.Time, schedule []models.DaySchedule, duration int, templateID *string, loc *time.Location) []models.Slot {
+	scheduleMap := make(map[int][]string)
+	for _, day := range schedule {
+		scheduleMap[day.DayOfWeek] = day.StartTimes
+	}
+
+	var slots []models.Slot
+	for currentDate := from; !currentDate.After(to); currentDate = currentDate.AddDate(0, 0, 1) {
+		// Get the weekday of the current iteration (0=Sun, 1=Mon...)
+		times, exists := scheduleMap[int(currentDate.Weekday())]
+		if !exists {
+			continue
+		}
+
+		for _, timeStr := range times {
+			slotTime, err := combineDateAndTime(currentDate, timeStr, loc)
+			if err != nil {
+				continue // Skip invalid time formats
+			}
+
+			slots = append(slots, models.Slot{
+				ID:             uuid.NewString(),
+				PsychologistID: psychID,
+				StartTime:      slotTime,
+				Duration:       duration,
+				Status:         models.StatusAvailable,
+				TemplateID:     templateID,
+				Version:        1,
+			})
+		}
+	}
+	return slots
+}
+
+// Horizon is the last day templates are materialized for: SLOT_GENERATION_WEEKS (default 8) weeks from today
+func Horizon() time.Time {
+	weeks, err := strconv.Atoi(os.Getenv("SLOT_GENERATION_WEEKS"))
+	if err != nil || weeks <= 0 {
+		weeks = 8
+	}
+	return today(time.UTC).AddDate(0, 0, 7*weeks)
+}
+
+// MaterializeTemplate creates the template's slots from the day after GeneratedUntil up to horizon
+// and moves GeneratedUntil forward. Days that were already generated are never revisited, so slots
+// the psychologist deleted by hand do not come back.
+func MaterializeTemplate(tx *gorm.DB, tpl *models.AvailabilityTemplate, horizon time.Time) (int, error) {
+	from, err := time.Parse(dateLayout, tpl.StartDate)
+	if err != nil {
+		return 0, err
+	}
+	loc := LoadLocation(tpl.TimeZone)
+	if t := today(loc); from.Before(t) {
+		from = t
+	}
+	if tpl.GeneratedUntil != "" {
+		if generated, err := time.Parse(dateLayout, tpl.GeneratedUntil); err == nil && !generated.Before(from) {
+			from = generated.AddDate(0, 0, 1)
+		}
+	}
+
+	to := horizon
+	if tpl.EndDate != "" {
+		end, err := time.Parse(dateLayout, tpl.EndDate)
+		if err != nil {
+			return 0, err
+		}
+		if end.Before(to) {
+			to = end
+		}
+	}
+	if from.After(to) {
+		return 0, nil
+	}
+
+	count := 0
+	var slots []models.Slot
+	now := time.Now()
+	for _, slot := range GenerateSlots(tpl.PsychologistID, from, to, tpl.Schedule, tpl.Duration, &tpl.ID, loc) {
+		if slot.StartTime.After(now) {
+			slots = append(slots, slot)
+		}
+	}
+	slots, err = ExcludeTimeOff(tx, tpl.PsychologistID, slots, loc)
+	if err != nil {
+		return 0, err
+	}
+	if len(slots) > 0 {
+		// if a slot already exists for this psych at this time, skip it
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&slots)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		count = int(result.RowsAffected)
+	}
+
+	tpl.GeneratedUntil = to.Format(dateLayout)
+	if err := tx.Model(tpl).Update("generated_until", tpl.GeneratedUntil).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ResetTemplate removes the template's future slots that nobody has reserved or booked,
+// so that MaterializeTemplate can regenerate them from the current pattern.
+func ResetTemplate(tx *gorm.DB, tpl *models.AvailabilityTemplate) error {
+	err := tx.Where("template_id = ? AND status = ? AND start_time > ?", tpl.ID, models.StatusAvailable, time.Now()).
+		Delete(&models.Slot{}).Error
+	if err != nil {
+		return err
+	}
+	tpl.GeneratedUntil = ""
+	return tx.Model(tpl).Update("generated_until", "").Error
+}
+
+// Helper to merge date "2026-01-01" with time "14:30" in the given location
+func combineDateAndTime(date time.Time, timeStr string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse("15:04", timeStr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Return new date with specific hour/minute
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, loc), nil
+}
+
+// today returns the current calendar date in loc, as midnight UTC like the parsed "YYYY-MM-DD" dates
+func today(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TimeOffRange returns the instants [start, end) covered by a time-off block in loc
+func TimeOffRange(off models.TimeOff, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(dateLayout, off.StartDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation(dateLayout, off.EndDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// ExcludeTimeOff drops slots whose day (in loc) falls into one of the psychologist's time-off blocks
+func ExcludeTimeOff(db *gorm.DB, psychID string, slots []models.Slot, loc *time.Location) ([]models.Slot, error) {
+	if len(slots) == 0 {
+		return slots, nil
+	}
+
+	first := slots[0].StartTime.In(loc).Format(dateLayout)
+	last := slots[len(slots)-1].StartTime.In(loc).Format(dateLayout)
+
+	var blocks []models.TimeOff
+	err := db.Where("psychologist_id = ? AND start_date <= ? AND end_date >= ?", psychID, last, first).
+		Find(&blocks).Error
+	if err != nil || len(blocks) == 0 {
+		return slots, err
+	}
+
+	var kept []models.Slot
+	for _, slot := range slots {
+		day := slot.StartTime.In(loc).Format(dateLayout)
+		blocked := false
+		for _, b := range blocks {
+			if day >= b.StartDate && day <= b.EndDate {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			kept = append(kept, slot)
+		}
+	}
+	return kept, nil
+}
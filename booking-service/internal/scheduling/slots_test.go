@@ -0,0 +1,167 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func date(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse(dateLayout, s)
+	require.NoError(t, err)
+	return d
+}
+
+func TestGenerateSlotsKeepsWallClockAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Clocks go forward on Sunday 29 March 2026
+	schedule := []models.DaySchedule{
+		{DayOfWeek: int(time.Saturday), StartTimes: []string{"09:00"}},
+		{DayOfWeek: int(time.Sunday), StartTimes: []string{"09:00"}},
+		{DayOfWeek: int(time.Monday), StartTimes: []string{"09:00"}},
+	}
+	slots := GenerateSlots("psych-1", date(t, "2026-03-28"), date(t, "2026-03-30"), schedule, 50, nil, berlin)
+	require.Len(t, slots, 3)
+
+	for _, s := range slots {
+		assert.Equal(t, "09:00", s.StartTime.In(berlin).Format("15:04"))
+	}
+	assert.Equal(t, "08:00", slots[0].StartTime.UTC().Format("15:04"), "CET")
+	assert.Equal(t, "07:00", slots[1].StartTime.UTC().Format("15:04"), "CEST")
+	assert.Equal(t, 23*time.Hour, slots[1].StartTime.Sub(slots[0].StartTime))
+}
+
+func TestGenerateSlotsInTimeZone(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+	templateID := "template-1"
+
+	// Wednesday 4 March 2026; an early slot is still on the same calendar day locally
+	schedule := []models.DaySchedule{{DayOfWeek: int(time.Wednesday), StartTimes: []string{"01:00", "9h30", "25:00", "18:00"}}}
+	slots := GenerateSlots("psych-1", date(t, "2026-03-04"), date(t, "2026-03-04"), schedule, 50, &templateID, almaty)
+	require.Len(t, slots, 2, "invalid times are skipped")
+
+	assert.Equal(t, "2026-03-04 01:00", slots[0].StartTime.In(almaty).Format("2006-01-02 15:04"))
+	assert.Equal(t, "2026-03-03 20:00", slots[0].StartTime.UTC().Format("2006-01-02 15:04"), "the day before in UTC")
+	assert.Equal(t, "18:00", slots[1].StartTime.In(almaty).Format("15:04"))
+	for _, s := range slots {
+		assert.Equal(t, "psych-1", s.PsychologistID)
+		assert.Equal(t, models.StatusAvailable, s.Status)
+		assert.Equal(t, 50, s.Duration)
+		assert.Equal(t, &templateID, s.TemplateID)
+		assert.NotEmpty(t, s.ID)
+	}
+}
+
+func TestGenerateSlotsBounds(t *testing.T) {
+	// Every day of one week, Sunday included
+	var schedule []models.DaySchedule
+	for day := 0; day < 7; day++ {
+		schedule = append(schedule, models.DaySchedule{DayOfWeek: day, StartTimes: []string{"10:00"}})
+	}
+	slots := GenerateSlots("psych-1", date(t, "2026-03-02"), date(t, "2026-03-08"), schedule, 50, nil, time.UTC)
+	require.Len(t, slots, 7, "both ends are inclusive")
+	assert.Equal(t, time.Monday, slots[0].StartTime.Weekday())
+	assert.Equal(t, time.Sunday, slots[6].StartTime.Weekday())
+
+	assert.Empty(t, GenerateSlots("psych-1", date(t, "2026-03-08"), date(t, "2026-03-02"), schedule, 50, nil, time.UTC))
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Slot{}, &models.AvailabilityTemplate{}, &models.TimeOff{}))
+	return db
+}
+
+func TestMaterializeTemplate(t *testing.T) {
+	db := setupTestDB(t)
+	almaty := LoadLocation("Asia/Almaty")
+
+	// Two weeks starting tomorrow, so none of the slots is in the past
+	start := today(almaty).AddDate(0, 0, 1)
+	var schedule []models.DaySchedule
+	for day := 0; day < 7; day++ {
+		schedule = append(schedule, models.DaySchedule{DayOfWeek: day, StartTimes: []string{"10:00", "11:00"}})
+	}
+	tpl := models.AvailabilityTemplate{
+		ID:             "template-1",
+		PsychologistID: "psych-1",
+		Duration:       50,
+		StartDate:      start.Format(dateLayout),
+		EndDate:        start.AddDate(0, 0, 13).Format(dateLayout),
+		Schedule:       schedule,
+		TimeZone:       "Asia/Almaty",
+	}
+	require.NoError(t, db.Create(&tpl).Error)
+
+	// A slot the psychologist added by hand at a time the template also covers
+	first := time.Date(start.Year(), start.Month(), start.Day(), 10, 0, 0, 0, almaty)
+	require.NoError(t, db.Create(&models.Slot{
+		ID: "manual", PsychologistID: "psych-1", StartTime: first, Duration: 50, Status: models.StatusAvailable,
+	}).Error)
+	// A day off in the second week
+	require.NoError(t, db.Create(&models.TimeOff{
+		ID: "off-1", PsychologistID: "psych-1",
+		StartDate: start.AddDate(0, 0, 7).Format(dateLayout), EndDate: start.AddDate(0, 0, 7).Format(dateLayout),
+	}).Error)
+
+	horizon := start.AddDate(0, 1, 0)
+	count, err := MaterializeTemplate(db, &tpl, horizon)
+	require.NoError(t, err)
+	assert.Equal(t, 14*2-1-2, count, "the manual slot and the day off are skipped")
+	assert.Equal(t, tpl.EndDate, tpl.GeneratedUntil)
+
+	var manual models.Slot
+	require.NoError(t, db.First(&manual, "start_time = ?", first).Error)
+	assert.Equal(t, "manual", manual.ID, "the existing slot is kept")
+
+	// Running again generates nothing new
+	count, err = MaterializeTemplate(db, &tpl, horizon)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// Not even when the generated days are forgotten, e.g. after a reset that kept taken slots
+	require.NoError(t, db.Model(&tpl).Update("generated_until", "").Error)
+	tpl.GeneratedUntil = ""
+	count, err = MaterializeTemplate(db, &tpl, horizon)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	var total int64
+	db.Model(&models.Slot{}).Where("psychologist_id = ?", "psych-1").Count(&total)
+	assert.Equal(t, int64(14*2-2), total)
+}
+
+func TestMaterializeTemplateStopsAtHorizon(t *testing.T) {
+	db := setupTestDB(t)
+	start := today(time.UTC).AddDate(0, 0, 1)
+	tpl := models.AvailabilityTemplate{
+		ID:             "template-1",
+		PsychologistID: "psych-1",
+		Duration:       50,
+		StartDate:      start.Format(dateLayout),
+		Schedule:       []models.DaySchedule{{DayOfWeek: int(start.Weekday()), StartTimes: []string{"10:00"}}},
+		TimeZone:       "UTC",
+	}
+	require.NoError(t, db.Create(&tpl).Error)
+
+	// Open-ended: one week now, the next week once the horizon moves
+	count, err := MaterializeTemplate(db, &tpl, start.AddDate(0, 0, 6))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = MaterializeTemplate(db, &tpl, start.AddDate(0, 0, 13))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, start.AddDate(0, 0, 13).Format(dateLayout), tpl.GeneratedUntil)
+}
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -8,31 +9,42 @@ import (
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
 )
 
-// StartReservationCleanup runs a background cron job to release expired locks
-func StartReservationCleanup() {
+// StartReservationCleanup runs a background cron job to release expired locks.
+// release frees one slot; expired waitlist holds move on to the next student in line there.
+func StartReservationCleanup(release func(ctx context.Context, slot models.Slot) (bool, error)) {
 	// Run the check every 1 minute
 	ticker := time.NewTicker(1 * time.Minute)
 
 	go func() {
 		for range ticker.C {
+			now := time.Now()
 			// Calculate the cutoff time (20 minutes ago)
-			expiredTime := time.Now().Add(-20 * time.Minute)
+			expiredTime := now.Add(-20 * time.Minute)
 
-			// Find and Update all expired reservations
-			result := config.DB.Model(&models.Slot{}).
-				Where("status = ? AND reserved_at < ?", models.StatusReserved, expiredTime).
-				Updates(map[string]interface{}{
-					"status":                models.StatusAvailable,
-					"student_id":            nil,
-					"reserved_at":           nil,
-					"booking_type":          "",
-					"questionnaire_answers": "",
-				})
+			var expired []models.Slot
+			err := config.DB.
+				Where("status = ?", models.StatusReserved).
+				Where("(hold_expires_at IS NULL AND reserved_at < ?) OR hold_expires_at < ?", expiredTime, now).
+				Find(&expired).Error
+			if err != nil {
+				log.Printf("[Worker Error] Failed to clean up reservations: %v", err)
+				continue
+			}
+
+			released := 0
+			for _, slot := range expired {
+				ok, err := release(context.Background(), slot)
+				if err != nil {
+					log.Printf("[Worker Error] Failed to release slot %s: %v", slot.ID, err)
+					continue
+				}
+				if ok {
+					released++
+				}
+			}
 
-			if result.Error != nil {
-				log.Printf("[Worker Error] Failed to clean up reservations: %v", result.Error)
-			} else if result.RowsAffected > 0 {
-				log.Printf("[Worker] Successfully released %d expired reservations back to available", result.RowsAffected)
+			if released > 0 {
+				log.Printf("[Worker] Successfully released %d expired reservations back to available", released)
 			}
 		}
 	}()
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	outboxBatchSize   = 100
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = 10 * time.Second
+	outboxMaxBackoff  = 30 * time.Minute
+)
+
+// StartOutboxRelay periodically publishes pending outbox messages to RabbitMQ
+func StartOutboxRelay(rabbitMQ *clients.RabbitMQClient) {
+	ticker := time.NewTicker(5 * time.Second)
+
+	go func() {
+		for range ticker.C {
+			if err := relayOutbox(rabbitMQ); err != nil {
+				log.Printf("[Worker Error] Outbox relay failed: %v", err)
+			}
+		}
+	}()
+}
+
+func relayOutbox(rabbitMQ *clients.RabbitMQClient) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets several booking-service replicas relay in parallel without double-publishing
+		var batch []models.OutboxMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, time.Now()).
+			Order("created_at asc").
+			Limit(outboxBatchSize).
+			Find(&batch).Error
+		if err != nil {
+			return err
+		}
+
+		for _, m := range batch {
+			now := time.Now()
+
+			if err := rabbitMQ.PublishRaw(m.Queue, []byte(m.Payload)); err != nil {
+				attempts := m.Attempts + 1
+				updates := map[string]interface{}{
+					"attempts":        attempts,
+					"last_error":      err.Error(),
+					"next_attempt_at": now.Add(outboxBackoff(attempts)),
+				}
+				if attempts >= outboxMaxAttempts {
+					updates["status"] = models.OutboxFailed
+					log.Printf("[Worker Error] Giving up on outbox message %s (%s) after %d attempts: %v", m.ID, m.Type, attempts, err)
+				}
+				if err := tx.Model(&models.OutboxMessage{}).Where("id = ?", m.ID).Updates(updates).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(&models.OutboxMessage{}).Where("id = ?", m.ID).Updates(map[string]interface{}{
+				"status":   models.OutboxSent,
+				"attempts": m.Attempts + 1,
+				"sent_at":  now,
+			}).Error; err != nil {
+				return err
+			}
+			log.Printf("[Worker] Published outbox message %s (%s)", m.ID, m.Type)
+		}
+		return nil
+	})
+}
+
+// outboxBackoff doubles the wait after every failed attempt, capped at outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return d
+}
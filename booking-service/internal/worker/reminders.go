@@ -8,6 +8,7 @@ import (
 	"github.com/pokonti/psychologist-backend/booking-service/config"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
 )
 
@@ -54,13 +55,13 @@ func sendReminder(slot models.Slot, userClient userprofile.UserProfileServiceCli
 	})
 
 	var studentEmail, psychName string
-	var telegramChatID string
+	studentLoc := scheduling.LoadLocation("")
 
 	if err == nil {
 		for _, p := range resp.Profiles {
 			if p.Id == *slot.StudentID {
 				studentEmail = p.Email
-				telegramChatID = p.TelegramChatId
+				studentLoc = scheduling.LoadLocation(p.TimeZone)
 			} else if p.Id == slot.PsychologistID {
 				psychName = p.FullName
 			}
@@ -73,8 +74,7 @@ func sendReminder(slot models.Slot, userClient userprofile.UserProfileServiceCli
 			ToEmail: studentEmail,
 			Data: map[string]string{
 				"psychologist_name": psychName,
-				"datetime":          slot.StartTime.Format("Monday, 02 Jan 2006 at 15:04"),
-				"telegram_chat_id":  telegramChatID,
+				"datetime":          slot.StartTime.In(studentLoc).Format("Monday, 02 Jan 2006 at 15:04"),
 				"subject":           subject,
 			},
 		}
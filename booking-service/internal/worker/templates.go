@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/booking-service/config"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/models"
+	"github.com/pokonti/psychologist-backend/booking-service/internal/scheduling"
+)
+
+// StartTemplateMaterializer keeps slots generated from availability templates
+// SLOT_GENERATION_WEEKS ahead of today
+func StartTemplateMaterializer() {
+	ticker := time.NewTicker(1 * time.Hour)
+
+	go func() {
+		materializeTemplates()
+		for range ticker.C {
+			materializeTemplates()
+		}
+	}()
+}
+
+func materializeTemplates() {
+	horizon := scheduling.Horizon()
+
+	var templates []models.AvailabilityTemplate
+	// Dates are stored as "YYYY-MM-DD", so string comparison orders them correctly
+	err := config.DB.
+		Where("generated_until < ? AND (end_date = '' OR end_date > generated_until)", horizon.Format("2006-01-02")).
+		Find(&templates).Error
+	if err != nil {
+		log.Printf("[Worker Error] Failed to load availability templates: %v", err)
+		return
+	}
+
+	for i := range templates {
+		count, err := scheduling.MaterializeTemplate(config.DB, &templates[i], horizon)
+		if err != nil {
+			log.Printf("[Worker Error] Failed to materialize template %s: %v", templates[i].ID, err)
+			continue
+		}
+		if count > 0 {
+			log.Printf("[Worker] Generated %d slots from template %s", count, templates[i].ID)
+		}
+	}
+}
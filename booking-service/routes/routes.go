@@ -14,19 +14,40 @@ func SetupRoutes(r *gin.Engine, h *handlers.BookingHandler) {
 		// Shared routes
 		api.GET("/slots", h.GetAvailableSlots)
 		api.GET("/slots/calendar", h.GetCalendarAvailability)
+		api.GET("/questionnaires", h.GetActiveQuestionnaires)
+
+		// iCal subscription
+		api.POST("/calendar/feed", h.CreateCalendarFeed)
+		api.DELETE("/calendar/feed", h.DeleteCalendarFeed)
+		api.GET("/calendar/ics/:token", h.GetCalendarFeed)
 
 		// Psychologist routes
 		psych := api.Group("/psychologist")
 		{
-			psych.POST("/slots", handlers.CreateSlot)
+			psych.POST("/slots", h.CreateSlot)
 			psych.GET("/slots", h.GetMySchedule)
 			psych.DELETE("/slots/:id", h.DeleteSlot)
 			psych.PUT("/slots/:id/notes", h.AddSessionNote)
+			psych.GET("/slots/:id/notes", h.GetSessionNote)
+			psych.POST("/slots/:id/notes/sign", h.SignSessionNote)
+			psych.GET("/slots/:id/notes/revisions", h.GetSessionNoteRevisions)
+			psych.GET("/slots/:id/notes/revisions/:revision", h.GetSessionNoteRevision)
 			psych.GET("/students/:student_id/history", h.GetStudentHistory)
+			psych.GET("/students/:student_id/mood", h.GetStudentMood)
 			psych.POST("/slots/:id/cancel", h.CancelBookingByPsychologist)
 			psych.PUT("/slots/:id/recommendations", h.AddRecommendation)
+			psych.PUT("/slots/:id/attendance", h.MarkAttendance)
 			psych.GET("/reviews", h.GetMyReviews)
 			psych.GET("/statistics", h.GetPsychologistStats)
+
+			psych.POST("/templates", h.CreateTemplate)
+			psych.GET("/templates", h.GetMyTemplates)
+			psych.PUT("/templates/:id", h.UpdateTemplate)
+			psych.DELETE("/templates/:id", h.DeleteTemplate)
+
+			psych.POST("/time-off", h.CreateTimeOff)
+			psych.GET("/time-off", h.GetMyTimeOff)
+			psych.DELETE("/time-off/:id", h.DeleteTimeOff)
 		}
 
 		// Student routes
@@ -51,6 +72,11 @@ func SetupRoutes(r *gin.Engine, h *handlers.BookingHandler) {
 		admin.POST("/bookings/:id/cancel", h.ForceCancelBooking)
 		admin.GET("/admin/dashboard", h.GetDashboard)
 		admin.GET("/reviews", h.GetAllReviews)
+		admin.POST("/notes/rotate-key", h.RotateNoteKey)
+		admin.GET("/notes/access-log", h.GetNoteAccessLog)
+		admin.GET("/questionnaires", h.GetAllQuestionnaires)
+		admin.POST("/questionnaires", h.CreateQuestionnaireVersion)
+		admin.PUT("/questionnaires/:code/active", h.SetQuestionnaireActive)
 	}
 
 	// Swagger endpoint
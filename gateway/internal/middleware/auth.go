@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"os"
 	"strings"
@@ -14,7 +15,11 @@ type ErrorResponse struct {
 }
 
 func JWTAuth() gin.HandlerFunc {
-	secret := []byte(os.Getenv("JWT_SECRET"))
+	jwksURL := os.Getenv("AUTH_JWKS_URL")
+	if jwksURL == "" {
+		jwksURL = "http://auth-service:8083/.well-known/jwks.json"
+	}
+	jwks := NewJWKSCache(jwksURL)
 
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -26,8 +31,12 @@ func JWTAuth() gin.HandlerFunc {
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
 		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return secret, nil
-		})
+			kid, ok := token.Header["kid"].(string)
+			if !ok || kid == "" {
+				return nil, errors.New("missing kid header")
+			}
+			return jwks.Key(kid)
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
 
 		if err != nil || !token.Valid {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid token"})
@@ -56,6 +65,13 @@ func JWTAuth() gin.HandlerFunc {
 		c.Request.Header.Set("X-User-ID", userID)
 		c.Request.Header.Set("X-User-Role", role)
 
+		// Session ID lets auth-service tell which device the request came from
+		if sessionID, ok := claims["sid"].(string); ok && sessionID != "" {
+			c.Request.Header.Set("X-Session-ID", sessionID)
+		} else {
+			c.Request.Header.Del("X-Session-ID")
+		}
+
 		c.Set("userID", userID)
 		c.Set("role", role)
 
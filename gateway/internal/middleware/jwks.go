@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	jwksRefreshInterval = 10 * time.Minute
+	// An unknown kid usually means auth-service rotated keys; refetch, but not more often than this
+	jwksMinRefetchInterval = 30 * time.Second
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSCache keeps auth-service's public signing keys in memory
+type JWKSCache struct {
+	url    string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	lastFetched time.Time
+}
+
+func NewJWKSCache(url string) *JWKSCache {
+	cache := &JWKSCache{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   map[string]*rsa.PublicKey{},
+	}
+
+	if err := cache.refresh(); err != nil {
+		// auth-service may still be starting; the first token verification retries
+		log.Printf("Initial JWKS fetch failed: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(jwksRefreshInterval)
+		for range ticker.C {
+			if err := cache.refresh(); err != nil {
+				log.Printf("JWKS refresh failed: %v", err)
+			}
+		}
+	}()
+
+	return cache
+}
+
+// Key returns the public key for kid, refetching the JWKS once if the kid is unknown
+func (j *JWKSCache) Key(kid string) (*rsa.PublicKey, error) {
+	j.mu.RLock()
+	key, ok := j.keys[kid]
+	stale := time.Since(j.lastFetched) > jwksMinRefetchInterval
+	j.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	if stale {
+		if err := j.refresh(); err != nil {
+			return nil, err
+		}
+		j.mu.RLock()
+		key, ok = j.keys[kid]
+		j.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (j *JWKSCache) refresh() error {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		j.markFetched()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		j.markFetched()
+		return fmt.Errorf("unexpected JWKS status: %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		j.markFetched()
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := parseRSAPublicKey(k)
+		if err != nil {
+			log.Printf("Skipping invalid JWK %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.lastFetched = time.Now()
+	j.mu.Unlock()
+	return nil
+}
+
+func (j *JWKSCache) markFetched() {
+	j.mu.Lock()
+	j.lastFetched = time.Now()
+	j.mu.Unlock()
+}
+
+func parseRSAPublicKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
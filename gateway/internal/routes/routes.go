@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/gateway/internal/middleware"
 	"github.com/pokonti/psychologist-backend/gateway/internal/proxy"
+	"github.com/pokonti/psychologist-backend/gateway/internal/stream"
 )
 
 func SetupRoutes(r *gin.Engine) {
@@ -17,19 +18,62 @@ func SetupRoutes(r *gin.Engine) {
 		authGroup.POST("/login", proxy.Forward("http://auth-service:8083"))
 		authGroup.POST("/verify", proxy.Forward("http://auth-service:8083"))
 		authGroup.POST("/refresh", proxy.Forward("http://auth-service:8083"))
+		authGroup.POST("/forgot-password", proxy.Forward("http://auth-service:8083"))
+		authGroup.POST("/reset-password", proxy.Forward("http://auth-service:8083"))
 	}
 
+	// Calendar apps cannot send a JWT, the secret token in the URL authenticates the feed
+	api.GET("/calendar/ics/:token", proxy.Forward("http://booking-service:8084"))
+
 	// Protected routes
 	protected := api.Group("", middleware.JWTAuth())
 	protected.GET("/users/me", proxy.Forward("http://user-service:8081"))
 	protected.PUT("/users/me", proxy.Forward("http://user-service:8081"))
 	protected.GET("/users/psychologists", proxy.Forward("http://user-service:8081"))
 	protected.POST("/users/me/mood", proxy.Forward("http://user-service:8081"))
+	protected.GET("/users/me/mood", proxy.Forward("http://user-service:8081"))
+	protected.DELETE("/users/me/mood/:id", proxy.Forward("http://user-service:8081"))
+	protected.POST("/users/me/mood/consents", proxy.Forward("http://user-service:8081"))
+	protected.GET("/users/me/mood/consents", proxy.Forward("http://user-service:8081"))
+	protected.DELETE("/users/me/mood/consents/:psychologist_id", proxy.Forward("http://user-service:8081"))
 	protected.GET("/users/me/mood/graphic", proxy.Forward("http://user-service:8081"))
+	protected.GET("/users/me/mood/chart", proxy.Forward("http://user-service:8081"))
+	protected.GET("/users/me/mood/reminder", proxy.Forward("http://user-service:8081"))
+	protected.PUT("/users/me/mood/reminder", proxy.Forward("http://user-service:8081"))
 	protected.GET("/slots", proxy.Forward("http://booking-service:8084"))
 	protected.GET("/slots/calendar", proxy.Forward("http://booking-service:8084"))
+	protected.GET("/questionnaires", proxy.Forward("http://booking-service:8084"))
+	protected.POST("/calendar/feed", proxy.Forward("http://booking-service:8084"))
+	protected.DELETE("/calendar/feed", proxy.Forward("http://booking-service:8084"))
 	protected.POST("/auth/logout", proxy.Forward("http://auth-service:8083"))
+	protected.GET("/auth/sessions", proxy.Forward("http://auth-service:8083"))
+	protected.DELETE("/auth/sessions", proxy.Forward("http://auth-service:8083"))
+	protected.DELETE("/auth/sessions/:id", proxy.Forward("http://auth-service:8083"))
 	protected.POST("/users/me/avatar-url", proxy.Forward("http://user-service:8081"))
+	protected.POST("/users/me/telegram/link", proxy.Forward("http://user-service:8081"))
+	protected.DELETE("/users/me/telegram", proxy.Forward("http://user-service:8081"))
+	protected.GET("/users/me/notification-preferences", proxy.Forward("http://user-service:8081"))
+	protected.PUT("/users/me/notification-preferences", proxy.Forward("http://user-service:8081"))
+
+	// In-app inbox: notification-service keeps it, the gateway streams new items live
+	protected.GET("/notifications", proxy.Forward("http://notification-service:8085"))
+	protected.GET("/notifications/stream", stream.Inbox())
+	protected.POST("/notifications/read-all", proxy.Forward("http://notification-service:8085"))
+	protected.POST("/notifications/:id/read", proxy.Forward("http://notification-service:8085"))
+
+	// Risk alerts and the duty rota live in user-service under /users
+	riskAlerts := protected.Group("/users/risk-alerts", middleware.RequireRoles("psychologist", "admin"))
+	{
+		riskAlerts.GET("", proxy.Forward("http://user-service:8081"))
+		riskAlerts.GET("/:id", proxy.Forward("http://user-service:8081"))
+		riskAlerts.POST("/:id/acknowledge", proxy.Forward("http://user-service:8081"))
+	}
+	dutyShifts := protected.Group("/users/admin/duty-shifts", middleware.RequireRoles("admin"))
+	{
+		dutyShifts.POST("", proxy.Forward("http://user-service:8081"))
+		dutyShifts.GET("", proxy.Forward("http://user-service:8081"))
+		dutyShifts.DELETE("/:id", proxy.Forward("http://user-service:8081"))
+	}
 
 	// Psychologist
 	psychOnly := protected.Group("/psychologist", middleware.RequireRoles("psychologist", "admin"))
@@ -38,11 +82,24 @@ func SetupRoutes(r *gin.Engine) {
 		psychOnly.GET("/slots", proxy.Forward("http://booking-service:8084"))
 		psychOnly.DELETE("/slots/:id", proxy.Forward("http://booking-service:8084"))
 		psychOnly.PUT("/slots/:id/notes", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/slots/:id/notes", proxy.Forward("http://booking-service:8084"))
+		psychOnly.POST("/slots/:id/notes/sign", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/slots/:id/notes/revisions", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/slots/:id/notes/revisions/:revision", proxy.Forward("http://booking-service:8084"))
 		psychOnly.GET("/students/:student_id/history", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/students/:student_id/mood", proxy.Forward("http://booking-service:8084"))
 		psychOnly.POST("/slots/:id/cancel", proxy.Forward("http://booking-service:8084"))
 		psychOnly.PUT("/slots/:id/recommendations", proxy.Forward("http://booking-service:8084"))
+		psychOnly.PUT("/slots/:id/attendance", proxy.Forward("http://booking-service:8084"))
 		psychOnly.GET("/reviews", proxy.Forward("http://booking-service:8084"))
 		psychOnly.GET("/statistics", proxy.Forward("http://booking-service:8084"))
+		psychOnly.POST("/templates", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/templates", proxy.Forward("http://booking-service:8084"))
+		psychOnly.PUT("/templates/:id", proxy.Forward("http://booking-service:8084"))
+		psychOnly.DELETE("/templates/:id", proxy.Forward("http://booking-service:8084"))
+		psychOnly.POST("/time-off", proxy.Forward("http://booking-service:8084"))
+		psychOnly.GET("/time-off", proxy.Forward("http://booking-service:8084"))
+		psychOnly.DELETE("/time-off/:id", proxy.Forward("http://booking-service:8084"))
 	}
 
 	// Student
@@ -70,6 +127,14 @@ func SetupRoutes(r *gin.Engine) {
 		adminOnly.GET("/users", proxy.Forward("http://user-service:8081"))
 		adminOnly.GET("/psychologists", proxy.Forward("http://user-service:8081"))
 		adminOnly.GET("/reviews", proxy.Forward("http://booking-service:8084"))
+		adminOnly.POST("/notes/rotate-key", proxy.Forward("http://booking-service:8084"))
+		adminOnly.GET("/notes/access-log", proxy.Forward("http://booking-service:8084"))
+		adminOnly.GET("/questionnaires", proxy.Forward("http://booking-service:8084"))
+		adminOnly.POST("/questionnaires", proxy.Forward("http://booking-service:8084"))
+		adminOnly.PUT("/questionnaires/:code/active", proxy.Forward("http://booking-service:8084"))
+		adminOnly.GET("/notifications", proxy.Forward("http://notification-service:8085"))
+		adminOnly.GET("/notifications/:id", proxy.Forward("http://notification-service:8085"))
+		adminOnly.POST("/notifications/:id/resend", proxy.Forward("http://notification-service:8085"))
 	}
 
 	// Proxy Swagger UIs
@@ -82,4 +147,7 @@ func SetupRoutes(r *gin.Engine) {
 	// Access User docs at: http://localhost:8080/docs/user/index.html
 	r.Any("/docs/user/*any", proxy.Forward("http://user-service:8081/swagger"))
 
+	// Access Notification docs at: http://localhost:8080/docs/notification/index.html
+	r.Any("/docs/notification/*any", proxy.Forward("http://notification-service:8085/swagger"))
+
 }
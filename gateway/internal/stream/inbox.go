@@ -0,0 +1,77 @@
+// Package stream pushes live updates to clients over Server-Sent Events
+package stream
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/gateway/internal/middleware"
+	"github.com/redis/go-redis/v9"
+)
+
+// keepAlive is how often an idle stream sends a comment, so proxies do not close it
+const keepAlive = 25 * time.Second
+
+// Inbox streams the signed-in user's new in-app notifications. notification-service publishes
+// each new inbox item on the Redis channel "inbox:<user id>"; every item is sent as a
+// "notification" event whose data is the item as JSON. It must run behind JWTAuth.
+//
+// The stream only carries what arrives while it is open: clients load GET /notifications on
+// connect to catch up, e.g. after a reconnect.
+func Inbox() gin.HandlerFunc {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: redisURL})
+
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, middleware.ErrorResponse{Error: "Unauthorized"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		sub := client.Subscribe(ctx, "inbox:"+userID)
+		defer sub.Close()
+		// Wait for the subscription, so nothing published after the response starts is missed
+		if _, err := sub.Receive(ctx); err != nil {
+			log.Printf("Failed to subscribe to the inbox of %s: %v", userID, err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, middleware.ErrorResponse{Error: "Notification stream unavailable"})
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // nginx would hold the events back otherwise
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		messages := sub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				c.SSEvent("notification", msg.Payload)
+				c.Writer.Flush()
+			case <-ticker.C:
+				if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
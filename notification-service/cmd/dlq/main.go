@@ -0,0 +1,96 @@
+// Command dlq lets an admin inspect, replay and purge notifications that ran out of retries.
+//
+//	dlq list [-limit 20]     show dead letters, oldest first, without removing them
+//	dlq replay -id <id>      put one dead letter back onto notifications_queue
+//	dlq replay -all          put every dead letter back
+//	dlq purge -yes           delete every dead letter
+//
+// Inside the stack: docker compose exec notification-service ./dlq list
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/notification-service/config"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/retry"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dlq list [-limit N] | dlq replay (-id ID | -all) | dlq purge -yes")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	log.SetFlags(0)
+
+	conn, ch, _ := config.ConnectRabbitMQ()
+	defer conn.Close()
+	defer ch.Close()
+	if err := retry.Declare(ch, retry.LoadPolicy()); err != nil {
+		log.Fatalf("Failed to declare queues: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ExitOnError)
+		limit := fs.Int("limit", 20, "maximum number of dead letters to show (0 for all)")
+		fs.Parse(os.Args[2:])
+
+		letters, err := retry.List(ch, *limit)
+		if err != nil {
+			log.Fatalf("Failed to list dead letters: %v", err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(letters)
+
+	case "replay":
+		fs := flag.NewFlagSet("replay", flag.ExitOnError)
+		id := fs.String("id", "", "ID of the dead letter to replay")
+		all := fs.Bool("all", false, "replay every dead letter")
+		fs.Parse(os.Args[2:])
+		if (*id == "") == !*all {
+			usage()
+		}
+
+		if err := ch.Confirm(false); err != nil {
+			log.Fatalf("Failed to enable publisher confirms: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		n, err := retry.Replay(ctx, ch, *id)
+		if err != nil {
+			log.Fatalf("Replayed %d dead letters, then failed: %v", n, err)
+		}
+		if *id != "" && n == 0 {
+			log.Fatalf("No dead letter with ID %s", *id)
+		}
+		fmt.Printf("Replayed %d dead letters\n", n)
+
+	case "purge":
+		fs := flag.NewFlagSet("purge", flag.ExitOnError)
+		yes := fs.Bool("yes", false, "confirm that every dead letter should be deleted")
+		fs.Parse(os.Args[2:])
+		if !*yes {
+			usage()
+		}
+
+		n, err := retry.Purge(ch)
+		if err != nil {
+			log.Fatalf("Failed to purge dead letters: %v", err)
+		}
+		fmt.Printf("Purged %d dead letters\n", n)
+
+	default:
+		usage()
+	}
+}
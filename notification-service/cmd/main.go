@@ -3,16 +3,71 @@ package main
 import (
 	"log"
 
+	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/notification-service/config"
+	_ "github.com/pokonti/psychologist-backend/notification-service/docs"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/notification-service/internal/consumer"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/email"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/handlers"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/retry"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/sms"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/templates"
+	"github.com/pokonti/psychologist-backend/notification-service/routes"
 )
 
+// @title       KBTU Psychologist Notification Service API
+// @version     1.0
+// @description In-app inbox, delivery log and resends for emails, Telegram messages and SMS of the KBTU counseling platform.
+// @BasePath    /api/v1
+// @host        localhost:8080
+// @schemes     http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	log.Println("Starting Notification Service...")
 
+	tmpl, err := templates.Load(templates.Dir())
+	if err != nil {
+		log.Fatalf("Failed to load templates: %v", err)
+	}
+
+	sender, err := email.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to set up email: %v", err)
+	}
+	texter, err := sms.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to set up SMS: %v", err)
+	}
+
+	config.ConnectDB()
+	config.ConnectRedis()
+
 	conn, ch, q := config.ConnectRabbitMQ()
 	defer conn.Close()
 	defer ch.Close()
 
-	consumer.StartListening(ch, q)
+	policy := retry.LoadPolicy()
+	if err := retry.Declare(ch, policy); err != nil {
+		log.Fatalf("Failed to declare retry queues: %v", err)
+	}
+
+	userClient, userConn, err := clients.NewUserProfileClient()
+	if err != nil {
+		log.Fatalf("Failed to connect to User Service: %v", err)
+	}
+	defer userConn.Close()
+
+	r := gin.Default()
+	routes.SetupRoutes(r, &handlers.NotificationHandler{RabbitConn: conn})
+	go func() {
+		log.Println("Notification Service API running on port 8085")
+		if err := r.Run(":8085"); err != nil {
+			log.Fatalf("Failed to start HTTP server: %v", err)
+		}
+	}()
+
+	consumer.StartListening(ch, q, userClient, tmpl, sender, texter, policy)
 }
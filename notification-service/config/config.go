@@ -1,12 +1,57 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
+var DB *gorm.DB
+var Redis *redis.Client
+
+func ConnectDB() {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		getEnv("DB_HOST", "localhost"),
+		getEnv("DB_USER", "postgres"),
+		getEnv("DB_PASSWORD", "password"),
+		getEnv("DB_NAME", "usersdb"),
+		getEnv("DB_PORT", "5432"),
+	)
+
+	var err error
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+
+	log.Println("Notification DB Connected. Running Migrations")
+	if err := DB.AutoMigrate(&models.NotificationDelivery{}, &models.InboxItem{}); err != nil {
+		log.Fatal("Failed to migrate database: ", err)
+	}
+}
+
+// ConnectRedis sets up the client that announces new inbox items to the gateway
+func ConnectRedis() {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis:6379"
+	}
+
+	Redis = redis.NewClient(&redis.Options{Addr: redisURL})
+	if err := Redis.Ping(context.Background()).Err(); err != nil {
+		// Items still land in the inbox, they are just not pushed until Redis is back
+		log.Printf("Failed to connect to Redis: %v", err)
+	}
+}
+
 func ConnectRabbitMQ() (*amqp.Connection, *amqp.Channel, amqp.Queue) {
 	rabbitURL := os.Getenv("RABBITMQ_URL")
 	if rabbitURL == "" {
@@ -39,3 +84,10 @@ func ConnectRabbitMQ() (*amqp.Connection, *amqp.Channel, amqp.Queue) {
 
 	return conn, ch, q
 }
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
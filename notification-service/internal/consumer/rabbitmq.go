@@ -1,22 +1,47 @@
 package consumer
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
+	"github.com/pokonti/psychologist-backend/notification-service/config"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/deliveries"
 	"github.com/pokonti/psychologist-backend/notification-service/internal/email"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/ical"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/inbox"
 	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/retry"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/routing"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/sms"
 	"github.com/pokonti/psychologist-backend/notification-service/internal/telegram"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/templates"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-// StartListening now takes the channel and queue injected from the config
-func StartListening(ch *amqp.Channel, q amqp.Queue) {
+// StartListening now takes the channel and queue injected from the config. userClient looks up
+// where each recipient wants to be notified, tmpl renders the messages, sender delivers the emails
+// and texter the text messages. A message is only acknowledged once it is delivered or handed to a
+// retry or dead-letter queue, per policy.
+func StartListening(ch *amqp.Channel, q amqp.Queue, userClient userprofile.UserProfileServiceClient, tmpl *templates.Set, sender email.Sender, texter sms.Provider, policy retry.Policy) {
+	if err := ch.Qos(10, 0, false); err != nil {
+		log.Fatalf("Failed to set prefetch: %v", err)
+	}
+	// Retried and dead-lettered messages are republished; confirms make sure they arrived
+	// before the original is acknowledged
+	if err := ch.Confirm(false); err != nil {
+		log.Fatalf("Failed to enable publisher confirms: %v", err)
+	}
+
 	msgs, err := ch.Consume(
 		q.Name,
 		"",
-		true,
+		false, // Acknowledged after delivery
 		false,
 		false,
 		false,
@@ -34,124 +59,168 @@ func StartListening(ch *amqp.Channel, q amqp.Queue) {
 	go func() {
 		for d := range msgs {
 			log.Printf("Received a message: %s", d.Body)
-			processMessage(d.Body)
+			// The ID ties the delivery log entries of every retry together
+			if d.MessageId == "" {
+				d.MessageId = retry.NewID()
+			}
+			err := processMessage(userClient, tmpl, sender, texter, d)
+			if err == nil {
+				d.Ack(false)
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			queue, pubErr := retry.Fail(ctx, ch, d, policy, err)
+			cancel()
+			if pubErr != nil {
+				// Leave it to the broker: the message is redelivered
+				log.Printf("Failed to schedule a retry (%v), requeueing: %v", err, pubErr)
+				time.Sleep(5 * time.Second)
+				d.Nack(false, true)
+				continue
+			}
+			log.Printf("Delivery failed (%v), moved to %s", err, queue)
+			if queue == retry.DeadLetterQueue {
+				deliveries.GiveUp(config.DB, d.MessageId)
+			}
+			d.Ack(false)
 		}
 	}()
 
 	<-forever // Blocks the main thread forever
 }
 
-// processMessage remains exactly the same as your code
-func processMessage(body []byte) {
+// sendTelegram is telegram.SendMessage; tests replace it so nothing reaches the Bot API
+var sendTelegram = telegram.SendMessage
+
+// processMessage renders the message in its recipient's language and sends it on the channels
+// they chose, logging each attempt. A message that cannot be decoded or rendered is logged as
+// failed without a channel. An error means the message should be retried unless it is
+// retry.Permanent.
+//
+// Email goes first and a failed email is retried before anything else is sent, so a retry never
+// repeats a message the recipient already got. For the same reason only failures before the first
+// sent message are retried, e.g. Telegram when email is muted; later ones are only logged. The
+// inbox comes before Telegram and does not count as sent because adding an item twice is
+// harmless: a retry skips it.
+func processMessage(userClient userprofile.UserProfileServiceClient, tmpl *templates.Set, sender email.Sender, texter sms.Provider, d amqp.Delivery) error {
 	var msg models.NotificationMessage
-	if err := json.Unmarshal(body, &msg); err != nil {
-		log.Printf("Error decoding JSON: %v", err)
-		return
+	if err := json.Unmarshal(d.Body, &msg); err != nil {
+		err = fmt.Errorf("decode message: %w", err)
+		deliveries.Reject(config.DB, deliveries.Message{ID: d.MessageId, ResendOf: retry.ResendOf(d)}, err)
+		return retry.Permanent(err)
 	}
 
-	var subject, htmlBody string
+	route := routing.Resolve(context.Background(), userClient, msg)
+	logged := deliveries.Message{ID: d.MessageId, ResendOf: retry.ResendOf(d), UserID: route.UserID, NotificationMessage: msg}
 
-	switch msg.Type {
-	case "booking_confirmation":
-		subject = "Appointment Confirmed! ✅"
-		htmlBody = fmt.Sprintf(`
-			<h2>Your Appointment is Confirmed</h2>
-			<p>You have successfully booked a session.</p>
-			<ul>
-				<li><b>Specialist:</b> %s</li>
-				<li><b>Date & Time:</b> %s</li>
-				<li><b>Format:</b> %s</li>
-			</ul>
-			<p>Thank you for using KBTU Care.</p>
-		`, msg.Data["psychologist_name"], msg.Data["datetime"], msg.Data["format"])
-
-	case "auth_verification":
-		subject = "Verify your KBTU Care Account"
-		htmlBody = fmt.Sprintf(`
-			<h2>Welcome to KBTU Care!</h2>
-			<p>Your verification code is: <b>%s</b></p>
-			<p>This code will expire in 15 minutes.</p>
-		`, msg.Data["code"])
-
-	case "booking_cancellation":
-		subject = "Appointment Canceled ❌"
-		htmlBody = fmt.Sprintf(`
-			<h2>Appointment Canceled</h2>
-			<p>Your appointment with <b>%s</b> on <b>%s</b> has been canceled.</p>
-			<p>We hope to see you again soon.</p>`, msg.Data["psychologist_name"], msg.Data["datetime"])
-
-	case "booking_cancellation_by_psychologist":
-		subject = "Appointment Canceled ❌"
-		htmlBody = fmt.Sprintf(`
-			<h2>Appointment Canceled</h2>
-			<p>Hello,</p>
-			<p>Your appointment with <b>%s</b> scheduled for <b>%s</b> has been canceled by <b>%s</b>.</p>
-			<p>We apologize for the inconvenience.</p>
-			<p>If you have any questions, please contact the KBTU Care support team.</p>
-		`, msg.Data["psychologist_name"], msg.Data["datetime"], msg.Data["psychologist_name"])
+	rendered, err := tmpl.Render(msg.Type, route.Locale, msg.Data)
+	if errors.Is(err, templates.ErrUnknownType) {
+		err = fmt.Errorf("unknown message type: %s", msg.Type)
+	} else if err != nil {
+		err = fmt.Errorf("render %s: %w", msg.Type, err)
+	}
+	if err != nil {
+		deliveries.Reject(config.DB, logged, err)
+		return retry.Permanent(err)
+	}
 
-	case "booking_reschedule":
-		subject = "Appointment Rescheduled 📅"
-		htmlBody = fmt.Sprintf(`
-			<h2>Appointment Rescheduled</h2>
-			<p>Your appointment has been successfully moved.</p>
-			<ul>
-				<li><b>Specialist:</b> %s</li>
-				<li><b>New Date & Time:</b> %s</li>
-				<li><b>Format:</b> %s</li>
-			</ul>`, msg.Data["psychologist_name"], msg.Data["datetime"], msg.Data["format"])
-
-	case "waitlist_alert":
-		subject = "A Slot Just Opened Up! 🚨"
-		htmlBody = fmt.Sprintf(`
-			<h2>Good News!</h2>
-			<p>A time slot has just become available for <b>%s</b> on <b>%s</b>.</p>
-			<p>Since you are on the waitlist, we are letting you know immediately.</p>
-			<p>Please log in to the KBTU Care platform to book this slot before someone else takes it!</p>
-			<br>
-			<p><i>Note: Waitlist alerts are sent to everyone waiting for this day. Booking is first-come, first-served.</i></p>
-		`, msg.Data["psychologist_name"], msg.Data["date"])
-	case "new_recommendation":
-		subject = "Post-Session Recommendations 📝"
-		htmlBody = fmt.Sprintf(`
-			<h2>Recommendations from %s</h2>
-			<p>Your psychologist has shared some notes and recommendations from your session on <b>%s</b>.</p>
-			<p>Please log in to your KBTU Care dashboard and visit "My Appointments" to view them.</p>
-			<br>
-			<p>Take care,<br>The KBTU Care Team</p>
-		`, msg.Data["psychologist_name"], msg.Data["date"])
-	case "account_blocked":
-		subject = "Your Account Access Status ⚠️"
-		htmlBody = fmt.Sprintf(`
-			<h2>Account Notice</h2>
-			<p>Your KBTU Care account has been restricted.</p>
-			<p><b>Reason:</b> %s</p>
-			<p>If you believe this is a mistake, please contact the administration office.</p>
-		`, msg.Data["reason"])
-	case "session_reminder":
-		subject = "Reminder: Upcoming Appointment ⏰"
-		htmlBody = fmt.Sprintf(`
-			<h2>Appointment Reminder</h2>
-			<p>You have a session with <b>%s</b>  <b>%s</b> at <b>%s</b>.</p>
-			<p>Please ensure you are on time!</p>
-		`, msg.Data["psychologist_name"], msg.Data["subject"], msg.Data["datetime"])
-
-		tgChatID := msg.Data["telegram_chat_id"]
-		if tgChatID != "" {
-			tgText := fmt.Sprintf("⏰ <b>Reminder!</b>\nYou have an appointment with %s tomorrow at %s.", msg.Data["psychologist_name"], msg.Data["datetime"])
-			telegram.SendMessage(tgChatID, tgText)
+	// Whether the recipient got the message on a channel a retry would repeat it on
+	delivered := false
+	if route.Email {
+		log.Printf("Sending email to %s...", msg.ToEmail)
+		err = sender.Send(email.Message{
+			To:          msg.ToEmail,
+			Subject:     rendered.Subject,
+			HTML:        rendered.HTML,
+			Text:        rendered.Text,
+			Attachments: calendarAttachments(msg),
+		})
+		deliveries.Record(config.DB, logged, models.ChannelEmail, err, true)
+		if err != nil {
+			return fmt.Errorf("send email to %s: %w", msg.ToEmail, err)
 		}
+		log.Printf("Email sent successfully to %s", msg.ToEmail)
+		delivered = true
+	} else {
+		log.Printf("%s is muted for email by %s", msg.Type, msg.ToEmail)
+	}
 
-	default:
-		log.Printf("Unknown message type: %s", msg.Type)
-		return
+	if route.InApp && route.UserID != "" {
+		err := inbox.Add(context.Background(), config.DB, config.Redis, models.InboxItem{
+			UserID:    route.UserID,
+			MessageID: d.MessageId,
+			Type:      msg.Type,
+			Title:     rendered.Subject,
+			Body:      rendered.Content,
+			Link:      inbox.Link(msg.Data),
+		})
+		deliveries.Record(config.DB, logged, models.ChannelInApp, err, !delivered)
+		if err != nil {
+			if !delivered {
+				return fmt.Errorf("add %s to the inbox: %w", msg.Type, err)
+			}
+			log.Printf("Failed to add %s to the inbox of %s: %v", msg.Type, msg.ToEmail, err)
+		}
 	}
 
-	log.Printf("Sending email to %s...", msg.ToEmail)
-	err := email.SendEmail(msg.ToEmail, subject, htmlBody)
-	if err != nil {
-		log.Printf("Failed to send email to %s: %v", msg.ToEmail, err)
-	} else {
-		log.Printf("Email sent successfully to %s", msg.ToEmail)
+	// Types without a Telegram template are email only
+	if route.Telegram && rendered.Telegram != "" {
+		err := sendTelegram(route.TelegramChatID, rendered.Telegram, route.Silent)
+		deliveries.Record(config.DB, logged, models.ChannelTelegram, err, !delivered)
+		if err != nil {
+			if !delivered {
+				return fmt.Errorf("send %s over Telegram: %w", msg.Type, err)
+			}
+			log.Printf("Failed to send %s to %s over Telegram: %v", msg.Type, msg.ToEmail, err)
+		} else {
+			delivered = true
+		}
+	}
+
+	// Only types with an SMS template are texted
+	if route.SMS && rendered.SMS != "" {
+		err := texter.Send(route.PhoneNumber, rendered.SMS)
+		deliveries.Record(config.DB, logged, models.ChannelSMS, err, !delivered)
+		if err != nil {
+			if !delivered {
+				return fmt.Errorf("send %s by SMS: %w", msg.Type, err)
+			}
+			log.Printf("Failed to send %s to %s by SMS: %v", msg.Type, msg.ToEmail, err)
+		}
+	}
+	return nil
+}
+
+// calendarAttachments builds the .ics invites for booking messages so that calendars add,
+// move or drop the appointment. Other message types get none.
+func calendarAttachments(msg models.NotificationMessage) []email.Attachment {
+	var attachments []email.Attachment
+
+	add := func(method, prefix, filename string) {
+		inv, ok := ical.FromData(msg.Data, prefix)
+		if !ok {
+			return
+		}
+		inv.Summary = "KBTU Care session with " + msg.Data["psychologist_name"]
+		inv.Organizer = os.Getenv("SMTP_EMAIL")
+		inv.Attendee = msg.ToEmail
+
+		attachments = append(attachments, email.Attachment{
+			Filename:    filename,
+			ContentType: "text/calendar; method=" + method + "; charset=UTF-8",
+			Data:        ical.Build(method, inv),
+		})
+	}
+
+	switch msg.Type {
+	case "booking_confirmation":
+		add(ical.MethodRequest, "", "invite.ics")
+	case "booking_reschedule":
+		add(ical.MethodRequest, "", "invite.ics")
+		add(ical.MethodCancel, "old_", "cancel.ics")
+	case "booking_cancellation", "booking_cancellation_by_psychologist", "booking_reschedule_offer":
+		add(ical.MethodCancel, "", "cancel.ics")
 	}
+	return attachments
 }
@@ -0,0 +1,404 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pokonti/psychologist-backend/notification-service/config"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/email"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/sms"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/telegram"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/templates"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const templatesDir = "../../templates"
+
+// MockUserClient mocks the gRPC client; only the notification preferences are used here
+type MockUserClient struct {
+	mock.Mock
+}
+
+func (m *MockUserClient) CreateUserProfile(ctx context.Context, in *userprofile.CreateUserProfileRequest, opts ...grpc.CallOption) (*userprofile.CreateUserProfileResponse, error) {
+	return nil, nil
+}
+
+func (m *MockUserClient) GetUserProfileByID(ctx context.Context, in *userprofile.GetUserProfileByIDRequest, opts ...grpc.CallOption) (*userprofile.GetUserProfileByIDResponse, error) {
+	return nil, nil
+}
+
+func (m *MockUserClient) GetBatchUserProfiles(ctx context.Context, in *userprofile.GetBatchUserProfilesRequest, opts ...grpc.CallOption) (*userprofile.GetBatchUserProfilesResponse, error) {
+	return nil, nil
+}
+
+func (m *MockUserClient) UpdateUserPhone(ctx context.Context, in *userprofile.UpdateUserPhoneRequest, opts ...grpc.CallOption) (*userprofile.UpdateUserPhoneResponse, error) {
+	return nil, nil
+}
+
+func (m *MockUserClient) GetConsentedMoodLogs(ctx context.Context, in *userprofile.GetConsentedMoodLogsRequest, opts ...grpc.CallOption) (*userprofile.GetConsentedMoodLogsResponse, error) {
+	return nil, nil
+}
+
+func (m *MockUserClient) GetNotificationPreferences(ctx context.Context, in *userprofile.GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*userprofile.GetNotificationPreferencesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetNotificationPreferencesResponse), args.Error(1)
+}
+
+// unknownRecipient is a user client for recipients without a profile: email only, in English
+func unknownRecipient() *MockUserClient {
+	client := new(MockUserClient)
+	client.On("GetNotificationPreferences", mock.Anything, mock.Anything).
+		Return(nil, status.Error(codes.NotFound, "user not found"))
+	return client
+}
+
+// failingSender fails every email like an unreachable SMTP server
+type failingSender struct{}
+
+func (failingSender) Send(email.Message) error {
+	return errors.New("dial tcp: connection refused")
+}
+
+func setupTestDB() {
+	// Using in-memory SQLite instead of Postgres
+	db, _ := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	config.DB = db
+	config.DB.AutoMigrate(&models.NotificationDelivery{}, &models.InboxItem{})
+	config.DB.Exec("DELETE FROM notification_deliveries")
+	config.DB.Exec("DELETE FROM inbox_items")
+}
+
+func setupTemplates(t *testing.T) *templates.Set {
+	tmpl, err := templates.Load(templatesDir)
+	require.NoError(t, err)
+	return tmpl
+}
+
+func delivery(id, body string) amqp.Delivery {
+	return amqp.Delivery{MessageId: id, Body: []byte(body)}
+}
+
+var booking = `"psychologist_name": "Aigerim Sadykova", "datetime": "Mon, 02 Mar 2026 at 10:00", "format": "online",
+	"slot_id": "0b1f6c1e-3a59-4d5e-9a57-1c2f0e4b7a10", "start_time": "2026-03-02T05:00:00Z", "duration": "50"`
+
+// Every message type publishers send, with the data they send it with and what the email must say
+var messageCases = []struct {
+	msgType     string
+	data        string
+	subject     string
+	mentions    []string
+	attachments []string
+}{
+	{"auth_verification", `{"code": "482913"}`, "Verify your KBTU Care Account", []string{"482913"}, nil},
+	{"password_reset", `{"code": "730215"}`, "Reset your KBTU Care Password", []string{"730215"}, nil},
+	{"account_blocked", `{"reason": "Repeated no-shows"}`, "Your Account Access Status ⚠️", []string{"Repeated no-shows"}, nil},
+	{"booking_confirmation", `{` + booking + `}`, "Appointment Confirmed! ✅", []string{"Aigerim Sadykova", "Mon, 02 Mar 2026 at 10:00", "online"}, []string{"invite.ics"}},
+	{"booking_reschedule", `{` + booking + `, "old_slot_id": "5d0c2b7e-8f4a-4c1b-9e3d-2a6f1b0c9d8e", "old_start_time": "2026-02-27T05:00:00Z"}`,
+		"Appointment Rescheduled 📅", []string{"Aigerim Sadykova", "Mon, 02 Mar 2026 at 10:00"}, []string{"invite.ics", "cancel.ics"}},
+	{"booking_cancellation", `{` + booking + `}`, "Appointment Canceled ❌", []string{"Aigerim Sadykova", "Mon, 02 Mar 2026 at 10:00"}, []string{"cancel.ics"}},
+	{"booking_cancellation_by_psychologist", `{` + booking + `}`, "Appointment Canceled ❌", []string{"Aigerim Sadykova", "Mon, 02 Mar 2026 at 10:00"}, []string{"cancel.ics"}},
+	{"booking_reschedule_offer", `{` + booking + `, "suggested_datetime": "Wed, 04 Mar 2026 at 14:00"}`,
+		"Appointment Canceled: New Time Suggested 📅", []string{"Aigerim Sadykova", "Wed, 04 Mar 2026 at 14:00"}, []string{"cancel.ics"}},
+	{"session_reminder", `{"psychologist_name": "Aigerim Sadykova", "datetime": "Mon, 02 Mar 2026 at 10:00", "subject": "tomorrow"}`,
+		"Reminder: Upcoming Appointment ⏰", []string{"Aigerim Sadykova", "Mon, 02 Mar 2026 at 10:00"}, nil},
+	{"new_recommendation", `{"psychologist_name": "Aigerim Sadykova", "date": "02 Mar 2026"}`,
+		"Post-Session Recommendations 📝", []string{"Aigerim Sadykova", "02 Mar 2026"}, nil},
+	{"waitlist_hold", `{"psychologist_name": "Aigerim Sadykova", "datetime": "Mon, 02 Mar 2026 at 10:00", "expires_at": "09:30", "confirm_url": "https://care.kbtu.kz/waitlist/confirm"}`,
+		"A Slot Is Being Held for You! 🚨", []string{"Aigerim Sadykova", "09:30", "https://care.kbtu.kz/waitlist/confirm"}, nil},
+	{"urgent_risk_alert", `{"student_name": "Dana Nurlanovna", "student_email": "dana@kbtu.kz", "student_phone": "+77011234567", "severity": "high", "source": "mood", "description": "Low mood for 5 days", "detail": "Mood logs", "raised_at": "02 Mar 2026 09:00", "alert_url": "https://care.kbtu.kz/alerts/1", "escalated": "false"}`,
+		"🚨 Urgent: a student may be at risk", []string{"Dana Nurlanovna", "dana@kbtu.kz", "Low mood for 5 days", "https://care.kbtu.kz/alerts/1"}, nil},
+}
+
+func TestEveryMessageTypeHasACase(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(templatesDir, templates.DefaultLocale, "*.tmpl"))
+	require.NoError(t, err)
+
+	covered := map[string]bool{}
+	for _, c := range messageCases {
+		covered[c.msgType] = true
+	}
+	for _, file := range files {
+		msgType := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		if msgType == "common" {
+			continue
+		}
+		assert.True(t, covered[msgType], "no test case for %s", msgType)
+	}
+}
+
+func TestProcessMessageSendsEmail(t *testing.T) {
+	setupTestDB()
+	tmpl := setupTemplates(t)
+
+	for _, c := range messageCases {
+		t.Run(c.msgType, func(t *testing.T) {
+			sender, _ := email.NewCaptureSender("")
+			body := `{"type": "` + c.msgType + `", "to_email": "student@kbtu.kz", "data": ` + c.data + `}`
+
+			err := processMessage(unknownRecipient(), tmpl, sender, sms.NewStubProvider(), delivery("msg-"+c.msgType, body))
+			require.NoError(t, err)
+
+			sent := sender.Sent()
+			require.Len(t, sent, 1)
+			got := sent[0]
+			assert.Equal(t, "student@kbtu.kz", got.To)
+			assert.Equal(t, c.subject, got.Subject)
+			for _, want := range c.mentions {
+				assert.Contains(t, got.Text, want)
+				assert.Contains(t, got.HTML, want)
+			}
+
+			var filenames []string
+			for _, a := range got.Attachments {
+				filenames = append(filenames, a.Filename)
+				assert.Contains(t, string(a.Data), "BEGIN:VCALENDAR")
+			}
+			assert.Equal(t, c.attachments, filenames)
+
+			// The email as it goes on the wire
+			parsed, err := mail.ReadMessage(bytes.NewReader(got.Raw))
+			require.NoError(t, err)
+			assert.Equal(t, got.MessageID, parsed.Header.Get("Message-ID"))
+			_, err = parsed.Header.Date()
+			assert.NoError(t, err)
+			assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/")
+
+			var logged models.NotificationDelivery
+			require.NoError(t, config.DB.Where("message_id = ?", "msg-"+c.msgType).First(&logged).Error)
+			assert.Equal(t, models.ChannelEmail, logged.Channel)
+			assert.Equal(t, models.DeliverySent, logged.Status)
+			assert.Equal(t, 1, logged.Attempts)
+		})
+	}
+}
+
+func TestProcessMessageFollowsPreferences(t *testing.T) {
+	setupTestDB()
+	tmpl := setupTemplates(t)
+
+	client := new(MockUserClient)
+	client.On("GetNotificationPreferences", mock.Anything, mock.Anything).
+		Return(&userprofile.GetNotificationPreferencesResponse{
+			UserId: "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			Locale: "ru",
+			Categories: map[string]*userprofile.ChannelPreferences{
+				userprofile.CategoryReminders: {Email: false, Telegram: false, InApp: true},
+			},
+		}, nil)
+	sender, _ := email.NewCaptureSender("")
+
+	// Reminders are muted for email but still land in the inbox
+	err := processMessage(client, tmpl, sender, sms.NewStubProvider(), delivery("msg-muted",
+		`{"type": "session_reminder", "to_email": "student@kbtu.kz", "data": {"psychologist_name": "Aigerim Sadykova"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, sender.Sent())
+
+	var item models.InboxItem
+	require.NoError(t, config.DB.Where("message_id = ?", "msg-muted").First(&item).Error)
+	assert.Equal(t, "7c9e6679-7425-40de-944b-e07fc1f90ae7", item.UserID)
+	assert.NotEmpty(t, item.Title)
+	assert.Contains(t, item.Body, "Aigerim Sadykova")
+	assert.Nil(t, item.ReadAt)
+
+	// A retry does not add the item twice
+	err = processMessage(client, tmpl, sender, sms.NewStubProvider(), delivery("msg-muted",
+		`{"type": "session_reminder", "to_email": "student@kbtu.kz", "data": {"psychologist_name": "Aigerim Sadykova"}}`))
+	require.NoError(t, err)
+	var count int64
+	config.DB.Model(&models.InboxItem{}).Where("message_id = ?", "msg-muted").Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// One-time codes stay out of the inbox
+	err = processMessage(client, tmpl, sender, sms.NewStubProvider(), delivery("msg-code", `{"type": "password_reset", "to_email": "student@kbtu.kz", "data": {"code": "730215"}}`))
+	require.NoError(t, err)
+	config.DB.Model(&models.InboxItem{}).Where("message_id = ?", "msg-code").Count(&count)
+	assert.Zero(t, count)
+	sender.Reset()
+
+	// Essential types ignore the categories and are written in the recipient's language
+	err = processMessage(client, tmpl, sender, sms.NewStubProvider(), delivery("msg-ru", `{"type": "booking_confirmation", "to_email": "student@kbtu.kz", "data": {`+booking+`}}`))
+	require.NoError(t, err)
+	require.Len(t, sender.Sent(), 1)
+
+	english, err := tmpl.Render("booking_confirmation", "en", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, english.Subject, sender.Sent()[0].Subject)
+
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ?", "msg-ru").First(&logged).Error)
+	assert.Equal(t, "7c9e6679-7425-40de-944b-e07fc1f90ae7", logged.UserID)
+}
+
+func TestProcessMessageUnknownType(t *testing.T) {
+	setupTestDB()
+	sender, _ := email.NewCaptureSender("")
+
+	err := processMessage(unknownRecipient(), setupTemplates(t), sender, sms.NewStubProvider(), delivery("msg-unknown",
+		`{"type": "no_such_type", "to_email": "student@kbtu.kz"}`))
+
+	assert.ErrorContains(t, err, "unknown message type")
+	assert.Empty(t, sender.Sent())
+
+	// Logged even though no channel was tried
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ?", "msg-unknown").First(&logged).Error)
+	assert.Equal(t, models.ChannelNone, logged.Channel)
+	assert.Equal(t, models.DeliveryFailed, logged.Status)
+	assert.Equal(t, "no_such_type", logged.Type)
+	assert.Equal(t, "student@kbtu.kz", logged.ToEmail)
+	assert.Contains(t, logged.LastError, "no_such_type")
+}
+
+func TestProcessMessageLogsUndecodableMessage(t *testing.T) {
+	setupTestDB()
+	sender, _ := email.NewCaptureSender("")
+
+	err := processMessage(unknownRecipient(), setupTemplates(t), sender, sms.NewStubProvider(), delivery("msg-garbled", `{"type": "booking_confirmation",`))
+
+	assert.ErrorContains(t, err, "decode message")
+	assert.Empty(t, sender.Sent())
+
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ?", "msg-garbled").First(&logged).Error)
+	assert.Equal(t, models.ChannelNone, logged.Channel)
+	assert.Equal(t, models.DeliveryFailed, logged.Status)
+	assert.Contains(t, logged.LastError, "decode message")
+	assert.Empty(t, logged.Data, "there is nothing to resend")
+}
+
+func TestProcessMessageLogsFailedEmail(t *testing.T) {
+	setupTestDB()
+	tmpl := setupTemplates(t)
+	body := `{"type": "booking_cancellation", "to_email": "student@kbtu.kz", "data": {` + booking + `}}`
+
+	for i := 0; i < 2; i++ {
+		err := processMessage(unknownRecipient(), tmpl, failingSender{}, sms.NewStubProvider(), delivery("msg-failing", body))
+		assert.Error(t, err)
+	}
+
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ?", "msg-failing").First(&logged).Error)
+	assert.Equal(t, models.DeliveryRetrying, logged.Status)
+	assert.Equal(t, 2, logged.Attempts)
+	assert.Contains(t, logged.LastError, "connection refused")
+	assert.NotEmpty(t, logged.Data) // can be resent
+}
+
+func TestProcessMessageSendsSMS(t *testing.T) {
+	setupTestDB()
+	tmpl := setupTemplates(t)
+
+	optedIn := func(phone string, quiet bool) *MockUserClient {
+		client := new(MockUserClient)
+		client.On("GetNotificationPreferences", mock.Anything, mock.Anything).
+			Return(&userprofile.GetNotificationPreferencesResponse{
+				UserId:       "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				Sms:          true,
+				PhoneNumber:  phone,
+				InQuietHours: quiet,
+				Categories: map[string]*userprofile.ChannelPreferences{
+					userprofile.CategoryReminders: {Email: true, Telegram: false, InApp: true},
+				},
+			}, nil)
+		return client
+	}
+	sender, _ := email.NewCaptureSender("")
+	reminder := `{"type": "session_reminder", "to_email": "student@kbtu.kz", "data": {"psychologist_name": "Aigerim Sadykova", "datetime": "Mon, 02 Mar 2026 at 10:00", "subject": "tomorrow"}}`
+
+	// Reminders and cancellations by the psychologist are texted, other types are not
+	texter := sms.NewStubProvider()
+	require.NoError(t, processMessage(optedIn("+77011234567", false), tmpl, sender, texter, delivery("msg-sms-reminder", reminder)))
+	require.NoError(t, processMessage(optedIn("+77011234567", false), tmpl, sender, texter, delivery("msg-sms-cancel",
+		`{"type": "booking_cancellation_by_psychologist", "to_email": "student@kbtu.kz", "data": {`+booking+`}}`)))
+	require.NoError(t, processMessage(optedIn("+77011234567", false), tmpl, sender, texter, delivery("msg-sms-confirm",
+		`{"type": "booking_confirmation", "to_email": "student@kbtu.kz", "data": {`+booking+`}}`)))
+
+	sent := texter.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "+77011234567", sent[0].To)
+	assert.Contains(t, sent[0].Text, "Aigerim Sadykova")
+	assert.Contains(t, sent[0].Text, "Mon, 02 Mar 2026 at 10:00")
+	assert.Contains(t, sent[1].Text, "canceled")
+
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ? AND channel = ?", "msg-sms-cancel", models.ChannelSMS).First(&logged).Error)
+	assert.Equal(t, models.DeliverySent, logged.Status)
+
+	// Nothing is texted to a number that is not E.164, or during quiet hours
+	texter = sms.NewStubProvider()
+	require.NoError(t, processMessage(optedIn("87011234567", false), tmpl, sender, texter, delivery("msg-sms-invalid", reminder)))
+	require.NoError(t, processMessage(optedIn("+77011234567", true), tmpl, sender, texter, delivery("msg-sms-quiet", reminder)))
+	assert.Empty(t, texter.Sent())
+}
+
+// failingTexter fails every text message like an unreachable SMS gateway
+type failingTexter struct{}
+
+func (failingTexter) Send(to, text string) error {
+	return errors.New("twilio: 503 service unavailable")
+}
+
+func TestProcessMessageDoesNotRepeatTelegram(t *testing.T) {
+	setupTestDB()
+	tmpl := setupTemplates(t)
+
+	var telegrams int
+	sendTelegram = func(chatID, text string, silent bool) error {
+		telegrams++
+		return nil
+	}
+	t.Cleanup(func() { sendTelegram = telegram.SendMessage })
+
+	client := new(MockUserClient)
+	client.On("GetNotificationPreferences", mock.Anything, mock.Anything).
+		Return(&userprofile.GetNotificationPreferencesResponse{
+			UserId:         "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			TelegramChatId: "12345",
+			Sms:            true,
+			PhoneNumber:    "+77011234567",
+			Categories: map[string]*userprofile.ChannelPreferences{
+				userprofile.CategoryReminders: {Email: false, Telegram: true, InApp: false},
+			},
+		}, nil)
+	sender, _ := email.NewCaptureSender("")
+	reminder := `{"type": "session_reminder", "to_email": "student@kbtu.kz", "data": {"psychologist_name": "Aigerim Sadykova", "datetime": "Mon, 02 Mar 2026 at 10:00", "subject": "tomorrow"}}`
+
+	// Telegram got through, so the failed SMS is not retried, which would send the Telegram message again
+	require.NoError(t, processMessage(client, tmpl, sender, failingTexter{}, delivery("msg-no-email", reminder)))
+	assert.Equal(t, 1, telegrams)
+	assert.Empty(t, sender.Sent())
+
+	var logged models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ? AND channel = ?", "msg-no-email", models.ChannelSMS).First(&logged).Error)
+	assert.Equal(t, models.DeliveryFailed, logged.Status)
+	assert.Contains(t, logged.LastError, "503")
+
+	// Before anything was sent a failure is still retried
+	sendTelegram = func(chatID, text string, silent bool) error {
+		telegrams++
+		return errors.New("telegram is down")
+	}
+	err := processMessage(client, tmpl, sender, failingTexter{}, delivery("msg-nothing-sent", reminder))
+	assert.ErrorContains(t, err, "Telegram")
+	var retried models.NotificationDelivery
+	require.NoError(t, config.DB.Where("message_id = ? AND channel = ?", "msg-nothing-sent", models.ChannelTelegram).First(&retried).Error)
+	assert.Equal(t, models.DeliveryRetrying, retried.Status)
+}
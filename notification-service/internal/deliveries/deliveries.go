@@ -0,0 +1,110 @@
+// Package deliveries keeps the delivery log: which notification went to whom, on which channel,
+// how often it was tried and how it ended. Logging never holds up a delivery; failures to write
+// the log are only reported.
+package deliveries
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// secretTypes carry one-time codes. Their data is not logged, so they cannot be resent either;
+// the user asks for a new code instead.
+var secretTypes = map[string]bool{
+	"auth_verification": true,
+	"password_reset":    true,
+}
+
+// Resendable reports whether messages of the type can be sent again from the log. Messages that
+// could not be decoded have no type and nothing to resend.
+func Resendable(msgType string) bool {
+	return msgType != "" && !secretTypes[msgType]
+}
+
+// Message identifies the notification an attempt belongs to
+type Message struct {
+	ID       string
+	ResendOf string
+	UserID   string
+	models.NotificationMessage
+}
+
+// Record logs one attempt to send the message on the channel. sendErr is the provider error, nil
+// when the message was sent. A failed attempt is "retrying" when the message is going to be tried
+// again and "failed" when it is not.
+func Record(db *gorm.DB, m Message, channel string, sendErr error, retried bool) {
+	now := time.Now()
+	d := models.NotificationDelivery{
+		ID:        uuid.New().String(),
+		MessageID: m.ID,
+		Channel:   channel,
+		Type:      m.Type,
+		ToEmail:   m.ToEmail,
+		UserID:    m.UserID,
+		ResendOf:  m.ResendOf,
+		Attempts:  1,
+	}
+	if Resendable(m.Type) {
+		data, _ := json.Marshal(m.Data)
+		d.Data = string(data)
+	}
+
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("notification_deliveries.attempts + 1"),
+		"user_id":    d.UserID,
+		"updated_at": now,
+	}
+	switch {
+	case sendErr == nil:
+		d.Status, d.SentAt = models.DeliverySent, &now
+		updates["sent_at"] = now
+	case retried:
+		d.Status, d.LastError = models.DeliveryRetrying, sendErr.Error()
+	default:
+		d.Status, d.LastError = models.DeliveryFailed, sendErr.Error()
+	}
+	updates["status"] = d.Status
+	if sendErr != nil {
+		// A later success keeps the error, so the log still shows why it took more than one try
+		updates["last_error"] = d.LastError
+	}
+
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_id"}, {Name: "channel"}},
+		DoUpdates: clause.Assignments(updates),
+	}).Create(&d).Error
+	if err != nil {
+		log.Printf("Failed to log %s delivery of message %s: %v", channel, m.ID, err)
+	}
+}
+
+// Reject logs a message that failed for good before any channel was tried, e.g. one that could
+// not be decoded or rendered, so that it still shows up in the log
+func Reject(db *gorm.DB, m Message, err error) {
+	Record(db, m, models.ChannelNone, err, false)
+}
+
+// GiveUp marks the message's pending channels failed once it has been dead-lettered
+func GiveUp(db *gorm.DB, messageID string) {
+	err := db.Model(&models.NotificationDelivery{}).
+		Where("message_id = ? AND status = ?", messageID, models.DeliveryRetrying).
+		Update("status", models.DeliveryFailed).Error
+	if err != nil {
+		log.Printf("Failed to mark message %s failed in the delivery log: %v", messageID, err)
+	}
+}
+
+// Body rebuilds the queued message from a log entry for a resend
+func Body(d models.NotificationDelivery) ([]byte, error) {
+	msg := models.NotificationMessage{Type: d.Type, ToEmail: d.ToEmail}
+	if err := json.Unmarshal([]byte(d.Data), &msg.Data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
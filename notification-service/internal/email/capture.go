@@ -0,0 +1,67 @@
+package email
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const captureFrom = "notifications@localhost"
+
+// Captured is an email the capture backend kept instead of sending
+type Captured struct {
+	Message
+	MessageID string
+	Raw       []byte // the email as it would have gone out
+}
+
+// CaptureSender keeps every email in memory and, with a directory, also writes it there as an
+// .eml file that mail clients can open. Nothing leaves the machine.
+type CaptureSender struct {
+	dir  string
+	mu   sync.Mutex
+	sent []Captured
+}
+
+// NewCaptureSender creates the capture backend. An empty dir keeps emails in memory only.
+func NewCaptureSender(dir string) (*CaptureSender, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &CaptureSender{dir: dir}, nil
+}
+
+func (s *CaptureSender) Send(msg Message) error {
+	raw, id := build(captureFrom, msg)
+
+	if s.dir != "" {
+		name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), strings.Trim(id, "<>"))
+		if err := os.WriteFile(filepath.Join(s.dir, name), raw, 0o644); err != nil {
+			return fmt.Errorf("failed to capture email: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, Captured{Message: msg, MessageID: id, Raw: raw})
+	return nil
+}
+
+// Sent returns the captured emails, oldest first
+func (s *CaptureSender) Sent() []Captured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Captured(nil), s.sent...)
+}
+
+// Reset forgets the captured emails; files already written stay
+func (s *CaptureSender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = nil
+}
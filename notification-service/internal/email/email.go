@@ -0,0 +1,142 @@
+// Package email sends notification emails through a Sender: SMTP in production, or a capture
+// backend that keeps the messages for development and tests.
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// Attachment is a file added to an email, e.g. a calendar invite
+type Attachment struct {
+	Filename    string
+	ContentType string // e.g. "text/calendar; method=REQUEST; charset=UTF-8"
+	Data        []byte
+}
+
+// Message is one email: HTML with a plain-text alternative, followed by the attachments
+type Message struct {
+	To          string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(msg Message) error
+}
+
+// FromEnv builds the sender chosen by EMAIL_BACKEND: "smtp" (the default) or "capture". The
+// capture backend writes .eml files to EMAIL_CAPTURE_DIR, or only keeps them in memory when it
+// is not set.
+func FromEnv() (Sender, error) {
+	switch backend := os.Getenv("EMAIL_BACKEND"); backend {
+	case "", "smtp":
+		return NewSMTPSender()
+	case "capture":
+		return NewCaptureSender(os.Getenv("EMAIL_CAPTURE_DIR"))
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_BACKEND %q", backend)
+	}
+}
+
+// build writes the message in RFC 5322 format. It returns the bytes and the Message-ID.
+func build(from string, msg Message) ([]byte, string) {
+	id := messageID(from)
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", from)
+	header("To", msg.To)
+	// Subjects are localized, so they are encoded for the header (RFC 2047)
+	header("Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", id)
+	header("MIME-Version", "1.0")
+	buf.Write(multipartBody(msg.HTML, msg.Text, msg.Attachments))
+	return buf.Bytes(), id
+}
+
+// messageID makes a globally unique Message-ID in the sender's domain
+func messageID(from string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// multipartBody builds a multipart/alternative body with the plain-text and HTML versions. With
+// attachments it is wrapped in a multipart/mixed body that carries them after it. The result
+// starts with the Content-Type header.
+func multipartBody(bodyHTML, bodyText string, attachments []Attachment) []byte {
+	var alt bytes.Buffer
+	aw := multipart.NewWriter(&alt)
+	// Clients show the last alternative they support, so the HTML goes last
+	writeText(aw, `text/plain; charset="UTF-8"`, bodyText)
+	writeText(aw, `text/html; charset="UTF-8"`, bodyHTML)
+	aw.Close()
+	altType := fmt.Sprintf("multipart/alternative; boundary=%q", aw.Boundary())
+
+	var buf bytes.Buffer
+	if len(attachments) == 0 {
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", altType)
+		buf.Write(alt.Bytes())
+		return buf.Bytes()
+	}
+
+	w := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", w.Boundary())
+
+	bodyPart, _ := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {altType},
+	})
+	bodyPart.Write(alt.Bytes())
+
+	for _, a := range attachments {
+		part, _ := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", a.Filename)},
+		})
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		// Keep lines within the 76 character limit of RFC 2045
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+
+	w.Close()
+	return buf.Bytes()
+}
+
+// writeText adds a quoted-printable text part, which keeps non-ASCII text and long HTML lines
+// within the line limits of SMTP
+func writeText(w *multipart.Writer, contentType, body string) {
+	part, _ := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	qp := quotedprintable.NewWriter(part)
+	qp.Write([]byte(body))
+	qp.Close()
+}
@@ -1,10 +1,20 @@
 package email
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/smtp"
 	"os"
+	"time"
+)
+
+const (
+	SecuritySTARTTLS = "starttls" // plain connection upgraded with STARTTLS, usually port 587
+	SecurityTLS      = "tls"      // implicit TLS from the first byte, usually port 465
+
+	smtpTimeout = time.Minute
 )
 
 type loginAuth struct {
@@ -31,26 +41,103 @@ func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
 	return nil, nil
 }
 
-func SendEmail(toEmail string, subject string, bodyHTML string) error {
-	from := os.Getenv("SMTP_EMAIL")
-	password := os.Getenv("SMTP_PASSWORD")
-	host := os.Getenv("SMTP_HOST")
-	port := os.Getenv("SMTP_PORT")
+// SMTPSender sends through an SMTP server, always over TLS
+type SMTPSender struct {
+	Host     string
+	Port     string
+	From     string // also the login
+	Password string
+	Security string // SecuritySTARTTLS or SecurityTLS
+}
 
-	if host == "" || port == "" || from == "" || password == "" {
-		return fmt.Errorf("SMTP configuration is missing")
+// NewSMTPSender reads SMTP_HOST, SMTP_PORT, SMTP_EMAIL and SMTP_PASSWORD. SMTP_SECURITY picks
+// "starttls" or "tls"; by default port 465 uses implicit TLS and any other port STARTTLS.
+func NewSMTPSender() (*SMTPSender, error) {
+	s := &SMTPSender{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		From:     os.Getenv("SMTP_EMAIL"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		Security: os.Getenv("SMTP_SECURITY"),
 	}
+	if s.Host == "" || s.Port == "" || s.From == "" || s.Password == "" {
+		return nil, fmt.Errorf("SMTP configuration is missing")
+	}
+
+	switch s.Security {
+	case "":
+		s.Security = SecuritySTARTTLS
+		if s.Port == "465" {
+			s.Security = SecurityTLS
+		}
+	case SecuritySTARTTLS, SecurityTLS:
+	default:
+		return nil, fmt.Errorf("unknown SMTP_SECURITY %q", s.Security)
+	}
+	return s, nil
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	raw, _ := build(s.From, msg)
+	if err := s.send(msg.To, raw); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
 
-	addr := fmt.Sprintf("%s:%s", host, port)
-	auth := LoginAuth(from, password)
+func (s *SMTPSender) send(to string, raw []byte) error {
+	addr := net.JoinHostPort(s.Host, s.Port)
+	tlsConfig := &tls.Config{ServerName: s.Host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
 
-	mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	headers := fmt.Sprintf("From: %s\nTo: %s\nSubject: %s\n", from, toEmail, subject)
-	msg := []byte(headers + mime + bodyHTML)
+	var conn net.Conn
+	var err error
+	if s.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	// A stalled server must not hold up the consumer forever
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
 
-	if err := smtp.SendMail(addr, auth, from, []string{toEmail}, msg); err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
 	}
+	defer c.Close()
 
-	return nil
+	if s.Security == SecuritySTARTTLS {
+		// Never fall back to sending the password in the clear
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("server does not support STARTTLS")
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if err := c.Auth(LoginAuth(s.From, s.Password)); err != nil {
+		return err
+	}
+
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
 }
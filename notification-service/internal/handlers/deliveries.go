@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/notification-service/config"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/deliveries"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/retry"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+)
+
+type NotificationHandler struct {
+	RabbitConn *amqp.Connection
+}
+
+// GetDeliveries godoc
+// @Summary      Admin: Search the delivery log
+// @Description  Lists notification deliveries, newest first, one entry per message and channel. Filter by recipient (user_id or email), message type, channel or status.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id query string false "Recipient's user ID"
+// @Param        email   query string false "Recipient's email"
+// @Param        type    query string false "Message type, e.g. booking_confirmation"
+// @Param        channel query string false "Channel" Enums(email, telegram, in_app, sms, none)
+// @Param        status  query string false "Final status" Enums(sent, retrying, failed)
+// @Param        limit   query int    false "Max entries (default 100, max 500)"
+// @Success      200 {array}  models.NotificationDelivery
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /admin/notifications [get]
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	query := config.DB.Order("created_at desc").Limit(limit)
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if email := c.Query("email"); email != "" {
+		query = query.Where("to_email = ?", email)
+	}
+	if msgType := c.Query("type"); msgType != "" {
+		query = query.Where("type = ?", msgType)
+	}
+	if channel := c.Query("channel"); channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	entries := []models.NotificationDelivery{}
+	if err := query.Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetDelivery godoc
+// @Summary      Admin: Get a delivery
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Delivery ID"
+// @Success      200 {object} models.NotificationDelivery
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /admin/notifications/{id} [get]
+func (h *NotificationHandler) GetDelivery(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	delivery, ok := findDelivery(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// ResendDelivery godoc
+// @Summary      Admin: Resend a notification
+// @Description  Queues the logged message again under a new message ID. It goes out on the channels the recipient has chosen now, and its deliveries point back to the original through resend_of. Messages with one-time codes (auth_verification, password_reset) cannot be resent.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Delivery ID"
+// @Success      202 {object} models.ResendResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /admin/notifications/{id}/resend [post]
+func (h *NotificationHandler) ResendDelivery(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	delivery, ok := findDelivery(c)
+	if !ok {
+		return
+	}
+	if delivery.Type == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "This message could not be read and cannot be resent"})
+		return
+	}
+	if !deliveries.Resendable(delivery.Type) || delivery.Data == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "This message holds a one-time code and cannot be resent; the user has to request a new one"})
+		return
+	}
+
+	body, err := deliveries.Body(delivery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Logged message is unreadable"})
+		return
+	}
+
+	// A channel of its own: handlers run concurrently and confirms are tracked per channel
+	ch, err := h.RabbitConn.Channel()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Message queue unavailable"})
+		return
+	}
+	defer ch.Close()
+	if err := ch.Confirm(false); err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Message queue unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	id, err := retry.Resend(ctx, ch, delivery.MessageID, body)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Failed to queue the message"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.ResendResponse{MessageID: id})
+}
+
+func findDelivery(c *gin.Context) (models.NotificationDelivery, bool) {
+	var delivery models.NotificationDelivery
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Delivery not found"})
+		return delivery, false
+	}
+	err := config.DB.Where("id = ?", id).First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Delivery not found"})
+		return delivery, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return delivery, false
+	}
+	return delivery, true
+}
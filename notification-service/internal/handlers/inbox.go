@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/notification-service/config"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetInbox godoc
+// @Summary      Get my notifications
+// @Description  Returns the in-app inbox, newest first, one page at a time. New items also arrive live on /notifications/stream.
+// @Tags         inbox
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page   query int  false "Page, from 1 (default 1)"
+// @Param        limit  query int  false "Items per page (default 20, max 100)"
+// @Param        unread query bool false "Only unread items"
+// @Success      200 {object} models.InboxPage
+// @Failure      401 {object} models.ErrorResponse
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetInbox(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	mine := config.DB.Model(&models.InboxItem{}).Where("user_id = ?", userID)
+	result := models.InboxPage{Items: []models.InboxItem{}, Page: page, Limit: limit}
+	if err := mine.Session(&gorm.Session{}).Where("read_at IS NULL").Count(&result.Unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	query := mine
+	if unread, _ := strconv.ParseBool(c.Query("unread")); unread {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if err := query.Order("created_at desc, id").Offset((page - 1) * limit).Limit(limit).Find(&result.Items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MarkRead godoc
+// @Summary      Mark a notification as read
+// @Tags         inbox
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Inbox item ID"
+// @Success      200 {object} models.InboxItem
+// @Failure      401 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Notification not found"})
+		return
+	}
+
+	// Reading an item twice keeps the time it was first read
+	err := config.DB.Model(&models.InboxItem{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	var item models.InboxItem
+	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Notification not found"})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// MarkAllRead godoc
+// @Summary      Mark all notifications as read
+// @Tags         inbox
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.MessageResponse
+// @Failure      401 {object} models.ErrorResponse
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	err := config.DB.Model(&models.InboxItem{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "All notifications marked as read"})
+}
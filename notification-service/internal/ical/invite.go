@@ -0,0 +1,83 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/proto/icaltext"
+)
+
+const (
+	MethodRequest = "REQUEST" // add or update the event
+	MethodCancel  = "CANCEL"  // remove the event from the calendar
+)
+
+const stampLayout = "20060102T150405Z"
+
+// Invite is a single appointment sent as an iTIP message (RFC 5546)
+type Invite struct {
+	UID       string
+	Start     time.Time
+	Duration  time.Duration
+	Summary   string
+	Sequence  int
+	Organizer string // email
+	Attendee  string // email
+}
+
+// FromData reads the event fields booking-service adds to a notification under the given prefix
+// ("" or "old_"). ok is false if the message does not describe an event.
+func FromData(data map[string]string, prefix string) (Invite, bool) {
+	slotID := data[prefix+"slot_id"]
+	start, err := time.Parse(time.RFC3339, data[prefix+"start_time"])
+	if slotID == "" || err != nil {
+		return Invite{}, false
+	}
+
+	minutes, err := strconv.Atoi(data[prefix+"duration"])
+	if err != nil || minutes <= 0 {
+		minutes = 50
+	}
+	sequence, _ := strconv.Atoi(data[prefix+"sequence"])
+
+	return Invite{
+		UID:      slotID + "@kbtu-care", // same UID as the booking-service feed, so both update one event
+		Start:    start,
+		Duration: time.Duration(minutes) * time.Minute,
+		Sequence: sequence,
+	}, true
+}
+
+// Build renders the invite as a VCALENDAR with the given method
+func Build(method string, inv Invite) []byte {
+	status := "CONFIRMED"
+	if method == MethodCancel {
+		status = "CANCELLED"
+	}
+
+	var b strings.Builder
+	icaltext.WriteLine(&b, "BEGIN:VCALENDAR")
+	icaltext.WriteLine(&b, "VERSION:2.0")
+	icaltext.WriteLine(&b, "PRODID:-//KBTU Care//Notification Service//EN")
+	icaltext.WriteLine(&b, "CALSCALE:GREGORIAN")
+	icaltext.WriteLine(&b, "METHOD:"+method)
+	icaltext.WriteLine(&b, "BEGIN:VEVENT")
+	icaltext.WriteLine(&b, "UID:"+inv.UID)
+	icaltext.WriteLine(&b, "DTSTAMP:"+time.Now().UTC().Format(stampLayout))
+	icaltext.WriteLine(&b, "DTSTART:"+inv.Start.UTC().Format(stampLayout))
+	icaltext.WriteLine(&b, "DTEND:"+inv.Start.Add(inv.Duration).UTC().Format(stampLayout))
+	icaltext.WriteLine(&b, fmt.Sprintf("SEQUENCE:%d", inv.Sequence))
+	icaltext.WriteLine(&b, "SUMMARY:"+icaltext.Escape(inv.Summary))
+	if inv.Organizer != "" {
+		icaltext.WriteLine(&b, "ORGANIZER;CN=KBTU Care:mailto:"+inv.Organizer)
+	}
+	if inv.Attendee != "" {
+		icaltext.WriteLine(&b, "ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=FALSE:mailto:"+inv.Attendee)
+	}
+	icaltext.WriteLine(&b, "STATUS:"+status)
+	icaltext.WriteLine(&b, "END:VEVENT")
+	icaltext.WriteLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
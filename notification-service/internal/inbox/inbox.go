@@ -0,0 +1,58 @@
+// Package inbox keeps the in-app inbox. New items are announced on Redis, where the gateway
+// picks them up and pushes them to the user's open streams.
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// linkKeys are the data fields that hold the page a notification asks the user to open
+var linkKeys = []string{"confirm_url", "alert_url"}
+
+// Channel is the Redis channel that carries the user's new inbox items
+func Channel(userID string) string {
+	return "inbox:" + userID
+}
+
+// Link picks the page the notification points to from the message data, if any
+func Link(data map[string]string) string {
+	for _, key := range linkKeys {
+		if data[key] != "" {
+			return data[key]
+		}
+	}
+	return ""
+}
+
+// Add stores the item and announces it. A message that is already in the inbox, because it is
+// being retried, is neither stored nor announced again. rdb may be nil.
+func Add(ctx context.Context, db *gorm.DB, rdb *redis.Client, item models.InboxItem) error {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_id"}},
+		DoNothing: true,
+	}).Create(&item)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 || rdb == nil {
+		return nil
+	}
+
+	payload, _ := json.Marshal(item)
+	if err := rdb.Publish(ctx, Channel(item.UserID), payload).Err(); err != nil {
+		// The item is saved; the user sees it the next time the inbox is loaded
+		log.Printf("Failed to announce inbox item %s: %v", item.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+	ChannelInApp    = "in_app"
+	ChannelSMS      = "sms"
+	ChannelNone     = "none" // the message failed before any channel was tried
+
+	DeliverySent     = "sent"
+	DeliveryRetrying = "retrying"
+	DeliveryFailed   = "failed"
+)
+
+// NotificationDelivery is the outcome of sending one notification on one channel. A retried
+// message updates its row, so Attempts counts every try and LastError keeps the most recent
+// provider error even once the message got through.
+type NotificationDelivery struct {
+	ID        string `gorm:"type:uuid;primary_key" json:"id"`
+	MessageID string `gorm:"type:varchar(64);not null;uniqueIndex:idx_delivery_message_channel" json:"message_id"`
+	Channel   string `gorm:"type:varchar(20);not null;uniqueIndex:idx_delivery_message_channel" json:"channel" example:"email"` // email, telegram, in_app, sms or none
+	Type      string `gorm:"type:varchar(100);not null;index" json:"type" example:"booking_confirmation"`
+	ToEmail   string `gorm:"type:varchar(255);not null;index" json:"to_email"`
+	UserID    string `gorm:"type:varchar(36);index" json:"user_id,omitempty"`              // empty when the recipient has no profile
+	Status    string `gorm:"type:varchar(20);not null;index" json:"status" example:"sent"` // sent, retrying, failed
+	ResendOf  string `gorm:"type:varchar(64);index" json:"resend_of,omitempty"`            // message an admin resent
+	Data      string `gorm:"type:text" json:"-"`                                           // message data as JSON, empty when it held a one-time secret
+
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ResendResponse struct {
+	MessageID string `json:"message_id" example:"3f2a9c0d5e8b4a17b6c1d2e3f4a5b6c7"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error" example:"Delivery not found"`
+}
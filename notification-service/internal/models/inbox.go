@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// InboxItem is a notification in a user's in-app inbox. It is unread until ReadAt is set.
+type InboxItem struct {
+	ID        string     `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    string     `gorm:"type:varchar(36);not null;index:idx_inbox_user_created" json:"-"`
+	MessageID string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"` // a retried message is not added twice
+	Type      string     `gorm:"type:varchar(100);not null" json:"type" example:"booking_confirmation"`
+	Title     string     `gorm:"not null" json:"title" example:"Appointment Confirmed! ✅"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	Link      string     `json:"link,omitempty" example:"https://care.kbtu.kz/waitlist/confirm?token=..."` // where the notification asks the user to go
+	ReadAt    *time.Time `gorm:"index" json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"index:idx_inbox_user_created" json:"created_at"`
+}
+
+type InboxPage struct {
+	Items  []InboxItem `json:"items"`
+	Page   int         `json:"page" example:"1"`
+	Limit  int         `json:"limit" example:"20"`
+	Total  int64       `json:"total" example:"42"` // items matching the filter
+	Unread int64       `json:"unread" example:"3"` // unread items in the whole inbox
+}
+
+type MessageResponse struct {
+	Message string `json:"message" example:"All notifications marked as read"`
+}
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetter is a message in the dead-letter queue
+type DeadLetter struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	ToEmail   string          `json:"to_email"`
+	Retries   int             `json:"retries"`
+	LastError string          `json:"last_error"`
+	FailedAt  string          `json:"failed_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func deadLetter(d amqp.Delivery) DeadLetter {
+	dl := DeadLetter{ID: d.MessageId, Retries: Retries(d), Body: d.Body}
+	dl.LastError, _ = d.Headers[headerLastError].(string)
+	dl.FailedAt, _ = d.Headers[headerFailedAt].(string)
+
+	var msg struct {
+		Type    string `json:"type"`
+		ToEmail string `json:"to_email"`
+	}
+	if json.Unmarshal(d.Body, &msg) == nil {
+		dl.Type, dl.ToEmail = msg.Type, msg.ToEmail
+	} else {
+		dl.Body, _ = json.Marshal(string(d.Body)) // keep the bytes readable as a JSON string
+	}
+	return dl
+}
+
+// drain takes up to limit messages off the dead-letter queue without acknowledging them and
+// hands each to keep. Messages keep returns false for are acknowledged, i.e. removed; the others
+// go back into the queue when drain returns. limit <= 0 means all.
+func drain(ch *amqp.Channel, limit int, keep func(amqp.Delivery) (bool, error)) error {
+	var held []amqp.Delivery
+	defer func() {
+		for _, d := range held {
+			d.Nack(false, true)
+		}
+	}()
+
+	for limit <= 0 || len(held) < limit {
+		d, ok, err := ch.Get(DeadLetterQueue, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		k, err := keep(d)
+		if err != nil {
+			d.Nack(false, true)
+			return err
+		}
+		if !k {
+			if err := d.Ack(false); err != nil {
+				return err
+			}
+			continue
+		}
+		held = append(held, d)
+	}
+	return nil
+}
+
+// List returns up to limit dead letters, oldest first, leaving them in the queue
+func List(ch *amqp.Channel, limit int) ([]DeadLetter, error) {
+	letters := []DeadLetter{}
+	err := drain(ch, limit, func(d amqp.Delivery) (bool, error) {
+		letters = append(letters, deadLetter(d))
+		return true, nil
+	})
+	return letters, err
+}
+
+// Replay puts dead letters back onto notifications_queue with their retries reset: the one with
+// the given ID, or every one when id is empty. It returns how many were replayed. ch must be in
+// confirm mode so a message only leaves the dead-letter queue once it is safely requeued.
+func Replay(ctx context.Context, ch *amqp.Channel, id string) (int, error) {
+	replayed := 0
+	err := drain(ch, 0, func(d amqp.Delivery) (bool, error) {
+		if id != "" && d.MessageId != id {
+			return true, nil
+		}
+		msg := amqp.Publishing{
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    d.MessageId,
+			Timestamp:    time.Now(),
+			Body:         d.Body,
+		}
+		if err := publish(ctx, ch, Queue, msg); err != nil {
+			return true, err
+		}
+		replayed++
+		return false, nil
+	})
+	return replayed, err
+}
+
+// Purge drops every dead letter and returns how many there were
+func Purge(ch *amqp.Channel) (int, error) {
+	return ch.QueuePurge(DeadLetterQueue, false)
+}
@@ -0,0 +1,194 @@
+// Package retry makes notification delivery reliable. A message whose delivery fails is
+// republished to a delay queue with a per-queue TTL; when the TTL runs out RabbitMQ dead-letters
+// it back onto notifications_queue. Each retry waits twice as long as the one before. Messages
+// that fail permanently or run out of retries end up in the dead-letter queue for an admin.
+package retry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	Queue           = "notifications_queue"
+	DeadLetterQueue = "notifications_queue.dlq"
+
+	headerRetries   = "x-retries"
+	headerLastError = "x-last-error"
+	headerFailedAt  = "x-failed-at"
+	headerResendOf  = "x-resend-of"
+
+	maxDelay = time.Hour
+)
+
+// Policy bounds the retries: retry n (from 1) waits BaseDelay * 2^(n-1), at most an hour
+type Policy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// LoadPolicy reads NOTIFY_MAX_RETRIES (default 5) and NOTIFY_RETRY_BASE_SECONDS (default 30),
+// i.e. retries after 30s, 1m, 2m, 4m and 8m
+func LoadPolicy() Policy {
+	p := Policy{MaxRetries: 5, BaseDelay: 30 * time.Second}
+	if n, err := strconv.Atoi(os.Getenv("NOTIFY_MAX_RETRIES")); err == nil && n >= 0 {
+		p.MaxRetries = n
+	}
+	if s, err := strconv.Atoi(os.Getenv("NOTIFY_RETRY_BASE_SECONDS")); err == nil && s > 0 {
+		p.BaseDelay = time.Duration(s) * time.Second
+	}
+	return p
+}
+
+// Delay is how long retry n (from 1) waits
+func (p Policy) Delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// delayQueue names the queue by its TTL, so changing the policy declares new queues instead of
+// clashing with the arguments of existing ones
+func delayQueue(d time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", Queue, d.Milliseconds())
+}
+
+// Declare sets up the delay queues for the policy and the dead-letter queue
+func Declare(ch *amqp.Channel, p Policy) error {
+	for n := 1; n <= p.MaxRetries; n++ {
+		d := p.Delay(n)
+		_, err := ch.QueueDeclare(delayQueue(d), true, false, false, false, amqp.Table{
+			"x-message-ttl":             d.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": Queue,
+		})
+		if err != nil {
+			return fmt.Errorf("declare %s: %w", delayQueue(d), err)
+		}
+	}
+	if _, err := ch.QueueDeclare(DeadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare %s: %w", DeadLetterQueue, err)
+	}
+	return nil
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, e.g. a malformed message
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Retries is how often the delivery has been retried already
+func Retries(d amqp.Delivery) int {
+	switch v := d.Headers[headerRetries].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// Fail schedules the next retry of the delivery, or moves it to the dead-letter queue when the
+// error is permanent or the retries are used up. It returns the queue the message went to. The
+// caller acks the delivery once this succeeds; ch must be in confirm mode.
+func Fail(ctx context.Context, ch *amqp.Channel, d amqp.Delivery, p Policy, cause error) (string, error) {
+	queue, msg := next(d, p, cause)
+	return queue, publish(ctx, ch, queue, msg)
+}
+
+// next decides where a failed delivery goes and builds the message to publish there
+func next(d amqp.Delivery, p Policy, cause error) (string, amqp.Publishing) {
+	retries := Retries(d)
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[headerLastError] = cause.Error()
+	headers[headerFailedAt] = time.Now().UTC().Format(time.RFC3339)
+
+	queue := DeadLetterQueue
+	var permanent permanentError
+	if !errors.As(cause, &permanent) && retries < p.MaxRetries {
+		queue = delayQueue(p.Delay(retries + 1))
+		headers[headerRetries] = int32(retries + 1)
+	}
+
+	msg := amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	}
+	if msg.MessageId == "" {
+		msg.MessageId = NewID()
+	}
+	return queue, msg
+}
+
+// ResendOf is the ID of the message this one resends, empty for an original message
+func ResendOf(d amqp.Delivery) string {
+	id, _ := d.Headers[headerResendOf].(string)
+	return id
+}
+
+// Resend queues body again as a new message that remembers the original's ID. It returns the
+// new message's ID. ch must be in confirm mode.
+func Resend(ctx context.Context, ch *amqp.Channel, originalID string, body []byte) (string, error) {
+	msg := amqp.Publishing{
+		Headers:      amqp.Table{headerResendOf: originalID},
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    NewID(),
+		Timestamp:    time.Now(),
+		Body:         body,
+	}
+	return msg.MessageId, publish(ctx, ch, Queue, msg)
+}
+
+// publish sends the message through the default exchange and waits for the broker to confirm it
+func publish(ctx context.Context, ch *amqp.Channel, queue string, msg amqp.Publishing) error {
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", queue, false, false, msg)
+	if err != nil {
+		return err
+	}
+	if confirm == nil {
+		return nil // channel not in confirm mode
+	}
+	ok, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("broker rejected the message for %s", queue)
+	}
+	return nil
+}
+
+// NewID makes a message ID for messages published without one
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
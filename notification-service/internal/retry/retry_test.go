@@ -0,0 +1,145 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelay(t *testing.T) {
+	p := Policy{MaxRetries: 5, BaseDelay: 30 * time.Second}
+
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{7, 32 * time.Minute},
+		// Capped at an hour
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Delay(tt.n), "retry %d", tt.n)
+	}
+
+	// A base above the cap is capped too
+	assert.Equal(t, time.Hour, Policy{BaseDelay: 2 * time.Hour}.Delay(1))
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Setenv("NOTIFY_MAX_RETRIES", "")
+	t.Setenv("NOTIFY_RETRY_BASE_SECONDS", "")
+	assert.Equal(t, Policy{MaxRetries: 5, BaseDelay: 30 * time.Second}, LoadPolicy())
+
+	t.Setenv("NOTIFY_MAX_RETRIES", "0")
+	t.Setenv("NOTIFY_RETRY_BASE_SECONDS", "10")
+	assert.Equal(t, Policy{MaxRetries: 0, BaseDelay: 10 * time.Second}, LoadPolicy())
+
+	// Invalid values keep the defaults
+	t.Setenv("NOTIFY_MAX_RETRIES", "-1")
+	t.Setenv("NOTIFY_RETRY_BASE_SECONDS", "0")
+	assert.Equal(t, Policy{MaxRetries: 5, BaseDelay: 30 * time.Second}, LoadPolicy())
+}
+
+// redeliver turns a published message into the delivery the consumer gets back
+func redeliver(msg amqp.Publishing) amqp.Delivery {
+	return amqp.Delivery{
+		Headers:     msg.Headers,
+		ContentType: msg.ContentType,
+		MessageId:   msg.MessageId,
+		Timestamp:   msg.Timestamp,
+		Body:        msg.Body,
+	}
+}
+
+func TestNextRetriesUntilDeadLetter(t *testing.T) {
+	p := Policy{MaxRetries: 3, BaseDelay: 30 * time.Second}
+	d := amqp.Delivery{
+		Headers:     amqp.Table{headerResendOf: "original-1"},
+		ContentType: "application/json",
+		MessageId:   "msg-1",
+		Body:        []byte(`{"type": "session_reminder"}`),
+	}
+
+	var queues []string
+	for i := 1; i <= 4; i++ {
+		queue, msg := next(d, p, fmt.Errorf("smtp: attempt %d failed", i))
+		queues = append(queues, queue)
+
+		assert.Equal(t, "msg-1", msg.MessageId)
+		assert.Equal(t, d.Body, msg.Body)
+		assert.Equal(t, uint8(amqp.Persistent), msg.DeliveryMode)
+		assert.Equal(t, fmt.Sprintf("smtp: attempt %d failed", i), msg.Headers[headerLastError])
+		_, err := time.Parse(time.RFC3339, msg.Headers[headerFailedAt].(string))
+		assert.NoError(t, err)
+
+		d = redeliver(msg)
+		// The headers survive every round trip
+		assert.Equal(t, "original-1", ResendOf(d))
+	}
+
+	assert.Equal(t, []string{
+		"notifications_queue.retry.30000ms",
+		"notifications_queue.retry.60000ms",
+		"notifications_queue.retry.120000ms",
+		DeadLetterQueue,
+	}, queues)
+	assert.Equal(t, 3, Retries(d), "the dead letter keeps the count of retries made")
+}
+
+func TestNextSendsPermanentErrorsToDeadLetter(t *testing.T) {
+	p := Policy{MaxRetries: 5, BaseDelay: 30 * time.Second}
+	d := amqp.Delivery{MessageId: "msg-1", Body: []byte(`{`)}
+
+	queue, msg := next(d, p, Permanent(errors.New("decode message: unexpected end of JSON input")))
+	assert.Equal(t, DeadLetterQueue, queue)
+	assert.Equal(t, "decode message: unexpected end of JSON input", msg.Headers[headerLastError])
+	assert.NotContains(t, msg.Headers, headerRetries)
+
+	// Also when wrapped
+	queue, _ = next(d, p, fmt.Errorf("process: %w", Permanent(errors.New("unknown message type"))))
+	assert.Equal(t, DeadLetterQueue, queue)
+
+	// No retries at all
+	queue, _ = next(d, Policy{MaxRetries: 0, BaseDelay: time.Second}, errors.New("timeout"))
+	assert.Equal(t, DeadLetterQueue, queue)
+}
+
+func TestNextGivesMessagesAnID(t *testing.T) {
+	_, msg := next(amqp.Delivery{Body: []byte(`{}`)}, Policy{MaxRetries: 1, BaseDelay: time.Second}, errors.New("timeout"))
+	assert.Len(t, msg.MessageId, 32)
+}
+
+func TestRetries(t *testing.T) {
+	assert.Zero(t, Retries(amqp.Delivery{}))
+	assert.Equal(t, 2, Retries(amqp.Delivery{Headers: amqp.Table{headerRetries: int32(2)}}))
+	assert.Equal(t, 3, Retries(amqp.Delivery{Headers: amqp.Table{headerRetries: int64(3)}}))
+	assert.Equal(t, 4, Retries(amqp.Delivery{Headers: amqp.Table{headerRetries: 4}}))
+	assert.Zero(t, Retries(amqp.Delivery{Headers: amqp.Table{headerRetries: "5"}}))
+}
+
+func TestDeadLetter(t *testing.T) {
+	_, msg := next(amqp.Delivery{MessageId: "msg-1", Body: []byte(`{"type": "booking_confirmation", "to_email": "student@kbtu.kz"}`)},
+		Policy{}, errors.New("smtp: 550 mailbox unavailable"))
+
+	dl := deadLetter(redeliver(msg))
+	assert.Equal(t, "msg-1", dl.ID)
+	assert.Equal(t, "booking_confirmation", dl.Type)
+	assert.Equal(t, "student@kbtu.kz", dl.ToEmail)
+	assert.Equal(t, "smtp: 550 mailbox unavailable", dl.LastError)
+	assert.NotEmpty(t, dl.FailedAt)
+
+	// A body that is not JSON is kept as a string
+	garbled := deadLetter(amqp.Delivery{MessageId: "msg-2", Body: []byte(`{"type"`)})
+	require.Empty(t, garbled.Type)
+	assert.JSONEq(t, `"{\"type\""`, string(garbled.Body))
+}
@@ -0,0 +1,103 @@
+// Package routing decides which channels a notification goes out on, following the recipient's
+// preferences kept by user-service.
+package routing
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/sms"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// categoryOf maps the message types users can mute to their category. Types not listed are
+// essential (account codes, booking changes, risk alerts) and are always sent.
+var categoryOf = map[string]string{
+	"session_reminder":   userprofile.CategoryReminders,
+	"waitlist_hold":      userprofile.CategoryWaitlist,
+	"new_recommendation": userprofile.CategoryRecommendations,
+}
+
+// emailOnly are essential types that never go to Telegram: codes and account notices belong in the mailbox
+var emailOnly = map[string]bool{
+	"auth_verification": true,
+	"password_reset":    true,
+	"account_blocked":   true,
+}
+
+// mailboxOnly types never go to the in-app inbox: a one-time code should not outlive its email
+var mailboxOnly = map[string]bool{
+	"auth_verification": true,
+	"password_reset":    true,
+}
+
+// urgent types ignore quiet hours
+var urgent = map[string]bool{
+	"urgent_risk_alert": true,
+}
+
+// Route is where one message goes
+type Route struct {
+	UserID         string // empty when the recipient has no profile
+	Email          bool
+	Telegram       bool
+	TelegramChatID string
+	InApp          bool
+	SMS            bool
+	PhoneNumber    string
+	Silent         bool   // quiet hours: Telegram messages arrive without a sound
+	Locale         string // kk, ru or en; empty when unknown
+}
+
+// Category returns the category of the message type, or "" for essential types
+func Category(msgType string) string {
+	return categoryOf[msgType]
+}
+
+// Resolve looks up the recipient by email. When user-service does not know them or cannot be
+// reached, the message still goes out by email so nothing essential is lost.
+func Resolve(ctx context.Context, client userprofile.UserProfileServiceClient, msg models.NotificationMessage) Route {
+	fallback := Route{Email: true}
+	if client == nil || msg.ToEmail == "" {
+		return fallback
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	prefs, err := client.GetNotificationPreferences(ctx, &userprofile.GetNotificationPreferencesRequest{Email: msg.ToEmail})
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			log.Printf("Failed to load notification preferences for %s, sending email only: %v", msg.ToEmail, err)
+		}
+		return fallback
+	}
+
+	route := Route{
+		UserID:         prefs.UserId,
+		Email:          true,
+		Telegram:       !emailOnly[msg.Type],
+		TelegramChatID: prefs.TelegramChatId,
+		InApp:          !mailboxOnly[msg.Type],
+		Silent:         prefs.InQuietHours && !urgent[msg.Type],
+		Locale:         prefs.Locale,
+		PhoneNumber:    prefs.PhoneNumber,
+	}
+	// A text message cannot arrive silently, so quiet hours hold it back; the other channels
+	// still carry the message
+	route.SMS = prefs.Sms && sms.Valid(prefs.PhoneNumber) && !route.Silent
+	if category := Category(msg.Type); category != "" {
+		channels := prefs.Categories[category]
+		route.Email = channels.GetEmail()
+		route.Telegram = channels.GetTelegram()
+		route.InApp = channels.GetInApp()
+	}
+	if route.TelegramChatID == "" {
+		route.Telegram = false
+	}
+	return route
+}
@@ -0,0 +1,181 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pokonti/psychologist-backend/notification-service/internal/models"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MockUserClient mocks the gRPC client; only the notification preferences are used here
+type MockUserClient struct {
+	userprofile.UserProfileServiceClient
+	mock.Mock
+}
+
+func (m *MockUserClient) GetNotificationPreferences(ctx context.Context, in *userprofile.GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*userprofile.GetNotificationPreferencesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userprofile.GetNotificationPreferencesResponse), args.Error(1)
+}
+
+func clientWith(prefs *userprofile.GetNotificationPreferencesResponse, err error) *MockUserClient {
+	client := new(MockUserClient)
+	client.On("GetNotificationPreferences", mock.Anything, mock.Anything).Return(prefs, err)
+	return client
+}
+
+// linked is a user with a Telegram chat, every category on and no quiet hours
+func linked() *userprofile.GetNotificationPreferencesResponse {
+	on := &userprofile.ChannelPreferences{Email: true, Telegram: true, InApp: true}
+	return &userprofile.GetNotificationPreferencesResponse{
+		UserId:         "user-1",
+		TelegramChatId: "12345",
+		Locale:         "ru",
+		PhoneNumber:    "+77011234567",
+		Categories: map[string]*userprofile.ChannelPreferences{
+			userprofile.CategoryReminders:       on,
+			userprofile.CategoryWaitlist:        on,
+			userprofile.CategoryRecommendations: on,
+			userprofile.CategoryMarketing:       {},
+		},
+	}
+}
+
+func TestResolveFallsBackToEmail(t *testing.T) {
+	msg := models.NotificationMessage{Type: "booking_confirmation", ToEmail: "user@test.com"}
+	emailOnly := Route{Email: true}
+
+	assert.Equal(t, emailOnly, Resolve(context.Background(), nil, msg))
+	assert.Equal(t, emailOnly, Resolve(context.Background(), clientWith(linked(), nil), models.NotificationMessage{Type: msg.Type}))
+	assert.Equal(t, emailOnly, Resolve(context.Background(), clientWith(nil, status.Error(codes.NotFound, "user not found")), msg))
+	assert.Equal(t, emailOnly, Resolve(context.Background(), clientWith(nil, errors.New("connection refused")), msg))
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   func(p *userprofile.GetNotificationPreferencesResponse)
+		msgType string
+		want    Route
+	}{
+		{
+			name:    "essential types use every channel",
+			msgType: "booking_confirmation",
+			want:    Route{Email: true, Telegram: true, InApp: true},
+		},
+		{
+			name: "essential types ignore muted categories",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				p.Categories[userprofile.CategoryReminders] = &userprofile.ChannelPreferences{}
+			},
+			msgType: "booking_cancellation",
+			want:    Route{Email: true, Telegram: true, InApp: true},
+		},
+		{
+			name:    "codes stay in the mailbox",
+			msgType: "password_reset",
+			want:    Route{Email: true},
+		},
+		{
+			name:    "account notices skip Telegram",
+			msgType: "account_blocked",
+			want:    Route{Email: true, InApp: true},
+		},
+		{
+			name: "category channels",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				p.Categories[userprofile.CategoryReminders] = &userprofile.ChannelPreferences{InApp: true}
+			},
+			msgType: "session_reminder",
+			want:    Route{InApp: true},
+		},
+		{
+			name: "muted category",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				delete(p.Categories, userprofile.CategoryWaitlist)
+			},
+			msgType: "waitlist_hold",
+			want:    Route{},
+		},
+		{
+			name:    "no Telegram without a linked chat",
+			prefs:   func(p *userprofile.GetNotificationPreferencesResponse) { p.TelegramChatId = "" },
+			msgType: "new_recommendation",
+			want:    Route{Email: true, InApp: true},
+		},
+		{
+			name:    "SMS when opted in",
+			prefs:   func(p *userprofile.GetNotificationPreferencesResponse) { p.Sms = true },
+			msgType: "session_reminder",
+			want:    Route{Email: true, Telegram: true, InApp: true, SMS: true},
+		},
+		{
+			name: "no SMS to an invalid number",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				p.Sms = true
+				p.PhoneNumber = "8 701 123 45 67"
+			},
+			msgType: "session_reminder",
+			want:    Route{Email: true, Telegram: true, InApp: true},
+		},
+		{
+			name: "quiet hours silence Telegram and hold back SMS",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				p.Sms = true
+				p.InQuietHours = true
+			},
+			msgType: "session_reminder",
+			want:    Route{Email: true, Telegram: true, InApp: true, Silent: true},
+		},
+		{
+			name: "urgent alerts ignore quiet hours",
+			prefs: func(p *userprofile.GetNotificationPreferencesResponse) {
+				p.Sms = true
+				p.InQuietHours = true
+			},
+			msgType: "urgent_risk_alert",
+			want:    Route{Email: true, Telegram: true, InApp: true, SMS: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := linked()
+			if tt.prefs != nil {
+				tt.prefs(prefs)
+			}
+			client := clientWith(prefs, nil)
+
+			got := Resolve(context.Background(), client, models.NotificationMessage{Type: tt.msgType, ToEmail: "user@test.com"})
+
+			want := tt.want
+			want.UserID = "user-1"
+			want.TelegramChatID = prefs.TelegramChatId
+			want.PhoneNumber = prefs.PhoneNumber
+			want.Locale = "ru"
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, userprofile.CategoryReminders, Category("session_reminder"))
+	assert.Equal(t, userprofile.CategoryWaitlist, Category("waitlist_hold"))
+	assert.Equal(t, userprofile.CategoryRecommendations, Category("new_recommendation"))
+	assert.Empty(t, Category("booking_confirmation"))
+
+	// Every category a message maps to is one users can set
+	for msgType, category := range categoryOf {
+		assert.Contains(t, userprofile.NotificationCategories, category, msgType)
+	}
+}
@@ -0,0 +1,123 @@
+// Package sms sends text messages through a Provider: Twilio in production, or a stub that only
+// logs and keeps them for development and tests.
+package sms
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// Valid reports whether the number is in E.164 format, e.g. +77011234567
+func Valid(number string) bool {
+	return e164.MatchString(number)
+}
+
+// Provider sends a text message to an E.164 number
+type Provider interface {
+	Send(to, text string) error
+}
+
+// FromEnv builds the provider chosen by SMS_PROVIDER: "stub" (the default) or "twilio"
+func FromEnv() (Provider, error) {
+	switch provider := os.Getenv("SMS_PROVIDER"); provider {
+	case "", "stub":
+		log.Println("SMS provider is the stub: text messages are logged, not sent")
+		return NewStubProvider(), nil
+	case "twilio":
+		return NewTwilioProvider()
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q", provider)
+	}
+}
+
+// Message is a text message the stub kept instead of sending
+type Message struct {
+	To   string
+	Text string
+}
+
+// StubProvider logs every message and keeps it in memory. Nothing leaves the machine.
+type StubProvider struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewStubProvider() *StubProvider {
+	return &StubProvider{}
+}
+
+func (p *StubProvider) Send(to, text string) error {
+	log.Printf("SMS to %s: %s", to, text)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, Message{To: to, Text: text})
+	return nil
+}
+
+// Sent returns the kept messages, oldest first
+func (p *StubProvider) Sent() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.sent...)
+}
+
+// TwilioProvider sends through the Twilio Messages API
+type TwilioProvider struct {
+	AccountSID string
+	AuthToken  string
+	From       string // sender number or alphanumeric sender ID
+	Client     *http.Client
+}
+
+// NewTwilioProvider reads TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and SMS_FROM
+func NewTwilioProvider() (*TwilioProvider, error) {
+	p := &TwilioProvider{
+		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		From:       os.Getenv("SMS_FROM"),
+		Client:     &http.Client{Timeout: 30 * time.Second},
+	}
+	if p.AccountSID == "" || p.AuthToken == "" || p.From == "" {
+		return nil, fmt.Errorf("Twilio configuration is missing")
+	}
+	return p, nil
+}
+
+func (p *TwilioProvider) Send(to, text string) error {
+	form := url.Values{"To": {to}, "From": {p.From}, "Body": {text}}
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", p.AccountSID)
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.AccountSID, p.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Keep Twilio's reason, e.g. "The 'To' number is not a valid phone number", for the delivery log
+		var result struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("failed to send SMS, status: %d %s", resp.StatusCode, result.Message)
+	}
+	return nil
+}
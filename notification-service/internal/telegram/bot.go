@@ -8,7 +8,8 @@ import (
 	"os"
 )
 
-func SendMessage(chatID string, text string) error {
+// SendMessage sends an HTML message to the chat. Silent messages arrive without a sound.
+func SendMessage(chatID string, text string, silent bool) error {
 	token := os.Getenv("TELEGRAM_BOT_TOKEN")
 	if token == "" || chatID == "" {
 		return fmt.Errorf("missing token or chat_id")
@@ -16,10 +17,11 @@ func SendMessage(chatID string, text string) error {
 
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", token)
 
-	payload := map[string]string{
-		"chat_id":    chatID,
-		"text":       text,
-		"parse_mode": "HTML",
+	payload := map[string]any{
+		"chat_id":              chatID,
+		"text":                 text,
+		"parse_mode":           "HTML",
+		"disable_notification": silent,
 	}
 	jsonPayload, _ := json.Marshal(payload)
 
@@ -30,7 +32,12 @@ func SendMessage(chatID string, text string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to send telegram message, status: %d", resp.StatusCode)
+		// Keep Telegram's reason, e.g. "Forbidden: bot was blocked by the user", for the delivery log
+		var result struct {
+			Description string `json:"description"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("failed to send telegram message, status: %d %s", resp.StatusCode, result.Description)
 	}
 	return nil
 }
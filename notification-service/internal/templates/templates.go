@@ -0,0 +1,156 @@
+// Package templates renders notifications from template files so wording can change without a
+// rebuild.
+//
+// The directory holds layout.tmpl, shared by every message, and one directory per locale with
+// common.tmpl (the locale's "lang" and "footer") and a <type>.tmpl per message type. A message
+// template defines "subject", "content_html" and "content_text", "telegram" when the message can
+// be sent over Telegram and "sms" when it can be sent by SMS. Every file is parsed twice: with
+// html/template for the HTML body and the Telegram text, so values are escaped, and with
+// text/template for the subject, the plain-text body and the SMS.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+const DefaultLocale = "en"
+
+// Locales are the languages notifications are written in
+var Locales = []string{"kk", "ru", "en"}
+
+var ErrUnknownType = errors.New("no template for message type")
+
+// Rendered is one message in one language
+type Rendered struct {
+	Subject  string
+	HTML     string
+	Text     string
+	Content  string // the plain-text message without the layout, for the in-app inbox
+	Telegram string // empty when the message type is not sent over Telegram
+	SMS      string // empty when the message type is not sent by SMS
+}
+
+type message struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// Set holds the parsed templates by locale and message type
+type Set struct {
+	messages map[string]map[string]message
+}
+
+// Dir is TEMPLATES_DIR, by default "templates" in the working directory
+func Dir() string {
+	if dir := os.Getenv("TEMPLATES_DIR"); dir != "" {
+		return dir
+	}
+	return "templates"
+}
+
+// Load parses every template under dir. The default locale must exist; other locales fall back
+// to it for message types they do not have.
+func Load(dir string) (*Set, error) {
+	layout := filepath.Join(dir, "layout.tmpl")
+	set := &Set{messages: map[string]map[string]message{}}
+
+	for _, locale := range Locales {
+		localeDir := filepath.Join(dir, locale)
+		files, err := filepath.Glob(filepath.Join(localeDir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		common := filepath.Join(localeDir, "common.tmpl")
+
+		set.messages[locale] = map[string]message{}
+		for _, file := range files {
+			if file == common {
+				continue
+			}
+			msgType := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+
+			h, err := htmltemplate.New(msgType).Option("missingkey=zero").ParseFiles(layout, common, file)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", locale, msgType, err)
+			}
+			t, err := texttemplate.New(msgType).Option("missingkey=zero").ParseFiles(layout, common, file)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", locale, msgType, err)
+			}
+			for _, name := range []string{"subject", "content_html", "content_text", "lang", "footer"} {
+				if t.Lookup(name) == nil {
+					return nil, fmt.Errorf("%s/%s: %q is not defined", locale, msgType, name)
+				}
+			}
+			set.messages[locale][msgType] = message{html: h, text: t}
+		}
+	}
+
+	if len(set.messages[DefaultLocale]) == 0 {
+		return nil, fmt.Errorf("no templates for the default locale %q in %s", DefaultLocale, dir)
+	}
+	return set, nil
+}
+
+// Render writes the message in the locale, or in the default locale when the locale is unknown
+// or has no template for the type. Missing values render as empty strings.
+func (s *Set) Render(msgType, locale string, data map[string]string) (Rendered, error) {
+	m, ok := s.messages[locale][msgType]
+	if !ok {
+		m, ok = s.messages[DefaultLocale][msgType]
+	}
+	if !ok {
+		return Rendered{}, ErrUnknownType
+	}
+
+	var r Rendered
+	var err error
+	if r.Subject, err = executeText(m.text, "subject", data); err != nil {
+		return r, err
+	}
+	// A subject is a single header line
+	r.Subject = strings.Join(strings.Fields(r.Subject), " ")
+
+	if r.Text, err = executeText(m.text, "text", data); err != nil {
+		return r, err
+	}
+	if r.Content, err = executeText(m.text, "content_text", data); err != nil {
+		return r, err
+	}
+	if r.HTML, err = executeHTML(m.html, "html", data); err != nil {
+		return r, err
+	}
+	if m.html.Lookup("telegram") != nil {
+		if r.Telegram, err = executeHTML(m.html, "telegram", data); err != nil {
+			return r, err
+		}
+	}
+	if m.text.Lookup("sms") != nil {
+		if r.SMS, err = executeText(m.text, "sms", data); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+func executeText(t *texttemplate.Template, name string, data map[string]string) (string, error) {
+	var b strings.Builder
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func executeHTML(t *htmltemplate.Template, name string, data map[string]string) (string, error) {
+	var b strings.Builder
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,97 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const templatesDir = "../../templates"
+
+func TestRenderEscapesValues(t *testing.T) {
+	set, err := Load(templatesDir)
+	require.NoError(t, err)
+
+	name := `<script>alert("x")</script>`
+	escaped := `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`
+
+	for _, locale := range Locales {
+		t.Run(locale, func(t *testing.T) {
+			r, err := set.Render("booking_cancellation_by_psychologist", locale, map[string]string{
+				"psychologist_name": name,
+				"datetime":          "Mon, 02 Mar 2026 at 10:00",
+			})
+			require.NoError(t, err)
+
+			assert.NotContains(t, r.HTML, "<script>")
+			assert.Contains(t, r.HTML, escaped)
+			assert.NotContains(t, r.Telegram, "<script>")
+			assert.Contains(t, r.Telegram, escaped)
+
+			// Plain text is not HTML, so the value stays as it was written
+			assert.Contains(t, r.Text, name)
+			assert.Contains(t, r.Content, name)
+			assert.Contains(t, r.SMS, name)
+		})
+	}
+
+	r, err := set.Render("account_blocked", "en", map[string]string{"reason": `<img src=x onerror="steal()">`})
+	require.NoError(t, err)
+	assert.NotContains(t, r.HTML, "<img")
+	assert.Contains(t, r.HTML, "&lt;img src=x onerror=&#34;steal()&#34;&gt;")
+	assert.Contains(t, r.Text, `<img src=x onerror="steal()">`)
+}
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("layout.tmpl", `{{define "html"}}{{template "content_html" .}}{{end}}{{define "text"}}{{template "content_text" .}}{{end}}`)
+	write("en/common.tmpl", `{{define "lang"}}en{{end}}{{define "footer"}}{{end}}`)
+	write("ru/common.tmpl", `{{define "lang"}}ru{{end}}{{define "footer"}}{{end}}`)
+	write("en/greeting.tmpl", `{{define "subject"}}Hello {{.name}}{{end}}{{define "content_html"}}<p>Hello {{.name}}</p>{{end}}{{define "content_text"}}Hello {{.name}}{{end}}`)
+	write("en/farewell.tmpl", `{{define "subject"}}Bye{{end}}{{define "content_html"}}<p>Bye</p>{{end}}{{define "content_text"}}Bye{{end}}`)
+	write("ru/farewell.tmpl", `{{define "subject"}}Пока{{end}}{{define "content_html"}}<p>Пока</p>{{end}}{{define "content_text"}}Пока{{end}}`)
+
+	set, err := Load(dir)
+	require.NoError(t, err)
+
+	// A locale without the type
+	r, err := set.Render("greeting", "ru", map[string]string{"name": "Dana"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Dana", r.Subject)
+
+	// An unknown locale
+	r, err = set.Render("farewell", "de", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Bye", r.Subject)
+
+	// The locale's own template when it has one
+	r, err = set.Render("farewell", "ru", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Пока", r.Subject)
+
+	// Missing values render empty, types without a Telegram or SMS template get none
+	r, err = set.Render("greeting", "en", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", r.Subject)
+	assert.Empty(t, r.Telegram)
+	assert.Empty(t, r.SMS)
+
+	_, err = set.Render("no_such_type", "ru", nil)
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
+
+func TestLoadRequiresDefaultLocale(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "layout.tmpl"), []byte(`{{define "html"}}{{end}}`), 0o644))
+
+	_, err := Load(dir)
+	assert.ErrorContains(t, err, "default locale")
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/notification-service/internal/handlers"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+func SetupRoutes(r *gin.Engine, h *handlers.NotificationHandler) {
+
+	api := r.Group("/api/v1")
+	{
+		// In-app inbox; the live stream is served by the gateway
+		inbox := api.Group("/notifications")
+		{
+			inbox.GET("", h.GetInbox)
+			inbox.POST("/read-all", h.MarkAllRead)
+			inbox.POST("/:id/read", h.MarkRead)
+		}
+
+		admin := api.Group("/admin/notifications")
+		{
+			admin.GET("", h.GetDeliveries)
+			admin.GET("/:id", h.GetDelivery)
+			admin.POST("/:id/resend", h.ResendDelivery)
+		}
+	}
+
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+}
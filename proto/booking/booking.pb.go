@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.20.3
+// source: proto/booking/booking.proto
+
+package booking
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Booked sessions of a user that have not started yet, soonest first.
+type GetUpcomingAppointmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"` // "student" or "psychologist"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUpcomingAppointmentsRequest) Reset() {
+	*x = GetUpcomingAppointmentsRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUpcomingAppointmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUpcomingAppointmentsRequest) ProtoMessage() {}
+
+func (x *GetUpcomingAppointmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUpcomingAppointmentsRequest.ProtoReflect.Descriptor instead.
+func (*GetUpcomingAppointmentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUpcomingAppointmentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUpcomingAppointmentsRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Appointment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlotId        string                 `protobuf:"bytes,1,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	StartTime     string                 `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // RFC 3339
+	BookingType   string                 `protobuf:"bytes,3,opt,name=booking_type,json=bookingType,proto3" json:"booking_type,omitempty"`
+	With          string                 `protobuf:"bytes,4,opt,name=with,proto3" json:"with,omitempty"` // the psychologist for students, the student for psychologists
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Appointment) Reset() {
+	*x = Appointment{}
+	mi := &file_proto_booking_booking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Appointment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Appointment) ProtoMessage() {}
+
+func (x *Appointment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Appointment.ProtoReflect.Descriptor instead.
+func (*Appointment) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Appointment) GetSlotId() string {
+	if x != nil {
+		return x.SlotId
+	}
+	return ""
+}
+
+func (x *Appointment) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Appointment) GetBookingType() string {
+	if x != nil {
+		return x.BookingType
+	}
+	return ""
+}
+
+func (x *Appointment) GetWith() string {
+	if x != nil {
+		return x.With
+	}
+	return ""
+}
+
+type GetUpcomingAppointmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Appointments  []*Appointment         `protobuf:"bytes,1,rep,name=appointments,proto3" json:"appointments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUpcomingAppointmentsResponse) Reset() {
+	*x = GetUpcomingAppointmentsResponse{}
+	mi := &file_proto_booking_booking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUpcomingAppointmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUpcomingAppointmentsResponse) ProtoMessage() {}
+
+func (x *GetUpcomingAppointmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUpcomingAppointmentsResponse.ProtoReflect.Descriptor instead.
+func (*GetUpcomingAppointmentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUpcomingAppointmentsResponse) GetAppointments() []*Appointment {
+	if x != nil {
+		return x.Appointments
+	}
+	return nil
+}
+
+var File_proto_booking_booking_proto protoreflect.FileDescriptor
+
+const file_proto_booking_booking_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/booking/booking.proto\x12\abooking\"M\n" +
+	"\x1eGetUpcomingAppointmentsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"|\n" +
+	"\vAppointment\x12\x17\n" +
+	"\aslot_id\x18\x01 \x01(\tR\x06slotId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\tR\tstartTime\x12!\n" +
+	"\fbooking_type\x18\x03 \x01(\tR\vbookingType\x12\x12\n" +
+	"\x04with\x18\x04 \x01(\tR\x04with\"[\n" +
+	"\x1fGetUpcomingAppointmentsResponse\x128\n" +
+	"\fappointments\x18\x01 \x03(\v2\x14.booking.AppointmentR\fappointments2~\n" +
+	"\x0eBookingService\x12l\n" +
+	"\x17GetUpcomingAppointments\x12'.booking.GetUpcomingAppointmentsRequest\x1a(.booking.GetUpcomingAppointmentsResponseB?Z=github.com/pokonti/psychologist-backend/proto/booking;bookingb\x06proto3"
+
+var (
+	file_proto_booking_booking_proto_rawDescOnce sync.Once
+	file_proto_booking_booking_proto_rawDescData []byte
+)
+
+func file_proto_booking_booking_proto_rawDescGZIP() []byte {
+	file_proto_booking_booking_proto_rawDescOnce.Do(func() {
+		file_proto_booking_booking_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_booking_booking_proto_rawDesc), len(file_proto_booking_booking_proto_rawDesc)))
+	})
+	return file_proto_booking_booking_proto_rawDescData
+}
+
+var file_proto_booking_booking_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_booking_booking_proto_goTypes = []any{
+	(*GetUpcomingAppointmentsRequest)(nil),  // 0: booking.GetUpcomingAppointmentsRequest
+	(*Appointment)(nil),                     // 1: booking.Appointment
+	(*GetUpcomingAppointmentsResponse)(nil), // 2: booking.GetUpcomingAppointmentsResponse
+}
+var file_proto_booking_booking_proto_depIdxs = []int32{
+	1, // 0: booking.GetUpcomingAppointmentsResponse.appointments:type_name -> booking.Appointment
+	0, // 1: booking.BookingService.GetUpcomingAppointments:input_type -> booking.GetUpcomingAppointmentsRequest
+	2, // 2: booking.BookingService.GetUpcomingAppointments:output_type -> booking.GetUpcomingAppointmentsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_booking_booking_proto_init() }
+func file_proto_booking_booking_proto_init() {
+	if File_proto_booking_booking_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_booking_booking_proto_rawDesc), len(file_proto_booking_booking_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_booking_booking_proto_goTypes,
+		DependencyIndexes: file_proto_booking_booking_proto_depIdxs,
+		MessageInfos:      file_proto_booking_booking_proto_msgTypes,
+	}.Build()
+	File_proto_booking_booking_proto = out.File
+	file_proto_booking_booking_proto_goTypes = nil
+	file_proto_booking_booking_proto_depIdxs = nil
+}
@@ -0,0 +1,37 @@
+// Package icaltext holds the RFC 5545 text rules shared by the booking-service calendar feed
+// and the notification-service invites, so both write the same bytes.
+package icaltext
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows, without the CRLF
+const maxLineOctets = 75
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Escape applies RFC 5545 TEXT escaping
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// WriteLine writes a content line, folded at 75 octets as RFC 5545 requires.
+// The space that starts a continuation line counts toward the limit, so continuations carry 74 octets.
+func WriteLine(b *strings.Builder, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// Do not split a multi-byte UTF-8 character
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,80 @@
+package icaltext
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// unfold reverses WriteLine: CRLF followed by a space joins two physical lines
+func unfold(s string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n")
+}
+
+func TestWriteLineFolding(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+	}{
+		{"short", "SUMMARY:Session"},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("a", 63)},
+		{"76 octets", "DESCRIPTION:" + strings.Repeat("a", 64)},
+		{"several continuations", "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{"multi-byte", "SUMMARY:" + strings.Repeat("Сессия с психологом ", 10)},
+		{"multi-byte across the 74 octet boundary", "X:" + strings.Repeat("a", 73) + strings.Repeat("ж", 80)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			WriteLine(&b, tc.in)
+			out := b.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line does not end with CRLF: %q", out)
+			}
+			physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, l := range physical {
+				if len(l) > 75 {
+					t.Errorf("line %d is %d octets: %q", i, len(l), l)
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, l)
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, l)
+				}
+			}
+			if len(tc.in) <= 75 && len(physical) != 1 {
+				t.Errorf("a %d octet line was folded", len(tc.in))
+			}
+			if got := unfold(out); got != tc.in {
+				t.Errorf("unfolded line differs:\n got %q\nwant %q", got, tc.in)
+			}
+		})
+	}
+}
+
+func TestWriteLineUsesFullContinuation(t *testing.T) {
+	var b strings.Builder
+	WriteLine(&b, strings.Repeat("a", 75+74+1))
+	physical := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+
+	want := []int{75, 75, 2}
+	if len(physical) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(physical), len(want), physical)
+	}
+	for i, l := range physical {
+		if len(l) != want[i] {
+			t.Errorf("line %d is %d octets, want %d", i, len(l), want[i])
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := Escape("Room 5; floor 2, C:\\path\r\nsecond\nthird")
+	want := `Room 5\; floor 2\, C:\\path\nsecond\nthird`
+	if got != want {
+		t.Errorf("Escape = %q, want %q", got, want)
+	}
+}
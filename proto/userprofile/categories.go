@@ -0,0 +1,13 @@
+package userprofile
+
+// Notification categories, the keys of GetNotificationPreferencesResponse.Categories.
+// user-service stores preferences under these names and notification-service routes by them.
+const (
+	CategoryReminders       = "reminders"
+	CategoryWaitlist        = "waitlist"
+	CategoryRecommendations = "recommendations"
+	CategoryMarketing       = "marketing"
+)
+
+// NotificationCategories are the kinds of notifications a user can route or mute
+var NotificationCategories = []string{CategoryReminders, CategoryWaitlist, CategoryRecommendations, CategoryMarketing}
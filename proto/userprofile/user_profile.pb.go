@@ -187,6 +187,7 @@ type GetUserProfileByIDResponse struct {
 	Email          string                 `protobuf:"bytes,6,opt,name=email,proto3" json:"email,omitempty"`
 	Phone          string                 `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
 	TelegramChatId string                 `protobuf:"bytes,8,opt,name=telegram_chat_id,json=telegramChatId,proto3" json:"telegram_chat_id,omitempty"`
+	TimeZone       string                 `protobuf:"bytes,9,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA name, e.g. "Asia/Almaty"
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -277,6 +278,13 @@ func (x *GetUserProfileByIDResponse) GetTelegramChatId() string {
 	return ""
 }
 
+func (x *GetUserProfileByIDResponse) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
 // Request: List of UUIDs
 type GetBatchUserProfilesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -327,7 +335,8 @@ type BasicUserProfile struct {
 	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	FullName       string                 `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
 	Email          string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	TelegramChatId string                 `protobuf:"bytes,4,opt,name=telegram_chat_id,json=telegramChatId,proto3" json:"telegram_chat_id,omitempty"` // Add photo_url here later if you have it
+	TelegramChatId string                 `protobuf:"bytes,4,opt,name=telegram_chat_id,json=telegramChatId,proto3" json:"telegram_chat_id,omitempty"`
+	TimeZone       string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA name, e.g. "Asia/Almaty"
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -390,6 +399,13 @@ func (x *BasicUserProfile) GetTelegramChatId() string {
 	return ""
 }
 
+func (x *BasicUserProfile) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
 type GetBatchUserProfilesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Profiles      []*BasicUserProfile    `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
@@ -530,28 +546,32 @@ func (x *UpdateUserPhoneResponse) GetSuccess() bool {
 	return false
 }
 
-type UpdateUserTelegramRequest struct {
+// Mood logs the student shared with the psychologist.
+// Fails with PERMISSION_DENIED when the student has not granted (or has revoked) consent.
+type GetConsentedMoodLogsRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	TelegramChatId string                 `protobuf:"bytes,2,opt,name=telegram_chat_id,json=telegramChatId,proto3" json:"telegram_chat_id,omitempty"`
+	StudentId      string                 `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	PsychologistId string                 `protobuf:"bytes,2,opt,name=psychologist_id,json=psychologistId,proto3" json:"psychologist_id,omitempty"`
+	From           string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"` // YYYY-MM-DD, inclusive
+	To             string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`     // YYYY-MM-DD, inclusive
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *UpdateUserTelegramRequest) Reset() {
-	*x = UpdateUserTelegramRequest{}
+func (x *GetConsentedMoodLogsRequest) Reset() {
+	*x = GetConsentedMoodLogsRequest{}
 	mi := &file_proto_userprofile_user_profile_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserTelegramRequest) String() string {
+func (x *GetConsentedMoodLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserTelegramRequest) ProtoMessage() {}
+func (*GetConsentedMoodLogsRequest) ProtoMessage() {}
 
-func (x *UpdateUserTelegramRequest) ProtoReflect() protoreflect.Message {
+func (x *GetConsentedMoodLogsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_userprofile_user_profile_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -563,46 +583,66 @@ func (x *UpdateUserTelegramRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserTelegramRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserTelegramRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetConsentedMoodLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetConsentedMoodLogsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UpdateUserTelegramRequest) GetId() string {
+func (x *GetConsentedMoodLogsRequest) GetStudentId() string {
 	if x != nil {
-		return x.Id
+		return x.StudentId
 	}
 	return ""
 }
 
-func (x *UpdateUserTelegramRequest) GetTelegramChatId() string {
+func (x *GetConsentedMoodLogsRequest) GetPsychologistId() string {
 	if x != nil {
-		return x.TelegramChatId
+		return x.PsychologistId
 	}
 	return ""
 }
 
-type UpdateUserTelegramResponse struct {
+func (x *GetConsentedMoodLogsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetConsentedMoodLogsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type MoodEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LoggedAt      string                 `protobuf:"bytes,2,opt,name=logged_at,json=loggedAt,proto3" json:"logged_at,omitempty"` // RFC 3339
+	Date          string                 `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`                         // YYYY-MM-DD in the student's time zone
+	Mood          string                 `protobuf:"bytes,4,opt,name=mood,proto3" json:"mood,omitempty"`
+	Score         int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	Note          string                 `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"` // only when the student shares journal notes
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserTelegramResponse) Reset() {
-	*x = UpdateUserTelegramResponse{}
+func (x *MoodEntry) Reset() {
+	*x = MoodEntry{}
 	mi := &file_proto_userprofile_user_profile_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserTelegramResponse) String() string {
+func (x *MoodEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserTelegramResponse) ProtoMessage() {}
+func (*MoodEntry) ProtoMessage() {}
 
-func (x *UpdateUserTelegramResponse) ProtoReflect() protoreflect.Message {
+func (x *MoodEntry) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_userprofile_user_profile_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -614,18 +654,310 @@ func (x *UpdateUserTelegramResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserTelegramResponse.ProtoReflect.Descriptor instead.
-func (*UpdateUserTelegramResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use MoodEntry.ProtoReflect.Descriptor instead.
+func (*MoodEntry) Descriptor() ([]byte, []int) {
 	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UpdateUserTelegramResponse) GetSuccess() bool {
+func (x *MoodEntry) GetId() string {
 	if x != nil {
-		return x.Success
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoodEntry) GetLoggedAt() string {
+	if x != nil {
+		return x.LoggedAt
+	}
+	return ""
+}
+
+func (x *MoodEntry) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *MoodEntry) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *MoodEntry) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *MoodEntry) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *MoodEntry) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type GetConsentedMoodLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*MoodEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NotesShared   bool                   `protobuf:"varint,2,opt,name=notes_shared,json=notesShared,proto3" json:"notes_shared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConsentedMoodLogsResponse) Reset() {
+	*x = GetConsentedMoodLogsResponse{}
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConsentedMoodLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsentedMoodLogsResponse) ProtoMessage() {}
+
+func (x *GetConsentedMoodLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsentedMoodLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetConsentedMoodLogsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetConsentedMoodLogsResponse) GetEntries() []*MoodEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetConsentedMoodLogsResponse) GetNotesShared() bool {
+	if x != nil {
+		return x.NotesShared
+	}
+	return false
+}
+
+// How a user wants to be notified. Messages are addressed by email, so the user is looked up by it.
+// Fails with NOT_FOUND when no profile has the email (e.g. before registration is finished).
+type GetNotificationPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationPreferencesRequest) Reset() {
+	*x = GetNotificationPreferencesRequest{}
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationPreferencesRequest) ProtoMessage() {}
+
+func (x *GetNotificationPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetNotificationPreferencesRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ChannelPreferences struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         bool                   `protobuf:"varint,1,opt,name=email,proto3" json:"email,omitempty"`
+	Telegram      bool                   `protobuf:"varint,2,opt,name=telegram,proto3" json:"telegram,omitempty"`
+	InApp         bool                   `protobuf:"varint,3,opt,name=in_app,json=inApp,proto3" json:"in_app,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelPreferences) Reset() {
+	*x = ChannelPreferences{}
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelPreferences) ProtoMessage() {}
+
+func (x *ChannelPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelPreferences.ProtoReflect.Descriptor instead.
+func (*ChannelPreferences) Descriptor() ([]byte, []int) {
+	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ChannelPreferences) GetEmail() bool {
+	if x != nil {
+		return x.Email
+	}
+	return false
+}
+
+func (x *ChannelPreferences) GetTelegram() bool {
+	if x != nil {
+		return x.Telegram
+	}
+	return false
+}
+
+func (x *ChannelPreferences) GetInApp() bool {
+	if x != nil {
+		return x.InApp
 	}
 	return false
 }
 
+type GetNotificationPreferencesResponse struct {
+	state          protoimpl.MessageState         `protogen:"open.v1"`
+	UserId         string                         `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TelegramChatId string                         `protobuf:"bytes,2,opt,name=telegram_chat_id,json=telegramChatId,proto3" json:"telegram_chat_id,omitempty"`                                           // empty when no chat is linked
+	Categories     map[string]*ChannelPreferences `protobuf:"bytes,3,rep,name=categories,proto3" json:"categories,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // reminders, waitlist, recommendations, marketing (see categories.go)
+	InQuietHours   bool                           `protobuf:"varint,4,opt,name=in_quiet_hours,json=inQuietHours,proto3" json:"in_quiet_hours,omitempty"`                                                // now falls into the user's quiet hours
+	Locale         string                         `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`                                                                                   // kk, ru or en
+	Sms            bool                           `protobuf:"varint,6,opt,name=sms,proto3" json:"sms,omitempty"`                                                                                        // opted in to text messages
+	PhoneNumber    string                         `protobuf:"bytes,7,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`                                                      // E.164, empty when unknown
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetNotificationPreferencesResponse) Reset() {
+	*x = GetNotificationPreferencesResponse{}
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationPreferencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationPreferencesResponse) ProtoMessage() {}
+
+func (x *GetNotificationPreferencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_userprofile_user_profile_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationPreferencesResponse.ProtoReflect.Descriptor instead.
+func (*GetNotificationPreferencesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_userprofile_user_profile_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetNotificationPreferencesResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetNotificationPreferencesResponse) GetTelegramChatId() string {
+	if x != nil {
+		return x.TelegramChatId
+	}
+	return ""
+}
+
+func (x *GetNotificationPreferencesResponse) GetCategories() map[string]*ChannelPreferences {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *GetNotificationPreferencesResponse) GetInQuietHours() bool {
+	if x != nil {
+		return x.InQuietHours
+	}
+	return false
+}
+
+func (x *GetNotificationPreferencesResponse) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *GetNotificationPreferencesResponse) GetSms() bool {
+	if x != nil {
+		return x.Sms
+	}
+	return false
+}
+
+func (x *GetNotificationPreferencesResponse) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
 var File_proto_userprofile_user_profile_proto protoreflect.FileDescriptor
 
 const file_proto_userprofile_user_profile_proto_rawDesc = "" +
@@ -639,7 +971,7 @@ const file_proto_userprofile_user_profile_proto_rawDesc = "" +
 	"\x19CreateUserProfileResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
 	"\x19GetUserProfileByIDRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xf3\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x90\x02\n" +
 	"\x1aGetUserProfileByIDResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\tfull_name\x18\x02 \x01(\tR\bfullName\x12\x16\n" +
@@ -648,32 +980,66 @@ const file_proto_userprofile_user_profile_proto_rawDesc = "" +
 	"\x0especialization\x18\x05 \x01(\tR\x0especialization\x12\x14\n" +
 	"\x05email\x18\x06 \x01(\tR\x05email\x12\x14\n" +
 	"\x05phone\x18\a \x01(\tR\x05phone\x12(\n" +
-	"\x10telegram_chat_id\x18\b \x01(\tR\x0etelegramChatId\"/\n" +
+	"\x10telegram_chat_id\x18\b \x01(\tR\x0etelegramChatId\x12\x1b\n" +
+	"\ttime_zone\x18\t \x01(\tR\btimeZone\"/\n" +
 	"\x1bGetBatchUserProfilesRequest\x12\x10\n" +
-	"\x03ids\x18\x01 \x03(\tR\x03ids\"\x7f\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\x9c\x01\n" +
 	"\x10BasicUserProfile\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\tfull_name\x18\x02 \x01(\tR\bfullName\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12(\n" +
-	"\x10telegram_chat_id\x18\x04 \x01(\tR\x0etelegramChatId\"Y\n" +
+	"\x10telegram_chat_id\x18\x04 \x01(\tR\x0etelegramChatId\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\"Y\n" +
 	"\x1cGetBatchUserProfilesResponse\x129\n" +
 	"\bprofiles\x18\x01 \x03(\v2\x1d.userprofile.BasicUserProfileR\bprofiles\">\n" +
 	"\x16UpdateUserPhoneRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05phone\x18\x02 \x01(\tR\x05phone\"3\n" +
 	"\x17UpdateUserPhoneResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"U\n" +
-	"\x19UpdateUserTelegramRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
-	"\x10telegram_chat_id\x18\x02 \x01(\tR\x0etelegramChatId\"6\n" +
-	"\x1aUpdateUserTelegramResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\x91\x04\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x89\x01\n" +
+	"\x1bGetConsentedMoodLogsRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\tR\tstudentId\x12'\n" +
+	"\x0fpsychologist_id\x18\x02 \x01(\tR\x0epsychologistId\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\"\x9e\x01\n" +
+	"\tMoodEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tlogged_at\x18\x02 \x01(\tR\bloggedAt\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x12\n" +
+	"\x04mood\x18\x04 \x01(\tR\x04mood\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x05R\x05score\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\"s\n" +
+	"\x1cGetConsentedMoodLogsResponse\x120\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.userprofile.MoodEntryR\aentries\x12!\n" +
+	"\fnotes_shared\x18\x02 \x01(\bR\vnotesShared\"9\n" +
+	"!GetNotificationPreferencesRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"]\n" +
+	"\x12ChannelPreferences\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\bR\x05email\x12\x1a\n" +
+	"\btelegram\x18\x02 \x01(\bR\btelegram\x12\x15\n" +
+	"\x06in_app\x18\x03 \x01(\bR\x05inApp\"\x9b\x03\n" +
+	"\"GetNotificationPreferencesResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12(\n" +
+	"\x10telegram_chat_id\x18\x02 \x01(\tR\x0etelegramChatId\x12_\n" +
+	"\n" +
+	"categories\x18\x03 \x03(\v2?.userprofile.GetNotificationPreferencesResponse.CategoriesEntryR\n" +
+	"categories\x12$\n" +
+	"\x0ein_quiet_hours\x18\x04 \x01(\bR\finQuietHours\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\x12\x10\n" +
+	"\x03sms\x18\x06 \x01(\bR\x03sms\x12!\n" +
+	"\fphone_number\x18\a \x01(\tR\vphoneNumber\x1a^\n" +
+	"\x0fCategoriesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x125\n" +
+	"\x05value\x18\x02 \x01(\v2\x1f.userprofile.ChannelPreferencesR\x05value:\x028\x012\x96\x05\n" +
 	"\x12UserProfileService\x12b\n" +
 	"\x11CreateUserProfile\x12%.userprofile.CreateUserProfileRequest\x1a&.userprofile.CreateUserProfileResponse\x12e\n" +
 	"\x12GetUserProfileByID\x12&.userprofile.GetUserProfileByIDRequest\x1a'.userprofile.GetUserProfileByIDResponse\x12k\n" +
 	"\x14GetBatchUserProfiles\x12(.userprofile.GetBatchUserProfilesRequest\x1a).userprofile.GetBatchUserProfilesResponse\x12\\\n" +
-	"\x0fUpdateUserPhone\x12#.userprofile.UpdateUserPhoneRequest\x1a$.userprofile.UpdateUserPhoneResponse\x12e\n" +
-	"\x12UpdateUserTelegram\x12&.userprofile.UpdateUserTelegramRequest\x1a'.userprofile.UpdateUserTelegramResponseBGZEgithub.com/pokonti/psychologist-backend/proto/userprofile;userprofileb\x06proto3"
+	"\x0fUpdateUserPhone\x12#.userprofile.UpdateUserPhoneRequest\x1a$.userprofile.UpdateUserPhoneResponse\x12k\n" +
+	"\x14GetConsentedMoodLogs\x12(.userprofile.GetConsentedMoodLogsRequest\x1a).userprofile.GetConsentedMoodLogsResponse\x12}\n" +
+	"\x1aGetNotificationPreferences\x12..userprofile.GetNotificationPreferencesRequest\x1a/.userprofile.GetNotificationPreferencesResponseBGZEgithub.com/pokonti/psychologist-backend/proto/userprofile;userprofileb\x06proto3"
 
 var (
 	file_proto_userprofile_user_profile_proto_rawDescOnce sync.Once
@@ -687,37 +1053,47 @@ func file_proto_userprofile_user_profile_proto_rawDescGZIP() []byte {
 	return file_proto_userprofile_user_profile_proto_rawDescData
 }
 
-var file_proto_userprofile_user_profile_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_userprofile_user_profile_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_proto_userprofile_user_profile_proto_goTypes = []any{
-	(*CreateUserProfileRequest)(nil),     // 0: userprofile.CreateUserProfileRequest
-	(*CreateUserProfileResponse)(nil),    // 1: userprofile.CreateUserProfileResponse
-	(*GetUserProfileByIDRequest)(nil),    // 2: userprofile.GetUserProfileByIDRequest
-	(*GetUserProfileByIDResponse)(nil),   // 3: userprofile.GetUserProfileByIDResponse
-	(*GetBatchUserProfilesRequest)(nil),  // 4: userprofile.GetBatchUserProfilesRequest
-	(*BasicUserProfile)(nil),             // 5: userprofile.BasicUserProfile
-	(*GetBatchUserProfilesResponse)(nil), // 6: userprofile.GetBatchUserProfilesResponse
-	(*UpdateUserPhoneRequest)(nil),       // 7: userprofile.UpdateUserPhoneRequest
-	(*UpdateUserPhoneResponse)(nil),      // 8: userprofile.UpdateUserPhoneResponse
-	(*UpdateUserTelegramRequest)(nil),    // 9: userprofile.UpdateUserTelegramRequest
-	(*UpdateUserTelegramResponse)(nil),   // 10: userprofile.UpdateUserTelegramResponse
+	(*CreateUserProfileRequest)(nil),           // 0: userprofile.CreateUserProfileRequest
+	(*CreateUserProfileResponse)(nil),          // 1: userprofile.CreateUserProfileResponse
+	(*GetUserProfileByIDRequest)(nil),          // 2: userprofile.GetUserProfileByIDRequest
+	(*GetUserProfileByIDResponse)(nil),         // 3: userprofile.GetUserProfileByIDResponse
+	(*GetBatchUserProfilesRequest)(nil),        // 4: userprofile.GetBatchUserProfilesRequest
+	(*BasicUserProfile)(nil),                   // 5: userprofile.BasicUserProfile
+	(*GetBatchUserProfilesResponse)(nil),       // 6: userprofile.GetBatchUserProfilesResponse
+	(*UpdateUserPhoneRequest)(nil),             // 7: userprofile.UpdateUserPhoneRequest
+	(*UpdateUserPhoneResponse)(nil),            // 8: userprofile.UpdateUserPhoneResponse
+	(*GetConsentedMoodLogsRequest)(nil),        // 9: userprofile.GetConsentedMoodLogsRequest
+	(*MoodEntry)(nil),                          // 10: userprofile.MoodEntry
+	(*GetConsentedMoodLogsResponse)(nil),       // 11: userprofile.GetConsentedMoodLogsResponse
+	(*GetNotificationPreferencesRequest)(nil),  // 12: userprofile.GetNotificationPreferencesRequest
+	(*ChannelPreferences)(nil),                 // 13: userprofile.ChannelPreferences
+	(*GetNotificationPreferencesResponse)(nil), // 14: userprofile.GetNotificationPreferencesResponse
+	nil, // 15: userprofile.GetNotificationPreferencesResponse.CategoriesEntry
 }
 var file_proto_userprofile_user_profile_proto_depIdxs = []int32{
 	5,  // 0: userprofile.GetBatchUserProfilesResponse.profiles:type_name -> userprofile.BasicUserProfile
-	0,  // 1: userprofile.UserProfileService.CreateUserProfile:input_type -> userprofile.CreateUserProfileRequest
-	2,  // 2: userprofile.UserProfileService.GetUserProfileByID:input_type -> userprofile.GetUserProfileByIDRequest
-	4,  // 3: userprofile.UserProfileService.GetBatchUserProfiles:input_type -> userprofile.GetBatchUserProfilesRequest
-	7,  // 4: userprofile.UserProfileService.UpdateUserPhone:input_type -> userprofile.UpdateUserPhoneRequest
-	9,  // 5: userprofile.UserProfileService.UpdateUserTelegram:input_type -> userprofile.UpdateUserTelegramRequest
-	1,  // 6: userprofile.UserProfileService.CreateUserProfile:output_type -> userprofile.CreateUserProfileResponse
-	3,  // 7: userprofile.UserProfileService.GetUserProfileByID:output_type -> userprofile.GetUserProfileByIDResponse
-	6,  // 8: userprofile.UserProfileService.GetBatchUserProfiles:output_type -> userprofile.GetBatchUserProfilesResponse
-	8,  // 9: userprofile.UserProfileService.UpdateUserPhone:output_type -> userprofile.UpdateUserPhoneResponse
-	10, // 10: userprofile.UserProfileService.UpdateUserTelegram:output_type -> userprofile.UpdateUserTelegramResponse
-	6,  // [6:11] is the sub-list for method output_type
-	1,  // [1:6] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	10, // 1: userprofile.GetConsentedMoodLogsResponse.entries:type_name -> userprofile.MoodEntry
+	15, // 2: userprofile.GetNotificationPreferencesResponse.categories:type_name -> userprofile.GetNotificationPreferencesResponse.CategoriesEntry
+	13, // 3: userprofile.GetNotificationPreferencesResponse.CategoriesEntry.value:type_name -> userprofile.ChannelPreferences
+	0,  // 4: userprofile.UserProfileService.CreateUserProfile:input_type -> userprofile.CreateUserProfileRequest
+	2,  // 5: userprofile.UserProfileService.GetUserProfileByID:input_type -> userprofile.GetUserProfileByIDRequest
+	4,  // 6: userprofile.UserProfileService.GetBatchUserProfiles:input_type -> userprofile.GetBatchUserProfilesRequest
+	7,  // 7: userprofile.UserProfileService.UpdateUserPhone:input_type -> userprofile.UpdateUserPhoneRequest
+	9,  // 8: userprofile.UserProfileService.GetConsentedMoodLogs:input_type -> userprofile.GetConsentedMoodLogsRequest
+	12, // 9: userprofile.UserProfileService.GetNotificationPreferences:input_type -> userprofile.GetNotificationPreferencesRequest
+	1,  // 10: userprofile.UserProfileService.CreateUserProfile:output_type -> userprofile.CreateUserProfileResponse
+	3,  // 11: userprofile.UserProfileService.GetUserProfileByID:output_type -> userprofile.GetUserProfileByIDResponse
+	6,  // 12: userprofile.UserProfileService.GetBatchUserProfiles:output_type -> userprofile.GetBatchUserProfilesResponse
+	8,  // 13: userprofile.UserProfileService.UpdateUserPhone:output_type -> userprofile.UpdateUserPhoneResponse
+	11, // 14: userprofile.UserProfileService.GetConsentedMoodLogs:output_type -> userprofile.GetConsentedMoodLogsResponse
+	14, // 15: userprofile.UserProfileService.GetNotificationPreferences:output_type -> userprofile.GetNotificationPreferencesResponse
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_userprofile_user_profile_proto_init() }
@@ -731,7 +1107,7 @@ func file_proto_userprofile_user_profile_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_userprofile_user_profile_proto_rawDesc), len(file_proto_userprofile_user_profile_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserProfileService_CreateUserProfile_FullMethodName    = "/userprofile.UserProfileService/CreateUserProfile"
-	UserProfileService_GetUserProfileByID_FullMethodName   = "/userprofile.UserProfileService/GetUserProfileByID"
-	UserProfileService_GetBatchUserProfiles_FullMethodName = "/userprofile.UserProfileService/GetBatchUserProfiles"
-	UserProfileService_UpdateUserPhone_FullMethodName      = "/userprofile.UserProfileService/UpdateUserPhone"
-	UserProfileService_UpdateUserTelegram_FullMethodName   = "/userprofile.UserProfileService/UpdateUserTelegram"
+	UserProfileService_CreateUserProfile_FullMethodName          = "/userprofile.UserProfileService/CreateUserProfile"
+	UserProfileService_GetUserProfileByID_FullMethodName         = "/userprofile.UserProfileService/GetUserProfileByID"
+	UserProfileService_GetBatchUserProfiles_FullMethodName       = "/userprofile.UserProfileService/GetBatchUserProfiles"
+	UserProfileService_UpdateUserPhone_FullMethodName            = "/userprofile.UserProfileService/UpdateUserPhone"
+	UserProfileService_GetConsentedMoodLogs_FullMethodName       = "/userprofile.UserProfileService/GetConsentedMoodLogs"
+	UserProfileService_GetNotificationPreferences_FullMethodName = "/userprofile.UserProfileService/GetNotificationPreferences"
 )
 
 // UserProfileServiceClient is the client API for UserProfileService service.
@@ -34,7 +35,8 @@ type UserProfileServiceClient interface {
 	GetUserProfileByID(ctx context.Context, in *GetUserProfileByIDRequest, opts ...grpc.CallOption) (*GetUserProfileByIDResponse, error)
 	GetBatchUserProfiles(ctx context.Context, in *GetBatchUserProfilesRequest, opts ...grpc.CallOption) (*GetBatchUserProfilesResponse, error)
 	UpdateUserPhone(ctx context.Context, in *UpdateUserPhoneRequest, opts ...grpc.CallOption) (*UpdateUserPhoneResponse, error)
-	UpdateUserTelegram(ctx context.Context, in *UpdateUserTelegramRequest, opts ...grpc.CallOption) (*UpdateUserTelegramResponse, error)
+	GetConsentedMoodLogs(ctx context.Context, in *GetConsentedMoodLogsRequest, opts ...grpc.CallOption) (*GetConsentedMoodLogsResponse, error)
+	GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error)
 }
 
 type userProfileServiceClient struct {
@@ -85,10 +87,20 @@ func (c *userProfileServiceClient) UpdateUserPhone(ctx context.Context, in *Upda
 	return out, nil
 }
 
-func (c *userProfileServiceClient) UpdateUserTelegram(ctx context.Context, in *UpdateUserTelegramRequest, opts ...grpc.CallOption) (*UpdateUserTelegramResponse, error) {
+func (c *userProfileServiceClient) GetConsentedMoodLogs(ctx context.Context, in *GetConsentedMoodLogsRequest, opts ...grpc.CallOption) (*GetConsentedMoodLogsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UpdateUserTelegramResponse)
-	err := c.cc.Invoke(ctx, UserProfileService_UpdateUserTelegram_FullMethodName, in, out, cOpts...)
+	out := new(GetConsentedMoodLogsResponse)
+	err := c.cc.Invoke(ctx, UserProfileService_GetConsentedMoodLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userProfileServiceClient) GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, UserProfileService_GetNotificationPreferences_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +115,8 @@ type UserProfileServiceServer interface {
 	GetUserProfileByID(context.Context, *GetUserProfileByIDRequest) (*GetUserProfileByIDResponse, error)
 	GetBatchUserProfiles(context.Context, *GetBatchUserProfilesRequest) (*GetBatchUserProfilesResponse, error)
 	UpdateUserPhone(context.Context, *UpdateUserPhoneRequest) (*UpdateUserPhoneResponse, error)
-	UpdateUserTelegram(context.Context, *UpdateUserTelegramRequest) (*UpdateUserTelegramResponse, error)
+	GetConsentedMoodLogs(context.Context, *GetConsentedMoodLogsRequest) (*GetConsentedMoodLogsResponse, error)
+	GetNotificationPreferences(context.Context, *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error)
 	mustEmbedUnimplementedUserProfileServiceServer()
 }
 
@@ -126,8 +139,11 @@ func (UnimplementedUserProfileServiceServer) GetBatchUserProfiles(context.Contex
 func (UnimplementedUserProfileServiceServer) UpdateUserPhone(context.Context, *UpdateUserPhoneRequest) (*UpdateUserPhoneResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateUserPhone not implemented")
 }
-func (UnimplementedUserProfileServiceServer) UpdateUserTelegram(context.Context, *UpdateUserTelegramRequest) (*UpdateUserTelegramResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method UpdateUserTelegram not implemented")
+func (UnimplementedUserProfileServiceServer) GetConsentedMoodLogs(context.Context, *GetConsentedMoodLogsRequest) (*GetConsentedMoodLogsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetConsentedMoodLogs not implemented")
+}
+func (UnimplementedUserProfileServiceServer) GetNotificationPreferences(context.Context, *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNotificationPreferences not implemented")
 }
 func (UnimplementedUserProfileServiceServer) mustEmbedUnimplementedUserProfileServiceServer() {}
 func (UnimplementedUserProfileServiceServer) testEmbeddedByValue()                            {}
@@ -222,20 +238,38 @@ func _UserProfileService_UpdateUserPhone_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UserProfileService_UpdateUserTelegram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserTelegramRequest)
+func _UserProfileService_GetConsentedMoodLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsentedMoodLogsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserProfileServiceServer).UpdateUserTelegram(ctx, in)
+		return srv.(UserProfileServiceServer).GetConsentedMoodLogs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UserProfileService_UpdateUserTelegram_FullMethodName,
+		FullMethod: UserProfileService_GetConsentedMoodLogs_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserProfileServiceServer).UpdateUserTelegram(ctx, req.(*UpdateUserTelegramRequest))
+		return srv.(UserProfileServiceServer).GetConsentedMoodLogs(ctx, req.(*GetConsentedMoodLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserProfileService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserProfileServiceServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserProfileService_GetNotificationPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserProfileServiceServer).GetNotificationPreferences(ctx, req.(*GetNotificationPreferencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -264,8 +298,12 @@ var UserProfileService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _UserProfileService_UpdateUserPhone_Handler,
 		},
 		{
-			MethodName: "UpdateUserTelegram",
-			Handler:    _UserProfileService_UpdateUserTelegram_Handler,
+			MethodName: "GetConsentedMoodLogs",
+			Handler:    _UserProfileService_GetConsentedMoodLogs_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _UserProfileService_GetNotificationPreferences_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
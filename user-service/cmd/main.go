@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net"
+	_ "time/tzdata" // IANA zones for slim container images
 
 	"github.com/gin-gonic/gin"
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
@@ -41,7 +42,14 @@ func main() {
 	defer rabbitCh.Close()
 
 	go consumer.StartListening(rabbitCh, rabbitQueue)
-	go worker.StartTelegramBot()
+	bookingClient, bookingConn, err := clients.NewBookingClient()
+	if err != nil {
+		log.Fatalf("Failed to connect to booking service: %v", err)
+	}
+	defer bookingConn.Close()
+
+	go worker.StartTelegramBot(bookingClient)
+	worker.StartRiskAlertWorker()
 
 	clients.InitS3()
 
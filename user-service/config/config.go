@@ -28,10 +28,63 @@ func ConnectDB() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	log.Println("Database connected")
-	err = DB.AutoMigrate(&models.UserProfile{}, &models.MoodLog{})
+
+	// Mood logs used to be one per day; entries are now timestamped and any number per day is
+	// allowed, so idx_user_date loses its unique constraint and AutoMigrate recreates it plain
+	indexes, err := DB.Migrator().GetIndexes(&models.MoodLog{})
+	if err != nil {
+		log.Fatal("Failed to read mood log indexes: ", err)
+	}
+	for _, idx := range indexes {
+		if unique, _ := idx.Unique(); idx.Name() == "idx_user_date" && unique {
+			if err := DB.Migrator().DropIndex(&models.MoodLog{}, "idx_user_date"); err != nil {
+				log.Fatal("Failed to drop unique mood log day index: ", err)
+			}
+		}
+	}
+
+	err = DB.AutoMigrate(&models.UserProfile{}, &models.MoodLog{}, &models.RiskAlert{}, &models.RiskAlertEvent{}, &models.DutyShift{}, &models.MoodConsent{}, &models.MoodReminder{}, &models.TelegramLinkToken{}, &models.NotificationPreference{})
 	if err != nil {
 		log.Fatal("Failed to migrate database: ", err)
 	}
+
+	// Entries from before timestamps get the time they were last updated
+	err = DB.Exec(`UPDATE mood_logs SET logged_at = updated_at WHERE logged_at IS NULL`).Error
+	if err != nil {
+		log.Fatal("Failed to backfill mood log timestamps: ", err)
+	}
+
+	// A student has at most one active consent per psychologist
+	err = DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_mood_consent_active ON mood_consents (student_id, psychologist_id) WHERE revoked_at IS NULL`).Error
+	if err != nil {
+		log.Fatal("Failed to index mood consents: ", err)
+	}
+
+	// One open alert per student and rule, so a repeated trigger does not page the psychologist again
+	err = DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_alert_open ON risk_alerts (student_id, rule) WHERE status = 'open'`).Error
+	if err != nil {
+		log.Fatal("Failed to index risk alerts: ", err)
+	}
+
+	// The acknowledgement trail must not be rewritten
+	err = DB.Exec(`
+		CREATE OR REPLACE FUNCTION risk_alert_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'risk_alert_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS risk_alert_events_no_change ON risk_alert_events;
+		CREATE TRIGGER risk_alert_events_no_change BEFORE UPDATE OR DELETE ON risk_alert_events
+			FOR EACH ROW EXECUTE FUNCTION risk_alert_events_append_only();
+
+		DROP TRIGGER IF EXISTS risk_alert_events_no_truncate ON risk_alert_events;
+		CREATE TRIGGER risk_alert_events_no_truncate BEFORE TRUNCATE ON risk_alert_events
+			FOR EACH STATEMENT EXECUTE FUNCTION risk_alert_events_append_only();
+	`).Error
+	if err != nil {
+		log.Fatal("Failed to protect risk alert trail: ", err)
+	}
 }
 
 func getEnv(key, fallback string) string {
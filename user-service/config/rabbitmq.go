@@ -7,6 +7,12 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// RabbitChannel and NotificationsQueue are used to publish notifications, such as risk alerts
+var (
+	RabbitChannel      *amqp.Channel
+	NotificationsQueue amqp.Queue
+)
+
 func ConnectRabbitMQ() (*amqp.Connection, *amqp.Channel, amqp.Queue) {
 	rabbitURL := os.Getenv("RABBITMQ_URL")
 	if rabbitURL == "" {
@@ -31,6 +37,15 @@ func ConnectRabbitMQ() (*amqp.Connection, *amqp.Channel, amqp.Queue) {
 		log.Fatalf("Failed to declare queue: %v", err)
 	}
 
-	log.Println("User Service connected to RabbitMQ (Consumer)")
+	NotificationsQueue, err = ch.QueueDeclare(
+		"notifications_queue",
+		true, false, false, false, nil,
+	)
+	if err != nil {
+		log.Fatalf("Failed to declare notifications queue: %v", err)
+	}
+	RabbitChannel = ch
+
+	log.Println("User Service connected to RabbitMQ")
 	return conn, ch, q
 }
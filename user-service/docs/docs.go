@@ -49,6 +49,134 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/admin/duty-shifts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current and upcoming duty shifts in start order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Admin: List duty shifts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DutyShift"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Risk alerts raised during the shift are sent to the psychologists on duty. When nobody is on duty, every psychologist is paged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Admin: Put a psychologist on duty",
+                "parameters": [
+                    {
+                        "description": "Shift",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DutyShiftInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DutyShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/admin/duty-shifts/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Admin: Remove a duty shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users/me": {
             "get": {
                 "security": [
@@ -187,13 +315,65 @@ const docTemplate = `{
             }
         },
         "/users/me/mood": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the student's mood entries with notes and tags, newest first. Dates are days in the student's time zone; the range defaults to the last 7 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "List mood entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MoodLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Student selects how they are feeling today. Updates the existing entry if already logged today.",
+                "description": "Student records how they are feeling, optionally with a journal note and activity tags. Any number of entries can be logged per day. logged_at defaults to now and may be up to 7 days in the past.",
                 "consumes": [
                     "application/json"
                 ],
@@ -203,10 +383,10 @@ const docTemplate = `{
                 "tags": [
                     "well-being"
                 ],
-                "summary": "Log daily mood",
+                "summary": "Log a mood entry",
                 "parameters": [
                     {
-                        "description": "Mood selection",
+                        "description": "Mood entry",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -216,42 +396,57 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.MoodLog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/me/mood/graphic": {
+        "/users/me/mood/chart": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns mood scores for the graph. Filter by 'last_week' or 'last_month'.",
+                "description": "Aggregates the student's mood entries per day or per week over any range of up to 366 days (default: the last 7 days) and computes insights: streaks, weekday averages, the trend and how activity tags relate to the score.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "well-being"
                 ],
-                "summary": "Get mood data for graphic",
+                "summary": "Get the mood chart with insights",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "enum": [
-                            "last_week",
-                            "last_month"
+                            "day",
+                            "week"
                         ],
                         "type": "string",
-                        "description": "Filter period (default: last_week)",
-                        "name": "filter",
+                        "description": "Aggregation (default: day)",
+                        "name": "group_by",
                         "in": "query"
                     }
                 ],
@@ -259,97 +454,1066 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MoodGraphicResponse"
-                            }
+                            "$ref": "#/definitions/models.MoodChartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/psychologists": {
+        "/users/me/mood/consents": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a sanitized list of psychologists for students to browse. Hides sensitive data.",
+                "description": "Returns the student's consents, active ones first, including revoked ones as a record.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "well-being"
                 ],
-                "summary": "List psychologists for booking",
+                "summary": "List who can see my mood logs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.PublicPsychologistResponse"
+                                "$ref": "#/definitions/models.MoodConsentResponse"
                             }
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lets the psychologist see the student's mood timeline while they have a booked session together. Journal notes are only shared when share_notes is set. Granting again updates share_notes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Share my mood logs with a psychologist",
+                "parameters": [
+                    {
+                        "description": "Psychologist to share with",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodConsentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Existing consent updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodConsent"
+                        }
                     },
-                    "500": {
-                        "description": "database error",
+                    "201": {
+                        "description": "Consent granted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodConsent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Invalid start_date"
-                }
-            }
-        },
-        "models.LogMoodInput": {
-            "type": "object",
-            "required": [
-                "mood"
-            ],
-            "properties": {
-                "mood": {
-                    "type": "string",
-                    "enum": [
-                        "Amazing",
-                        "Nice",
-                        "Not bad",
-                        "Sad",
-                        "Anxiously",
-                        "Stressed"
-                    ]
-                }
-            }
         },
-        "models.MoodGraphicResponse": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "description": "\"2026-02-23\"",
-                    "type": "string"
-                },
+        "/users/me/mood/consents/{psychologist_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes effect immediately. The consent is kept, marked as revoked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Stop sharing my mood logs with a psychologist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Psychologist ID",
+                        "name": "psychologist_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mood/graphic": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns one point per day with the last mood logged that day. Filter by 'last_week' or 'last_month'. Kept for existing clients: GET /users/me/mood/chart adds weekly aggregation, custom ranges and insights.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Get mood data for graphic",
+                "parameters": [
+                    {
+                        "enum": [
+                            "last_week",
+                            "last_month"
+                        ],
+                        "type": "string",
+                        "description": "Filter period (default: last_week)",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MoodGraphicResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mood/reminder": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Get my daily mood check-in",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodReminder"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Once a day at the chosen time (in the user's time zone) the Telegram bot asks how the user feels, with one button per mood. Requires a linked Telegram account. Sending /stop to the bot turns it off too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Turn the daily mood check-in on or off",
+                "parameters": [
+                    {
+                        "description": "Check-in settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodReminderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoodReminder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mood/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-being"
+                ],
+                "summary": "Delete a mood entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Which channels (email, telegram, in_app) each category of notifications goes to, whether session reminders and cancellations are also sent by SMS, and the quiet hours. Account, booking and risk-alert messages are always sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Get my notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the channels of the categories sent (reminders, waitlist, recommendations, marketing); the others keep their settings. SMS (session reminders and cancellations by the psychologist) needs a phone number in the profile. Telegram messages are sent silently during quiet hours, which are in the user's time zone and may span midnight. Leaving quiet_hours out keeps them, sending it as null turns them off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update my notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferenceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/telegram": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops all Telegram messages to this profile and turns off the daily mood check-in. Sending /unlink to the bot does the same.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Disconnect Telegram",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/telegram/link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a signed token that links the Telegram chat which sends it to the bot (/start \u003ctoken\u003e) to this profile. The token works once and expires after 10 minutes; requesting a new one invalidates the previous one. url opens the bot with the token filled in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Get a link for connecting Telegram",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TelegramLinkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/psychologists": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a sanitized list of psychologists for students to browse. Hides sensitive data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List psychologists for booking",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PublicPsychologistResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "database error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/risk-alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Alerts raised from students' mood logs and questionnaire answers, newest first. Open alerts are shown by default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk-alerts"
+                ],
+                "summary": "List risk alerts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "acknowledged",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Alert status (default: open)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of alerts (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RiskAlertResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/risk-alerts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the alert with its full trail: when it was raised, who was paged, escalations and the acknowledgement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk-alerts"
+                ],
+                "summary": "Get a risk alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RiskAlertResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/risk-alerts/{id}/acknowledge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records that the psychologist has taken charge of the alert, which stops further escalation. The note is kept in the alert's trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk-alerts"
+                ],
+                "summary": "Acknowledge a risk alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "What was done",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.AcknowledgeRiskAlertInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already acknowledged",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AcknowledgeRiskAlertInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Called the student, follow-up session booked"
+                }
+            }
+        },
+        "models.ChannelPreferences": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
+                },
+                "in_app": {
+                    "type": "boolean"
+                },
+                "telegram": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DutyShift": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DutyShiftInput": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "psychologist_id",
+                "starts_at"
+            ],
+            "properties": {
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-03-02T18:00:00+05:00"
+                },
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-03-02T09:00:00+05:00"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid start_date"
+                }
+            }
+        },
+        "models.LogMoodInput": {
+            "type": "object",
+            "required": [
+                "mood"
+            ],
+            "properties": {
+                "logged_at": {
+                    "description": "defaults to now, may be up to 7 days in the past",
+                    "type": "string",
+                    "example": "2026-03-02T21:15:00+05:00"
+                },
+                "mood": {
+                    "type": "string",
+                    "enum": [
+                        "Amazing",
+                        "Nice",
+                        "Not bad",
+                        "Sad",
+                        "Anxiously",
+                        "Stressed"
+                    ]
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Slept badly before the exam"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sleep",
+                        "exams"
+                    ]
+                }
+            }
+        },
+        "models.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodChartResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2026-02-24"
+                },
+                "group_by": {
+                    "description": "day, week",
+                    "type": "string",
+                    "example": "day"
+                },
+                "insights": {
+                    "$ref": "#/definitions/models.MoodInsights"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MoodPoint"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-03-02"
+                }
+            }
+        },
+        "models.MoodConsent": {
+            "type": "object",
+            "properties": {
+                "granted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_viewed_at": {
+                    "type": "string"
+                },
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "share_notes": {
+                    "description": "journal notes are shared too",
+                    "type": "boolean"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodConsentInput": {
+            "type": "object",
+            "required": [
+                "psychologist_id"
+            ],
+            "properties": {
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "share_notes": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MoodConsentResponse": {
+            "type": "object",
+            "properties": {
+                "granted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_viewed_at": {
+                    "type": "string"
+                },
+                "psychologist_id": {
+                    "type": "string"
+                },
+                "psychologist_name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "share_notes": {
+                    "description": "journal notes are shared too",
+                    "type": "boolean"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodGraphicResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-02-23"
+                },
                 "day_of_week": {
-                    "description": "\"Mon\", \"Tue\"",
+                    "type": "string",
+                    "example": "Mon"
+                },
+                "mood": {
+                    "type": "string",
+                    "example": "Amazing"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 6
+                }
+            }
+        },
+        "models.MoodInsights": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number",
+                    "example": 4.1
+                },
+                "current_streak": {
+                    "description": "days in a row with an entry, up to today",
+                    "type": "integer",
+                    "example": 4
+                },
+                "days_logged": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "longest_streak": {
+                    "description": "within the range",
+                    "type": "integer",
+                    "example": 5
+                },
+                "mood_counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "tag_correlations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagCorrelation"
+                    }
+                },
+                "trend": {
+                    "description": "improving, declining, stable or not_enough_data",
+                    "type": "string",
+                    "example": "improving"
+                },
+                "trend_change": {
+                    "description": "average of the second half of the range minus the first",
+                    "type": "number",
+                    "example": 0.8
+                },
+                "weekday_averages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WeekdayAverage"
+                    }
+                }
+            }
+        },
+        "models.MoodLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Day of LoggedAt in the student's time zone, YYYY-MM-DD",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logged_at": {
                     "type": "string"
                 },
                 "mood": {
-                    "description": "\"Amazing\"",
+                    "type": "string"
+                },
+                "note": {
                     "type": "string"
                 },
                 "score": {
-                    "description": "6",
+                    "description": "1 to 6 (for the graph)",
                     "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sleep",
+                        "exams"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodPoint": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number",
+                    "example": 4.33
+                },
+                "date": {
+                    "description": "first day of the period",
+                    "type": "string",
+                    "example": "2026-02-23"
+                },
+                "day_of_week": {
+                    "type": "string",
+                    "example": "Mon"
+                },
+                "end_date": {
+                    "description": "last day of the period",
+                    "type": "string",
+                    "example": "2026-03-01"
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "max_score": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "min_score": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "mood": {
+                    "description": "most frequent mood in the period",
+                    "type": "string",
+                    "example": "Nice"
+                }
+            }
+        },
+        "models.MoodReminder": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "description": "HH:MM in the user's time zone",
+                    "type": "string",
+                    "example": "21:00"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodReminderInput": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "description": "required to enable",
+                    "type": "string",
+                    "example": "21:00"
+                }
+            }
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "reminders, waitlist, recommendations, marketing",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.ChannelPreferences"
+                    }
+                },
+                "quiet_hours": {
+                    "$ref": "#/definitions/models.QuietHours"
+                },
+                "sms": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationPreferenceInput": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "reminders, waitlist, recommendations, marketing",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.ChannelPreferences"
+                    }
+                },
+                "quiet_hours": {
+                    "$ref": "#/definitions/models.QuietHours"
+                },
+                "sms": {
+                    "type": "boolean"
                 }
             }
         },
@@ -385,6 +1549,159 @@ const docTemplate = `{
                 }
             }
         },
+        "models.QuietHours": {
+            "type": "object",
+            "required": [
+                "end",
+                "start"
+            ],
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "08:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "22:00"
+                }
+            }
+        },
+        "models.RiskAlertEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "notified"
+                },
+                "actor_id": {
+                    "description": "empty for the system",
+                    "type": "string"
+                },
+                "alert_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RiskAlertResponse": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Reported feeling stressed or anxious 3 days in a row"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RiskAlertEvent"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notified_at": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "distress_streak"
+                },
+                "severity": {
+                    "description": "high, critical",
+                    "type": "string",
+                    "example": "high"
+                },
+                "slot_id": {
+                    "description": "booking the questionnaire came with",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "mood, questionnaire",
+                    "type": "string",
+                    "example": "mood"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_email": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                },
+                "student_phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagCorrelation": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number",
+                    "example": 5.2
+                },
+                "correlation": {
+                    "type": "number",
+                    "example": 0.62
+                },
+                "difference": {
+                    "description": "average with the tag minus average without it",
+                    "type": "number",
+                    "example": 1.3
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "sport"
+                }
+            }
+        },
+        "models.TelegramLinkResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "set when TELEGRAM_BOT_USERNAME is configured",
+                    "type": "string",
+                    "example": "https://t.me/kbtu_care_bot?start=..."
+                }
+            }
+        },
         "models.UpdateProfileRequest": {
             "type": "object",
             "properties": {
@@ -400,11 +1717,25 @@ const docTemplate = `{
                 "gender": {
                     "type": "string"
                 },
+                "locale": {
+                    "type": "string",
+                    "enum": [
+                        "kk",
+                        "ru",
+                        "en"
+                    ]
+                },
                 "phone": {
-                    "type": "string"
+                    "description": "E.164, \"\" removes it",
+                    "type": "string",
+                    "example": "+77011234567"
                 },
                 "specialization": {
                     "type": "string"
+                },
+                "time_zone": {
+                    "description": "IANA name, e.g. \"Asia/Almaty\"",
+                    "type": "string"
                 }
             }
         },
@@ -475,6 +1806,10 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "locale": {
+                    "description": "language of notifications: kk, ru or en",
+                    "type": "string"
+                },
                 "password": {
                     "type": "string"
                 },
@@ -498,10 +1833,31 @@ const docTemplate = `{
                 "telegram_chat_id": {
                     "type": "string"
                 },
+                "time_zone": {
+                    "description": "IANA name, e.g. \"Asia/Almaty\"",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
+        },
+        "models.WeekdayAverage": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number",
+                    "example": 3.75
+                },
+                "day_of_week": {
+                    "type": "string",
+                    "example": "Mon"
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/proto/booking"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Appointment is a booked session as shown by the Telegram bot
+type Appointment struct {
+	StartTime   time.Time
+	BookingType string
+	With        string // the psychologist for students, the student for psychologists
+}
+
+func NewBookingClient() (booking.BookingServiceClient, *grpc.ClientConn, error) {
+	addr := os.Getenv("BOOKING_SERVICE_GRPC_ADDR")
+	if addr == "" {
+		addr = "booking-service:9094"
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("User Service connected to Booking Service at %s", addr)
+	return booking.NewBookingServiceClient(conn), conn, nil
+}
+
+// UpcomingAppointments returns the user's booked sessions that have not started yet, soonest first
+func UpcomingAppointments(ctx context.Context, client booking.BookingServiceClient, userID, role string) ([]Appointment, error) {
+	resp, err := client.GetUpcomingAppointments(ctx, &booking.GetUpcomingAppointmentsRequest{UserId: userID, Role: role})
+	if err != nil {
+		return nil, err
+	}
+
+	var upcoming []Appointment
+	for _, a := range resp.Appointments {
+		start, err := time.Parse(time.RFC3339, a.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		upcoming = append(upcoming, Appointment{StartTime: start, BookingType: a.BookingType, With: a.With})
+	}
+	return upcoming, nil
+}
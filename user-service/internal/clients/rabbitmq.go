@@ -0,0 +1,38 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type NotificationMessage struct {
+	Type    string            `json:"type"`
+	ToEmail string            `json:"to_email"`
+	Data    map[string]string `json:"data"`
+}
+
+// PublishNotification sends a message to notification-service
+func PublishNotification(msg NotificationMessage) error {
+	if config.RabbitChannel == nil {
+		return fmt.Errorf("rabbitmq is not connected")
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return config.RabbitChannel.Publish(
+		"",
+		config.NotificationsQueue.Name,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Body:         body,
+		})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pokonti/psychologist-backend/user-service/config"
 	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/risk"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -13,6 +14,12 @@ type UserEventMessage struct {
 	Type           string `json:"type"`
 	PsychologistID string `json:"psychologist_id"`
 	Rating         int    `json:"rating"`
+	StudentID      string `json:"student_id,omitempty"`
+	SlotID         string `json:"slot_id,omitempty"`
+	Rule           string `json:"rule,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	Detail         string `json:"detail,omitempty"`
 }
 
 func StartListening(ch *amqp.Channel, q amqp.Queue) {
@@ -39,6 +46,23 @@ func processMessage(body []byte) {
 		return
 	}
 
+	if msg.Type == "risk_signal" {
+		// Questionnaire answers from booking-service that matched a risk rule
+		_, err := risk.Raise(config.DB, risk.Signal{
+			StudentID:   msg.StudentID,
+			Source:      models.RiskSourceQuestionnaire,
+			Rule:        msg.Rule,
+			Description: msg.Description,
+			Severity:    msg.Severity,
+			Detail:      msg.Detail,
+			SlotID:      msg.SlotID,
+		})
+		if err != nil {
+			log.Printf("Failed to raise risk alert for student %s: %v", msg.StudentID, err)
+		}
+		return
+	}
+
 	if msg.Type == "new_rating" {
 		var user models.UserProfile
 		if err := config.DB.First(&user, "id = ?", msg.PsychologistID).Error; err != nil {
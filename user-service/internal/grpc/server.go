@@ -3,13 +3,17 @@ package grpcserver
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/pokonti/psychologist-backend/user-service/config"
 	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/mood"
+	"github.com/pokonti/psychologist-backend/user-service/internal/preferences"
 	"github.com/pokonti/psychologist-backend/user-service/internal/repository"
+	"github.com/pokonti/psychologist-backend/user-service/internal/timezone"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-
 	"gorm.io/gorm"
 )
 
@@ -56,6 +60,7 @@ func (s *UserProfileServer) GetUserProfileByID(ctx context.Context, req *userpro
 		Email:          p.Email,
 		Phone:          p.Phone,
 		TelegramChatId: p.TelegramChatID,
+		TimeZone:       p.TimeZone,
 	}, nil
 }
 
@@ -78,6 +83,7 @@ func (s *UserProfileServer) GetBatchUserProfiles(ctx context.Context, req *userp
 			FullName:       u.FullName,
 			Email:          u.Email,
 			TelegramChatId: u.TelegramChatID,
+			TimeZone:       u.TimeZone,
 		})
 	}
 
@@ -98,16 +104,89 @@ func (s *UserProfileServer) UpdateUserPhone(ctx context.Context, req *userprofil
 	return &userprofile.UpdateUserPhoneResponse{Success: true}, nil
 }
 
-func (s *UserProfileServer) UpdateUserTelegram(ctx context.Context, req *userprofile.UpdateUserTelegramRequest) (*userprofile.UpdateUserTelegramResponse, error) {
+func (s *UserProfileServer) GetConsentedMoodLogs(ctx context.Context, req *userprofile.GetConsentedMoodLogsRequest) (*userprofile.GetConsentedMoodLogsResponse, error) {
+	var consent models.MoodConsent
+	err := config.DB.WithContext(ctx).
+		Where("student_id = ? AND psychologist_id = ? AND revoked_at IS NULL", req.StudentId, req.PsychologistId).
+		First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.PermissionDenied, "the student has not shared their mood logs")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	from, errFrom := time.Parse("2006-01-02", req.From)
+	to, errTo := time.Parse("2006-01-02", req.To)
+	if errFrom != nil || errTo != nil || from.After(to) {
+		return nil, status.Error(codes.InvalidArgument, "from and to must be dates in YYYY-MM-DD format, from not after to")
+	}
+	if to.Sub(from) >= mood.MaxRangeDays*24*time.Hour {
+		return nil, status.Errorf(codes.InvalidArgument, "the range can span at most %d days", mood.MaxRangeDays)
+	}
+
+	var logs []models.MoodLog
+	err = config.DB.WithContext(ctx).
+		Where("user_id = ? AND date BETWEEN ? AND ?", req.StudentId, req.From, req.To).
+		Order("logged_at asc").
+		Find(&logs).Error
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	// Shown to the student as when the psychologist last looked
+	config.DB.WithContext(ctx).Model(&consent).Update("last_viewed_at", time.Now())
+
+	entries := make([]*userprofile.MoodEntry, 0, len(logs))
+	for _, l := range logs {
+		entry := &userprofile.MoodEntry{
+			Id:       l.ID,
+			LoggedAt: l.LoggedAt.Format(time.RFC3339),
+			Date:     l.Day(),
+			Mood:     l.Mood,
+			Score:    int32(l.Score),
+			Tags:     l.Tags,
+		}
+		if consent.ShareNotes {
+			entry.Note = l.Note
+		}
+		entries = append(entries, entry)
+	}
+
+	return &userprofile.GetConsentedMoodLogsResponse{Entries: entries, NotesShared: consent.ShareNotes}, nil
+}
+
+func (s *UserProfileServer) GetNotificationPreferences(ctx context.Context, req *userprofile.GetNotificationPreferencesRequest) (*userprofile.GetNotificationPreferencesResponse, error) {
 	var profile models.UserProfile
-	if err, _ := s.Repo.GetByID(ctx, req.Id); err != nil {
+	err := config.DB.WithContext(ctx).Select("id", "telegram_chat_id", "time_zone", "locale", "phone").
+		First(&profile, "email = ?", req.Email).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, status.Error(codes.NotFound, "profile not found")
 	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
 
-	profile.TelegramChatID = req.TelegramChatId
-	if err := s.Repo.Update(ctx, &profile); err != nil {
-		return nil, status.Error(codes.Internal, "failed to update telegram chat id")
+	prefs, err := preferences.Load(config.DB.WithContext(ctx), profile.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	return &userprofile.UpdateUserTelegramResponse{Success: true}, nil
+	resp := &userprofile.GetNotificationPreferencesResponse{
+		UserId:         profile.ID,
+		TelegramChatId: profile.TelegramChatID,
+		Categories:     make(map[string]*userprofile.ChannelPreferences, len(prefs.Categories)),
+		InQuietHours:   preferences.InQuietHours(prefs.QuietHours, time.Now(), timezone.Location(profile.TimeZone)),
+		Locale:         profile.Locale,
+		Sms:            prefs.SMS,
+		PhoneNumber:    profile.Phone,
+	}
+	for category, channels := range prefs.Categories {
+		resp.Categories[category] = &userprofile.ChannelPreferences{
+			Email:    channels.Email,
+			Telegram: channels.Telegram,
+			InApp:    channels.InApp,
+		}
+	}
+	return resp, nil
 }
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GrantMoodConsent godoc
+// @Summary      Share my mood logs with a psychologist
+// @Description  Lets the psychologist see the student's mood timeline while they have a booked session together. Journal notes are only shared when share_notes is set. Granting again updates share_notes.
+// @Tags         well-being
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.MoodConsentInput true "Psychologist to share with"
+// @Success      201 {object} models.MoodConsent "Consent granted"
+// @Success      200 {object} models.MoodConsent "Existing consent updated"
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /users/me/mood/consents [post]
+func (h *ProfileHandler) GrantMoodConsent(c *gin.Context) {
+	studentID := c.GetHeader("X-User-ID")
+	if c.GetHeader("X-User-Role") != "student" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only students can share their mood logs"})
+		return
+	}
+
+	var input models.MoodConsentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var psych models.UserProfile
+	if err := config.DB.First(&psych, "id = ? AND role = ?", input.PsychologistID, "psychologist").Error; err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Psychologist not found"})
+		return
+	}
+
+	var consent models.MoodConsent
+	err := config.DB.Where("student_id = ? AND psychologist_id = ? AND revoked_at IS NULL", studentID, input.PsychologistID).
+		First(&consent).Error
+	if err == nil {
+		if err := config.DB.Model(&consent).Update("share_notes", input.ShareNotes).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+		consent.ShareNotes = input.ShareNotes
+		c.JSON(http.StatusOK, consent)
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	consent = models.MoodConsent{
+		ID:             uuid.NewString(),
+		StudentID:      studentID,
+		PsychologistID: input.PsychologistID,
+		ShareNotes:     input.ShareNotes,
+		GrantedAt:      time.Now(),
+	}
+	// idx_mood_consent_active makes a concurrent second grant a no-op
+	res := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&consent)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Consent was granted at the same time. Please try again."})
+		return
+	}
+
+	c.JSON(http.StatusCreated, consent)
+}
+
+// GetMoodConsents godoc
+// @Summary      List who can see my mood logs
+// @Description  Returns the student's consents, active ones first, including revoked ones as a record.
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.MoodConsentResponse
+// @Router       /users/me/mood/consents [get]
+func (h *ProfileHandler) GetMoodConsents(c *gin.Context) {
+	studentID := c.GetHeader("X-User-ID")
+
+	var consents []models.MoodConsent
+	err := config.DB.Where("student_id = ?", studentID).
+		Order("revoked_at IS NOT NULL, granted_at desc").
+		Find(&consents).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	ids := make([]string, 0, len(consents))
+	for _, consent := range consents {
+		ids = append(ids, consent.PsychologistID)
+	}
+	names := map[string]string{}
+	if len(ids) > 0 {
+		var psychs []models.UserProfile
+		if err := config.DB.Select("id", "full_name").Where("id IN ?", ids).Find(&psychs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+		for _, p := range psychs {
+			names[p.ID] = p.FullName
+		}
+	}
+
+	response := make([]models.MoodConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		response = append(response, models.MoodConsentResponse{
+			MoodConsent:      consent,
+			PsychologistName: names[consent.PsychologistID],
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeMoodConsent godoc
+// @Summary      Stop sharing my mood logs with a psychologist
+// @Description  Takes effect immediately. The consent is kept, marked as revoked.
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Param        psychologist_id path string true "Psychologist ID"
+// @Success      200 {object} models.MessageResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/me/mood/consents/{psychologist_id} [delete]
+func (h *ProfileHandler) RevokeMoodConsent(c *gin.Context) {
+	studentID := c.GetHeader("X-User-ID")
+
+	res := config.DB.Model(&models.MoodConsent{}).
+		Where("student_id = ? AND psychologist_id = ? AND revoked_at IS NULL", studentID, c.Param("psychologist_id")).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "No active consent for this psychologist"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Consent revoked"})
+}
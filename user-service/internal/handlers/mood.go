@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/mood"
+	"github.com/pokonti/psychologist-backend/user-service/internal/risk"
+	"github.com/pokonti/psychologist-backend/user-service/internal/timezone"
+)
+
+// maxBackdate is how far in the past an entry may be logged
+const maxBackdate = 7 * 24 * time.Hour
+
+// LogMood godoc
+// @Summary      Log a mood entry
+// @Description  Student records how they are feeling, optionally with a journal note and activity tags. Any number of entries can be logged per day. logged_at defaults to now and may be up to 7 days in the past.
+// @Tags         well-being
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.LogMoodInput true "Mood entry"
+// @Success      201 {object} models.MoodLog
+// @Failure      400 {object} models.ErrorResponse
+// @Router       /users/me/mood [post]
+func (h *ProfileHandler) LogMood(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	var input models.LogMoodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	now := time.Now()
+	loggedAt := now
+	if input.LoggedAt != nil {
+		if input.LoggedAt.After(now.Add(5 * time.Minute)) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "logged_at cannot be in the future"})
+			return
+		}
+		if input.LoggedAt.Before(now.Add(-maxBackdate)) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Entries can be backdated by at most 7 days"})
+			return
+		}
+		loggedAt = *input.LoggedAt
+	}
+
+	moodLog, err := mood.Record(config.DB, userID, input, loggedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to log mood"})
+		return
+	}
+
+	risk.CheckMood(config.DB, userID, now.In(timezone.ForUser(config.DB, userID)))
+
+	c.JSON(http.StatusCreated, moodLog)
+}
+
+// GetMoodEntries godoc
+// @Summary      List mood entries
+// @Description  Returns the student's mood entries with notes and tags, newest first. Dates are days in the student's time zone; the range defaults to the last 7 days.
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from  query string false "First day, YYYY-MM-DD"
+// @Param        to    query string false "Last day, YYYY-MM-DD (default: today)"
+// @Param        limit query int    false "Maximum number of entries (default 100, max 500)"
+// @Success      200 {array}  models.MoodLog
+// @Failure      400 {object} models.ErrorResponse
+// @Router       /users/me/mood [get]
+func (h *ProfileHandler) GetMoodEntries(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	from, to, _, err := moodRange(c, timezone.ForUser(config.DB, userID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	entries := []models.MoodLog{}
+	err = config.DB.
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("logged_at desc").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	for i := range entries {
+		entries[i].Date = entries[i].Day()
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// DeleteMoodEntry godoc
+// @Summary      Delete a mood entry
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Entry ID"
+// @Success      200 {object} models.MessageResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/me/mood/{id} [delete]
+func (h *ProfileHandler) DeleteMoodEntry(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	res := config.DB.Delete(&models.MoodLog{}, "id = ? AND user_id = ?", c.Param("id"), userID)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Entry not found"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Entry deleted"})
+}
+
+// GetMoodGraphic godoc
+// @Summary      Get mood data for graphic
+// @Description  Returns one point per day with the last mood logged that day. Filter by 'last_week' or 'last_month'. Kept for existing clients: GET /users/me/mood/chart adds weekly aggregation, custom ranges and insights.
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Param        filter query string false "Filter period (default: last_week)" Enums(last_week, last_month)
+// @Success      200 {array} models.MoodGraphicResponse
+// @Router       /users/me/mood/graphic [get]
+func (h *ProfileHandler) GetMoodGraphic(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	filter := c.DefaultQuery("filter", "last_week")
+
+	today := time.Now().In(timezone.ForUser(config.DB, userID))
+	startDate := today.AddDate(0, 0, -6)
+	if filter == "last_month" {
+		startDate = today.AddDate(0, -1, 0)
+	}
+
+	var logs []models.MoodLog
+	err := config.DB.
+		Where("user_id = ? AND date >= ?", userID, startDate.Format("2006-01-02")).
+		Order("logged_at asc").
+		Find(&logs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	// Days used to hold a single entry; the latest one stands for the day
+	response := []models.MoodGraphicResponse{}
+	for _, l := range logs {
+		parsedDate, _ := time.Parse("2006-01-02", l.Day())
+		point := models.MoodGraphicResponse{
+			Date:      l.Day(),
+			DayOfWeek: parsedDate.Format("Mon"),
+			Mood:      l.Mood,
+			Score:     l.Score,
+		}
+		if n := len(response); n > 0 && response[n-1].Date == point.Date {
+			response[n-1] = point
+			continue
+		}
+		response = append(response, point)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMoodChart godoc
+// @Summary      Get the mood chart with insights
+// @Description  Aggregates the student's mood entries per day or per week over any range of up to 366 days (default: the last 7 days) and computes insights: streaks, weekday averages, the trend and how activity tags relate to the score.
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from     query string false "First day, YYYY-MM-DD"
+// @Param        to       query string false "Last day, YYYY-MM-DD (default: today)"
+// @Param        group_by query string false "Aggregation (default: day)" Enums(day, week)
+// @Success      200 {object} models.MoodChartResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Router       /users/me/mood/chart [get]
+func (h *ProfileHandler) GetMoodChart(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	loc := timezone.ForUser(config.DB, userID)
+
+	from, to, today, err := moodRange(c, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", mood.GroupByDay)
+	if groupBy != mood.GroupByDay && groupBy != mood.GroupByWeek {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "group_by must be day or week"})
+		return
+	}
+
+	var logs []models.MoodLog
+	err = config.DB.
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("logged_at asc").
+		Find(&logs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	// The current streak can reach back before the range
+	var loggedDays []string
+	err = config.DB.Model(&models.MoodLog{}).
+		Where("user_id = ? AND date > ?", userID, today.AddDate(0, 0, -mood.MaxRangeDays).Format("2006-01-02")).
+		Distinct().
+		Pluck("date", &loggedDays).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MoodChartResponse{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		Points:   mood.Aggregate(logs, from, to, groupBy),
+		Insights: mood.Insights(logs, from, to, loggedDays, today),
+	})
+}
+
+// moodRange reads the from and to query days. Days are returned as UTC midnights so date
+// arithmetic is not thrown off by daylight saving; today is the current day in loc.
+func moodRange(c *gin.Context, loc *time.Location) (from, to, today time.Time, err error) {
+	today, _ = time.Parse("2006-01-02", time.Now().In(loc).Format("2006-01-02"))
+
+	to = today
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return from, to, today, fmt.Errorf("to must be a date in YYYY-MM-DD format")
+		}
+	}
+	from = to.AddDate(0, 0, -6)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return from, to, today, fmt.Errorf("from must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	if from.After(to) {
+		return from, to, today, fmt.Errorf("from must not be after to")
+	}
+	if to.Sub(from) >= mood.MaxRangeDays*24*time.Hour {
+		return from, to, today, fmt.Errorf("the range can span at most %d days", mood.MaxRangeDays)
+	}
+	return from, to, today, nil
+}
+
+// GetMoodReminder godoc
+// @Summary      Get my daily mood check-in
+// @Tags         well-being
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.MoodReminder
+// @Router       /users/me/mood/reminder [get]
+func (h *ProfileHandler) GetMoodReminder(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	reminder := models.MoodReminder{UserID: userID}
+	if err := config.DB.Where("user_id = ?", userID).Limit(1).Find(&reminder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, reminder)
+}
+
+// SetMoodReminder godoc
+// @Summary      Turn the daily mood check-in on or off
+// @Description  Once a day at the chosen time (in the user's time zone) the Telegram bot asks how the user feels, with one button per mood. Requires a linked Telegram account. Sending /stop to the bot turns it off too.
+// @Tags         well-being
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.MoodReminderInput true "Check-in settings"
+// @Success      200 {object} models.MoodReminder
+// @Failure      400 {object} models.ErrorResponse
+// @Router       /users/me/mood/reminder [put]
+func (h *ProfileHandler) SetMoodReminder(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	var input models.MoodReminderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var profile models.UserProfile
+	if err := config.DB.First(&profile, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Profile not found"})
+		return
+	}
+
+	reminder := models.MoodReminder{UserID: userID}
+	if err := config.DB.Where("user_id = ?", userID).Limit(1).Find(&reminder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if input.Time != "" {
+		reminder.Time = input.Time
+	}
+	reminder.Enabled = *input.Enabled
+
+	if reminder.Enabled {
+		if profile.TelegramChatID == "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Link your Telegram account first"})
+			return
+		}
+		if reminder.Time == "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "time is required to turn the check-in on"})
+			return
+		}
+		// A time that already passed today starts tomorrow
+		now := time.Now().In(timezone.Location(profile.TimeZone))
+		if now.Format("15:04") >= reminder.Time {
+			reminder.LastSentOn = now.Format("2006-01-02")
+		} else {
+			reminder.LastSentOn = ""
+		}
+	}
+
+	if err := config.DB.Save(&reminder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, reminder)
+}
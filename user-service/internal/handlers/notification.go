@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/preferences"
+)
+
+// GetNotificationPreferences godoc
+// @Summary      Get my notification preferences
+// @Description  Which channels (email, telegram, in_app) each category of notifications goes to, whether session reminders and cancellations are also sent by SMS, and the quiet hours. Account, booking and risk-alert messages are always sent.
+// @Tags         profile
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.NotificationPreference
+// @Router       /users/me/notification-preferences [get]
+func (h *ProfileHandler) GetNotificationPreferences(c *gin.Context) {
+	prefs, err := preferences.Load(config.DB, c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary      Update my notification preferences
+// @Description  Replaces the channels of the categories sent (reminders, waitlist, recommendations, marketing); the others keep their settings. SMS (session reminders and cancellations by the psychologist) needs a phone number in the profile. Telegram messages are sent silently during quiet hours, which are in the user's time zone and may span midnight. Leaving quiet_hours out keeps them, sending it as null turns them off.
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.NotificationPreferenceInput true "Preferences"
+// @Success      200 {object} models.NotificationPreference
+// @Failure      400 {object} models.ErrorResponse
+// @Router       /users/me/notification-preferences [put]
+func (h *ProfileHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	var input models.NotificationPreferenceInput
+	if err := c.ShouldBindBodyWith(&input, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	for category := range input.Categories {
+		if !slices.Contains(userprofile.NotificationCategories, category) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unknown notification category: " + category})
+			return
+		}
+	}
+
+	// A missing quiet_hours and one sent as null both decode to nil; only null turns them off
+	var sent struct {
+		QuietHours json.RawMessage `json:"quiet_hours"`
+	}
+	if err := c.ShouldBindBodyWith(&sent, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	prefs, err := preferences.Load(config.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if input.SMS != nil && *input.SMS && !prefs.SMS {
+		var profile models.UserProfile
+		if err := config.DB.Select("phone").First(&profile, "id = ?", userID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+			return
+		}
+		if profile.Phone == "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Add a phone number to your profile before turning on SMS"})
+			return
+		}
+	}
+
+	for category, channels := range input.Categories {
+		prefs.Categories[category] = channels
+	}
+	if input.SMS != nil {
+		prefs.SMS = *input.SMS
+	}
+	if sent.QuietHours != nil {
+		prefs.QuietHours = input.QuietHours
+	}
+
+	if err := config.DB.Save(&prefs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/preferences"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func updatePreferences(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/me/notification-preferences", (&ProfileHandler{}).UpdateNotificationPreferences)
+
+	req := httptest.NewRequest(http.MethodPut, "/me/notification-preferences", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "user-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateNotificationPreferencesQuietHours(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.UserProfile{}, &models.NotificationPreference{}))
+	config.DB = db
+
+	quietHours := func() *models.QuietHours {
+		prefs, err := preferences.Load(db, "user-1")
+		require.NoError(t, err)
+		return prefs.QuietHours
+	}
+
+	w := updatePreferences(t, `{"quiet_hours":{"start":"22:00","end":"08:00"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, quietHours())
+
+	// Changing something else keeps them
+	w = updatePreferences(t, `{"categories":{"marketing":{"email":true}}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, quietHours())
+	assert.Equal(t, "22:00", quietHours().Start)
+
+	w = updatePreferences(t, `{"quiet_hours":{"start":"25:00","end":"08:00"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = updatePreferences(t, `{"categories":{"newsletter":{"email":true}}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NotNil(t, quietHours())
+
+	// Only an explicit null turns them off
+	w = updatePreferences(t, `{"quiet_hours":null}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, quietHours())
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/risk"
+	"gorm.io/gorm"
+)
+
+func isPsychOrAdmin(c *gin.Context) bool {
+	role := c.GetHeader("X-User-Role")
+	return role == "psychologist" || role == "admin"
+}
+
+// GetRiskAlerts godoc
+// @Summary      List risk alerts
+// @Description  Alerts raised from students' mood logs and questionnaire answers, newest first. Open alerts are shown by default.
+// @Tags         risk-alerts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status query string false "Alert status (default: open)" Enums(open, acknowledged, all)
+// @Param        limit  query int    false "Maximum number of alerts (default 50, max 200)"
+// @Success      200 {array}  models.RiskAlertResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /users/risk-alerts [get]
+func (h *ProfileHandler) GetRiskAlerts(c *gin.Context) {
+	if !isPsychOrAdmin(c) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Psychologist access required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	query := config.DB.Order("created_at desc").Limit(limit)
+	switch status := c.DefaultQuery("status", models.RiskAlertOpen); status {
+	case models.RiskAlertOpen, models.RiskAlertAcknowledged:
+		query = query.Where("status = ?", status)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid status"})
+		return
+	}
+
+	var alerts []models.RiskAlert
+	if err := query.Find(&alerts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	response, err := riskAlertResponses(alerts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRiskAlert godoc
+// @Summary      Get a risk alert
+// @Description  Returns the alert with its full trail: when it was raised, who was paged, escalations and the acknowledgement.
+// @Tags         risk-alerts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Alert ID"
+// @Success      200 {object} models.RiskAlertResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/risk-alerts/{id} [get]
+func (h *ProfileHandler) GetRiskAlert(c *gin.Context) {
+	if !isPsychOrAdmin(c) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Psychologist access required"})
+		return
+	}
+
+	var alert models.RiskAlert
+	err := config.DB.Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).First(&alert, "id = ?", c.Param("id")).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Alert not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+
+	response, err := riskAlertResponses([]models.RiskAlert{alert})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, response[0])
+}
+
+// AcknowledgeRiskAlert godoc
+// @Summary      Acknowledge a risk alert
+// @Description  Records that the psychologist has taken charge of the alert, which stops further escalation. The note is kept in the alert's trail.
+// @Tags         risk-alerts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path string                           true  "Alert ID"
+// @Param        request body models.AcknowledgeRiskAlertInput false "What was done"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Failure      409 {object} models.ErrorResponse "Already acknowledged"
+// @Router       /users/risk-alerts/{id}/acknowledge [post]
+func (h *ProfileHandler) AcknowledgeRiskAlert(c *gin.Context) {
+	if !isPsychOrAdmin(c) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Psychologist access required"})
+		return
+	}
+
+	var input models.AcknowledgeRiskAlertInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	alertID := c.Param("id")
+	acknowledged, err := risk.Acknowledge(config.DB, alertID, c.GetHeader("X-User-ID"), input.Note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if !acknowledged {
+		var alert models.RiskAlert
+		if err := config.DB.First(&alert, "id = ?", alertID).Error; err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Alert not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Alert was already acknowledged"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Alert acknowledged"})
+}
+
+// riskAlertResponses adds the students' contact details to the alerts
+func riskAlertResponses(alerts []models.RiskAlert) ([]models.RiskAlertResponse, error) {
+	ids := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		ids = append(ids, a.StudentID)
+	}
+
+	students := map[string]models.UserProfile{}
+	if len(ids) > 0 {
+		var profiles []models.UserProfile
+		if err := config.DB.Where("id IN ?", ids).Find(&profiles).Error; err != nil {
+			return nil, err
+		}
+		for _, p := range profiles {
+			students[p.ID] = p
+		}
+	}
+
+	response := make([]models.RiskAlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		student := students[a.StudentID]
+		response = append(response, models.RiskAlertResponse{
+			RiskAlert:    a,
+			StudentName:  student.FullName,
+			StudentEmail: student.Email,
+			StudentPhone: student.Phone,
+		})
+	}
+	return response, nil
+}
+
+// CreateDutyShift godoc
+// @Summary      Admin: Put a psychologist on duty
+// @Description  Risk alerts raised during the shift are sent to the psychologists on duty. When nobody is on duty, every psychologist is paged.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body models.DutyShiftInput true "Shift"
+// @Success      201 {object} models.DutyShift
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /users/admin/duty-shifts [post]
+func (h *ProfileHandler) CreateDutyShift(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var input models.DutyShiftInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var psych models.UserProfile
+	if err := config.DB.First(&psych, "id = ? AND role = ?", input.PsychologistID, "psychologist").Error; err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Psychologist not found"})
+		return
+	}
+
+	shift := models.DutyShift{
+		ID:             uuid.NewString(),
+		PsychologistID: input.PsychologistID,
+		StartsAt:       input.StartsAt,
+		EndsAt:         input.EndsAt,
+		CreatedBy:      c.GetHeader("X-User-ID"),
+	}
+	if err := config.DB.Create(&shift).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusCreated, shift)
+}
+
+// GetDutyShifts godoc
+// @Summary      Admin: List duty shifts
+// @Description  Returns the current and upcoming duty shifts in start order.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array}  models.DutyShift
+// @Failure      403 {object} models.ErrorResponse
+// @Router       /users/admin/duty-shifts [get]
+func (h *ProfileHandler) GetDutyShifts(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	shifts := []models.DutyShift{}
+	if err := config.DB.Where("ends_at > ?", time.Now()).Order("starts_at asc").Find(&shifts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, shifts)
+}
+
+// DeleteDutyShift godoc
+// @Summary      Admin: Remove a duty shift
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Shift ID"
+// @Success      200 {object} models.MessageResponse
+// @Failure      403 {object} models.ErrorResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/admin/duty-shifts/{id} [delete]
+func (h *ProfileHandler) DeleteDutyShift(c *gin.Context) {
+	if c.GetHeader("X-User-Role") != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	res := config.DB.Delete(&models.DutyShift{}, "id = ?", c.Param("id"))
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Shift not found"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Shift removed"})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/telegramlink"
+)
+
+// CreateTelegramLink godoc
+// @Summary      Get a link for connecting Telegram
+// @Description  Issues a signed token that links the Telegram chat which sends it to the bot (/start <token>) to this profile. The token works once and expires after 10 minutes; requesting a new one invalidates the previous one. url opens the bot with the token filled in.
+// @Tags         profile
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201 {object} models.TelegramLinkResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/me/telegram/link [post]
+func (h *ProfileHandler) CreateTelegramLink(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+
+	var profile models.UserProfile
+	if err := config.DB.Select("id").First(&profile, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Profile not found"})
+		return
+	}
+
+	token, expiresAt, err := telegramlink.Issue(config.DB, userID)
+	if err != nil {
+		log.Printf("Failed to issue Telegram link token for %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create Telegram link"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.TelegramLinkResponse{
+		Token:     token,
+		URL:       telegramlink.URL(token),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// UnlinkTelegram godoc
+// @Summary      Disconnect Telegram
+// @Description  Stops all Telegram messages to this profile and turns off the daily mood check-in. Sending /unlink to the bot does the same.
+// @Tags         profile
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} models.MessageResponse
+// @Failure      404 {object} models.ErrorResponse
+// @Router       /users/me/telegram [delete]
+func (h *ProfileHandler) UnlinkTelegram(c *gin.Context) {
+	unlinked, err := telegramlink.Unlink(config.DB, c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Database error"})
+		return
+	}
+	if !unlinked {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "No Telegram account is linked"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Telegram disconnected"})
+}
@@ -24,6 +24,8 @@ type UserProfile struct {
 	Rating         float32   `json:"rating"`
 	RatingCount    int       `json:"rating_count"`
 	TelegramChatID string    `json:"telegram_chat_id"`
+	TimeZone       string    `gorm:"type:varchar(64);default:'Asia/Almaty'" json:"time_zone"` // IANA name, e.g. "Asia/Almaty"
+	Locale         string    `gorm:"type:varchar(2);default:'en'" json:"locale"`              // language of notifications: kk, ru or en
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
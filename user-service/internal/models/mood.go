@@ -4,23 +4,125 @@ import (
 	"time"
 )
 
+// MoodLog is one mood entry. A student can log as many as they like per day.
 type MoodLog struct {
 	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_user_date" json:"user_id"`
-	Date      string    `gorm:"type:date;not null;uniqueIndex:idx_user_date" json:"date"` // Format: YYYY-MM-DD
+	UserID    string    `gorm:"type:uuid;not null;index:idx_mood_user_logged;index:idx_user_date" json:"user_id"`
+	LoggedAt  time.Time `gorm:"index:idx_mood_user_logged" json:"logged_at"`
+	Date      string    `gorm:"type:date;not null;index:idx_user_date" json:"date"` // Day of LoggedAt in the student's time zone, YYYY-MM-DD
 	Mood      string    `gorm:"not null" json:"mood"`
 	Score     int       `gorm:"not null" json:"score"` // 1 to 6 (for the graph)
+	Note      string    `gorm:"type:text" json:"note,omitempty"`
+	Tags      []string  `gorm:"serializer:json;type:jsonb" json:"tags,omitempty" example:"sleep,exams"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Day returns the entry's day as YYYY-MM-DD. Drivers read the date column back as a timestamp.
+func (l MoodLog) Day() string {
+	if len(l.Date) > len("2006-01-02") {
+		return l.Date[:len("2006-01-02")]
+	}
+	return l.Date
+}
+
 type LogMoodInput struct {
-	Mood string `json:"mood" binding:"required,oneof=Amazing Nice 'Not bad' Sad Anxiously Stressed"`
+	Mood     string     `json:"mood" binding:"required,oneof=Amazing Nice 'Not bad' Sad Anxiously Stressed"`
+	Note     string     `json:"note" binding:"max=2000" example:"Slept badly before the exam"`
+	Tags     []string   `json:"tags" binding:"max=10,dive,min=1,max=30" example:"sleep,exams"`
+	LoggedAt *time.Time `json:"logged_at" example:"2026-03-02T21:15:00+05:00"` // defaults to now, may be up to 7 days in the past
 }
 
+// MoodGraphicResponse is one day of the original mood graphic: the last mood logged that day
 type MoodGraphicResponse struct {
-	Date      string `json:"date"`        // "2026-02-23"
-	DayOfWeek string `json:"day_of_week"` // "Mon", "Tue"
-	Mood      string `json:"mood"`        // "Amazing"
-	Score     int    `json:"score"`       // 6
+	Date      string `json:"date" example:"2026-02-23"`
+	DayOfWeek string `json:"day_of_week" example:"Mon"`
+	Mood      string `json:"mood" example:"Amazing"`
+	Score     int    `json:"score" example:"6"`
+}
+
+// MoodChartResponse is the mood chart for a date range with insights computed over it
+type MoodChartResponse struct {
+	From     string       `json:"from" example:"2026-02-24"`
+	To       string       `json:"to" example:"2026-03-02"`
+	GroupBy  string       `json:"group_by" example:"day"` // day, week
+	Points   []MoodPoint  `json:"points"`
+	Insights MoodInsights `json:"insights"`
+}
+
+// MoodPoint aggregates the entries of one day or week. Periods without entries have no scores.
+type MoodPoint struct {
+	Date         string   `json:"date" example:"2026-02-23"`     // first day of the period
+	EndDate      string   `json:"end_date" example:"2026-03-01"` // last day of the period
+	DayOfWeek    string   `json:"day_of_week,omitempty" example:"Mon"`
+	Entries      int      `json:"entries" example:"3"`
+	AverageScore *float64 `json:"average_score" example:"4.33"`
+	MinScore     *int     `json:"min_score,omitempty" example:"2"`
+	MaxScore     *int     `json:"max_score,omitempty" example:"6"`
+	Mood         string   `json:"mood,omitempty" example:"Nice"` // most frequent mood in the period
+}
+
+type MoodInsights struct {
+	Entries         int              `json:"entries" example:"18"`
+	DaysLogged      int              `json:"days_logged" example:"6"`
+	AverageScore    *float64         `json:"average_score" example:"4.1"`
+	CurrentStreak   int              `json:"current_streak" example:"4"` // days in a row with an entry, up to today
+	LongestStreak   int              `json:"longest_streak" example:"5"` // within the range
+	Trend           string           `json:"trend" example:"improving"`  // improving, declining, stable or not_enough_data
+	TrendChange     float64          `json:"trend_change" example:"0.8"` // average of the second half of the range minus the first
+	MoodCounts      map[string]int   `json:"mood_counts"`
+	WeekdayAverages []WeekdayAverage `json:"weekday_averages"`
+	TagCorrelations []TagCorrelation `json:"tag_correlations"`
+}
+
+type WeekdayAverage struct {
+	DayOfWeek    string   `json:"day_of_week" example:"Mon"`
+	Entries      int      `json:"entries" example:"4"`
+	AverageScore *float64 `json:"average_score" example:"3.75"`
+}
+
+// TagCorrelation relates a tag to the scores of the entries that carry it. Correlation is
+// Pearson's r between having the tag and the score: positive means better moods with the tag.
+type TagCorrelation struct {
+	Tag          string  `json:"tag" example:"sport"`
+	Entries      int     `json:"entries" example:"5"`
+	AverageScore float64 `json:"average_score" example:"5.2"`
+	Difference   float64 `json:"difference" example:"1.3"` // average with the tag minus average without it
+	Correlation  float64 `json:"correlation" example:"0.62"`
+}
+
+// MoodConsent lets a psychologist see a student's mood logs until the student revokes it.
+// Revoked consents are kept as a record; granting again creates a new one.
+type MoodConsent struct {
+	ID             string     `gorm:"type:uuid;primaryKey" json:"id"`
+	StudentID      string     `gorm:"type:uuid;not null;index" json:"student_id"`
+	PsychologistID string     `gorm:"type:uuid;not null;index" json:"psychologist_id"`
+	ShareNotes     bool       `gorm:"not null;default:false" json:"share_notes"` // journal notes are shared too
+	GrantedAt      time.Time  `gorm:"not null" json:"granted_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	LastViewedAt   *time.Time `json:"last_viewed_at,omitempty"`
+}
+
+type MoodConsentInput struct {
+	PsychologistID string `json:"psychologist_id" binding:"required,uuid"`
+	ShareNotes     bool   `json:"share_notes"`
+}
+
+type MoodConsentResponse struct {
+	MoodConsent
+	PsychologistName string `json:"psychologist_name"`
+}
+
+// MoodReminder is a user's opt-in daily mood check-in over Telegram
+type MoodReminder struct {
+	UserID     string    `gorm:"type:uuid;primaryKey" json:"-"`
+	Enabled    bool      `gorm:"not null;default:false" json:"enabled"`
+	Time       string    `gorm:"type:varchar(5);not null" json:"time" example:"21:00"` // HH:MM in the user's time zone
+	LastSentOn string    `gorm:"type:varchar(10)" json:"-"`                            // day of the last reminder in the user's time zone
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type MoodReminderInput struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Time    string `json:"time" binding:"omitempty,datetime=15:04" example:"21:00"` // required to enable
 }
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ChannelPreferences says which channels a category of notifications goes to
+type ChannelPreferences struct {
+	Email    bool `json:"email"`
+	Telegram bool `json:"telegram"`
+	InApp    bool `json:"in_app"`
+}
+
+// NotificationPreference is how a user wants to be notified. Users without a row get the defaults.
+// Messages about the account itself, bookings and risk alerts are not optional and ignore it.
+// SMS is opt-in and only carries session reminders and cancellations by the psychologist, sent to
+// the profile's phone number.
+type NotificationPreference struct {
+	UserID     string                        `gorm:"type:uuid;primaryKey" json:"-"`
+	Categories map[string]ChannelPreferences `gorm:"serializer:json;type:jsonb" json:"categories"` // reminders, waitlist, recommendations, marketing
+	SMS        bool                          `gorm:"not null;default:false" json:"sms"`
+	QuietHours *QuietHours                   `gorm:"serializer:json;type:jsonb" json:"quiet_hours"`
+	UpdatedAt  time.Time                     `json:"updated_at"`
+}
+
+// QuietHours mutes Telegram messages between Start and End in the user's time zone. Start after End
+// spans midnight.
+type QuietHours struct {
+	Start string `json:"start" binding:"required,datetime=15:04" example:"22:00"`
+	End   string `json:"end" binding:"required,datetime=15:04" example:"08:00"`
+}
+
+// NotificationPreferenceInput replaces the categories it lists; the others keep their settings.
+// sms and quiet_hours left out keep the current choice. quiet_hours null turns quiet hours off.
+type NotificationPreferenceInput struct {
+	Categories map[string]ChannelPreferences `json:"categories"` // reminders, waitlist, recommendations, marketing
+	SMS        *bool                         `json:"sms"`
+	QuietHours *QuietHours                   `json:"quiet_hours"`
+}
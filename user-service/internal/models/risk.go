@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Risk alert sources
+const (
+	RiskSourceMood          = "mood"
+	RiskSourceQuestionnaire = "questionnaire"
+)
+
+// Risk alert states
+const (
+	RiskAlertOpen         = "open"
+	RiskAlertAcknowledged = "acknowledged"
+)
+
+// Actions recorded in an alert's trail
+const (
+	RiskEventRaised       = "raised"
+	RiskEventNotified     = "notified"
+	RiskEventEscalated    = "escalated"
+	RiskEventAcknowledged = "acknowledged"
+)
+
+// RiskAlert is raised when a student's mood logs or questionnaire answers match a risk rule.
+// Only one alert per student and rule is open at a time; it stays open until a psychologist
+// acknowledges it.
+type RiskAlert struct {
+	ID             string           `gorm:"type:uuid;primaryKey" json:"id"`
+	StudentID      string           `gorm:"type:uuid;not null;index" json:"student_id"`
+	Source         string           `gorm:"type:varchar(20);not null" json:"source" example:"mood"` // mood, questionnaire
+	Rule           string           `gorm:"type:varchar(50);not null" json:"rule" example:"distress_streak"`
+	Description    string           `json:"description" example:"Reported feeling stressed or anxious 3 days in a row"`
+	Severity       string           `gorm:"type:varchar(20);not null" json:"severity" example:"high"` // high, critical
+	Detail         string           `gorm:"type:text" json:"detail,omitempty"`
+	SlotID         string           `gorm:"type:varchar(36)" json:"slot_id,omitempty"` // booking the questionnaire came with
+	Status         string           `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	NotifiedAt     *time.Time       `json:"notified_at,omitempty"`
+	EscalatedAt    *time.Time       `json:"escalated_at,omitempty"`
+	AcknowledgedBy *string          `gorm:"type:uuid" json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Events         []RiskAlertEvent `gorm:"foreignKey:AlertID" json:"events,omitempty"`
+}
+
+// RiskAlertEvent is one entry of an alert's trail. Entries are only ever added.
+type RiskAlertEvent struct {
+	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
+	AlertID   string    `gorm:"type:uuid;not null;index" json:"alert_id"`
+	Action    string    `gorm:"type:varchar(20);not null" json:"action" example:"notified"`
+	ActorID   string    `gorm:"type:varchar(36)" json:"actor_id,omitempty"` // empty for the system
+	Note      string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DutyShift puts a psychologist on duty for risk alerts between StartsAt and EndsAt
+type DutyShift struct {
+	ID             string    `gorm:"type:uuid;primaryKey" json:"id"`
+	PsychologistID string    `gorm:"type:uuid;not null;index" json:"psychologist_id"`
+	StartsAt       time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt         time.Time `gorm:"not null;index" json:"ends_at"`
+	CreatedBy      string    `gorm:"type:uuid" json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type DutyShiftInput struct {
+	PsychologistID string    `json:"psychologist_id" binding:"required,uuid"`
+	StartsAt       time.Time `json:"starts_at" binding:"required" example:"2026-03-02T09:00:00+05:00"`
+	EndsAt         time.Time `json:"ends_at" binding:"required,gtfield=StartsAt" example:"2026-03-02T18:00:00+05:00"`
+}
+
+type AcknowledgeRiskAlertInput struct {
+	Note string `json:"note" binding:"max=2000" example:"Called the student, follow-up session booked"`
+}
+
+// RiskAlertResponse is an alert with the contact details the psychologist needs to reach out
+type RiskAlertResponse struct {
+	RiskAlert
+	StudentName  string `json:"student_name"`
+	StudentEmail string `json:"student_email"`
+	StudentPhone string `json:"student_phone,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TelegramLinkToken is a one-time deep-link token for connecting a Telegram chat to a profile.
+// Only a hash of the token's nonce is stored.
+type TelegramLinkToken struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	UserID    string    `gorm:"type:uuid;not null;index"`
+	NonceHash string    `gorm:"type:varchar(64);uniqueIndex;not null"` // sha256 hex
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type TelegramLinkResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url,omitempty" example:"https://t.me/kbtu_care_bot?start=..."` // set when TELEGRAM_BOT_USERNAME is configured
+	ExpiresAt time.Time `json:"expires_at"`
+}
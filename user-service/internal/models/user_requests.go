@@ -6,7 +6,9 @@ type UpdateProfileRequest struct {
 	Specialization *string `json:"specialization" binding:"omitempty"`
 	Bio            *string `json:"bio" binding:"omitempty"`
 	AvatarURL      *string `json:"avatar_url" binding:"omitempty"`
-	Phone          *string `json:"phone" binding:"omitempty"`
+	Phone          *string `json:"phone" binding:"omitempty,len=0|e164" example:"+77011234567"` // E.164, "" removes it
+	TimeZone       *string `json:"time_zone" binding:"omitempty"`                               // IANA name, e.g. "Asia/Almaty"
+	Locale         *string `json:"locale" binding:"omitempty,oneof=kk ru en"`
 }
 
 // PublicPsychologistResponse represents the safe public profile of a psychologist
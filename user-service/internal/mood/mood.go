@@ -0,0 +1,351 @@
+// Package mood aggregates mood entries for the well-being chart and computes insights over them.
+package mood
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/timezone"
+	"gorm.io/gorm"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	GroupByDay  = "day"
+	GroupByWeek = "week"
+
+	// MaxRangeDays bounds the chart range
+	MaxRangeDays = 366
+
+	// minTagEntries is how often a tag must be used before it is correlated with the score
+	minTagEntries = 3
+	// stableTrend is the change in average score below which the trend counts as stable
+	stableTrend = 0.5
+)
+
+// Moods are the moods a student can log, best first, as accepted by LogMoodInput
+var Moods = []string{"Amazing", "Nice", "Not bad", "Sad", "Anxiously", "Stressed"}
+
+// Record stores a mood entry logged at loggedAt. The entry's day is taken in the user's time zone.
+func Record(db *gorm.DB, userID string, input models.LogMoodInput, loggedAt time.Time) (models.MoodLog, error) {
+	entry := models.MoodLog{
+		ID:       uuid.NewString(),
+		UserID:   userID,
+		LoggedAt: loggedAt,
+		Date:     loggedAt.In(timezone.ForUser(db, userID)).Format(dateLayout),
+		Mood:     input.Mood,
+		Score:    Score(input.Mood),
+		Note:     strings.TrimSpace(input.Note),
+		Tags:     NormalizeTags(input.Tags),
+	}
+	return entry, db.Create(&entry).Error
+}
+
+// Score maps a mood to its place on the chart, 6 being the best
+func Score(mood string) int {
+	switch mood {
+	case "Amazing":
+		return 6
+	case "Nice":
+		return 5
+	case "Not bad":
+		return 4
+	case "Sad":
+		return 3
+	case "Anxiously":
+		return 2
+	case "Stressed":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// NormalizeTags lowercases and trims the tags and drops empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// Aggregate buckets the entries between from and to (inclusive, YYYY-MM-DD) by day or by week.
+// Weeks start on Monday; the first and last week are cut to the range.
+func Aggregate(logs []models.MoodLog, from, to time.Time, groupBy string) []models.MoodPoint {
+	byDay := make(map[string][]models.MoodLog)
+	for _, l := range logs {
+		byDay[l.Day()] = append(byDay[l.Day()], l)
+	}
+
+	points := []models.MoodPoint{}
+	for start := from; !start.After(to); {
+		end := start
+		if groupBy == GroupByWeek {
+			end = start.AddDate(0, 0, (7-int(start.Weekday()))%7) // next Sunday
+			if end.After(to) {
+				end = to
+			}
+		}
+
+		var bucket []models.MoodLog
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			bucket = append(bucket, byDay[d.Format(dateLayout)]...)
+		}
+
+		point := models.MoodPoint{
+			Date:    start.Format(dateLayout),
+			EndDate: end.Format(dateLayout),
+			Entries: len(bucket),
+		}
+		if groupBy == GroupByDay {
+			point.DayOfWeek = start.Format("Mon")
+		}
+		if len(bucket) > 0 {
+			avg, low, high := stats(bucket)
+			point.AverageScore = &avg
+			point.MinScore = &low
+			point.MaxScore = &high
+			point.Mood = dominant(bucket)
+		}
+		points = append(points, point)
+
+		start = end.AddDate(0, 0, 1)
+	}
+	return points
+}
+
+// Insights summarizes the entries between from and to. loggedDays are all days the student
+// has logged on (YYYY-MM-DD, any order) and today is the current day in the student's time zone;
+// they give the current streak, which may reach back before the range.
+func Insights(logs []models.MoodLog, from, to time.Time, loggedDays []string, today time.Time) models.MoodInsights {
+	insights := models.MoodInsights{
+		Entries:         len(logs),
+		Trend:           "not_enough_data",
+		MoodCounts:      map[string]int{},
+		WeekdayAverages: []models.WeekdayAverage{},
+		TagCorrelations: []models.TagCorrelation{},
+	}
+
+	days := make(map[string]bool)
+	for _, l := range logs {
+		days[l.Day()] = true
+		insights.MoodCounts[l.Mood]++
+	}
+	insights.DaysLogged = len(days)
+	if len(logs) > 0 {
+		avg, _, _ := stats(logs)
+		insights.AverageScore = &avg
+	}
+
+	// Longest run of logged days inside the range
+	run := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if days[d.Format(dateLayout)] {
+			run++
+			if run > insights.LongestStreak {
+				insights.LongestStreak = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	insights.CurrentStreak = currentStreak(loggedDays, today)
+	insights.Trend, insights.TrendChange = trend(logs, from, to)
+	insights.WeekdayAverages = weekdayAverages(logs)
+	insights.TagCorrelations = tagCorrelations(logs)
+	return insights
+}
+
+// currentStreak counts the days in a row with an entry up to today. A streak that reached
+// yesterday is still current until today is over.
+func currentStreak(loggedDays []string, today time.Time) int {
+	logged := make(map[string]bool, len(loggedDays))
+	for _, d := range loggedDays {
+		if len(d) > len(dateLayout) {
+			d = d[:len(dateLayout)]
+		}
+		logged[d] = true
+	}
+
+	day := today
+	if !logged[day.Format(dateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for logged[day.Format(dateLayout)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// trend compares the average score of the second half of the range with the first half
+func trend(logs []models.MoodLog, from, to time.Time) (string, float64) {
+	middle := from.AddDate(0, 0, int(to.Sub(from).Hours()/24+1)/2).Format(dateLayout)
+
+	var first, second []models.MoodLog
+	for _, l := range logs {
+		if l.Day() < middle {
+			first = append(first, l)
+		} else {
+			second = append(second, l)
+		}
+	}
+	if len(first) < 2 || len(second) < 2 {
+		return "not_enough_data", 0
+	}
+
+	before, _, _ := stats(first)
+	after, _, _ := stats(second)
+	change := round2(after - before)
+	switch {
+	case change >= stableTrend:
+		return "improving", change
+	case change <= -stableTrend:
+		return "declining", change
+	}
+	return "stable", change
+}
+
+func weekdayAverages(logs []models.MoodLog) []models.WeekdayAverage {
+	sums := make([]int, 7)
+	counts := make([]int, 7)
+	for _, l := range logs {
+		day, err := time.Parse(dateLayout, l.Day())
+		if err != nil {
+			continue
+		}
+		wd := (int(day.Weekday()) + 6) % 7 // Monday first
+		sums[wd] += l.Score
+		counts[wd]++
+	}
+
+	names := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	averages := make([]models.WeekdayAverage, 0, 7)
+	for i, name := range names {
+		wa := models.WeekdayAverage{DayOfWeek: name, Entries: counts[i]}
+		if counts[i] > 0 {
+			avg := round2(float64(sums[i]) / float64(counts[i]))
+			wa.AverageScore = &avg
+		}
+		averages = append(averages, wa)
+	}
+	return averages
+}
+
+// tagCorrelations relates every tag used at least minTagEntries times to the score. Tags on
+// every entry are left out since there is nothing to compare them with.
+func tagCorrelations(logs []models.MoodLog) []models.TagCorrelation {
+	n := float64(len(logs))
+	if n == 0 {
+		return []models.TagCorrelation{}
+	}
+
+	total := 0.0
+	for _, l := range logs {
+		total += float64(l.Score)
+	}
+	mean := total / n
+	variance := 0.0
+	for _, l := range logs {
+		variance += (float64(l.Score) - mean) * (float64(l.Score) - mean)
+	}
+	sd := math.Sqrt(variance / n)
+
+	type tally struct {
+		count int
+		sum   float64
+	}
+	tags := map[string]*tally{}
+	for _, l := range logs {
+		for _, tag := range l.Tags {
+			t, ok := tags[tag]
+			if !ok {
+				t = &tally{}
+				tags[tag] = t
+			}
+			t.count++
+			t.sum += float64(l.Score)
+		}
+	}
+
+	correlations := []models.TagCorrelation{}
+	for tag, t := range tags {
+		if t.count < minTagEntries || t.count == len(logs) {
+			continue
+		}
+		with := t.sum / float64(t.count)
+		without := (total - t.sum) / (n - float64(t.count))
+
+		// Pearson's r for a yes/no variable against the score (point-biserial)
+		r := 0.0
+		if sd > 0 {
+			p := float64(t.count) / n
+			r = (with - without) * math.Sqrt(p*(1-p)) / sd
+		}
+
+		correlations = append(correlations, models.TagCorrelation{
+			Tag:          tag,
+			Entries:      t.count,
+			AverageScore: round2(with),
+			Difference:   round2(with - without),
+			Correlation:  round2(r),
+		})
+	}
+
+	sort.Slice(correlations, func(i, j int) bool {
+		a, b := math.Abs(correlations[i].Correlation), math.Abs(correlations[j].Correlation)
+		if a != b {
+			return a > b
+		}
+		return correlations[i].Tag < correlations[j].Tag
+	})
+	return correlations
+}
+
+func stats(logs []models.MoodLog) (avg float64, low, high int) {
+	sum := 0
+	low, high = logs[0].Score, logs[0].Score
+	for _, l := range logs {
+		sum += l.Score
+		if l.Score < low {
+			low = l.Score
+		}
+		if l.Score > high {
+			high = l.Score
+		}
+	}
+	return round2(float64(sum) / float64(len(logs))), low, high
+}
+
+// dominant returns the most frequent mood, the lower-scoring one on a tie
+func dominant(logs []models.MoodLog) string {
+	counts := map[string]int{}
+	best := ""
+	for _, l := range logs {
+		counts[l.Mood]++
+	}
+	for mood, count := range counts {
+		if best == "" || count > counts[best] || (count == counts[best] && Score(mood) < Score(best)) {
+			best = mood
+		}
+	}
+	return best
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
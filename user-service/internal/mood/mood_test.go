@@ -0,0 +1,154 @@
+package mood
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse(dateLayout, s)
+	return d
+}
+
+func entry(day, mood string, tags ...string) models.MoodLog {
+	return models.MoodLog{Date: day, Mood: mood, Score: Score(mood), Tags: tags}
+}
+
+func TestCurrentStreak(t *testing.T) {
+	today := date("2026-03-10")
+
+	tests := []struct {
+		name   string
+		logged []string
+		want   int
+	}{
+		{"nothing logged", nil, 0},
+		{"only today", []string{"2026-03-10"}, 1},
+		{"three days up to today", []string{"2026-03-08", "2026-03-10", "2026-03-09"}, 3},
+		{"up to yesterday still counts", []string{"2026-03-08", "2026-03-09"}, 2},
+		{"broken two days ago", []string{"2026-03-07", "2026-03-09", "2026-03-10"}, 2},
+		{"last entry two days ago", []string{"2026-03-07", "2026-03-08"}, 0},
+		{"timestamps from the driver", []string{"2026-03-09T00:00:00Z", "2026-03-10T00:00:00Z"}, 2},
+		{"across a month boundary", []string{"2026-02-27", "2026-02-28", "2026-03-01"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, currentStreak(tt.logged, today))
+		})
+	}
+
+	assert.Equal(t, 3, currentStreak([]string{"2026-02-27", "2026-02-28", "2026-03-01"}, date("2026-03-01")))
+}
+
+func TestTrend(t *testing.T) {
+	from, to := date("2026-03-01"), date("2026-03-08")
+
+	tests := []struct {
+		name   string
+		logs   []models.MoodLog
+		trend  string
+		change float64
+	}{
+		{"no entries", nil, "not_enough_data", 0},
+		{"only the first half", []models.MoodLog{entry("2026-03-01", "Sad"), entry("2026-03-02", "Sad"), entry("2026-03-03", "Sad")}, "not_enough_data", 0},
+		{"one entry in the second half", []models.MoodLog{entry("2026-03-01", "Sad"), entry("2026-03-02", "Sad"), entry("2026-03-07", "Nice")}, "not_enough_data", 0},
+		{"improving", []models.MoodLog{
+			entry("2026-03-01", "Sad"), entry("2026-03-03", "Stressed"),
+			entry("2026-03-05", "Nice"), entry("2026-03-08", "Amazing"),
+		}, "improving", 3.5},
+		{"declining", []models.MoodLog{
+			entry("2026-03-02", "Amazing"), entry("2026-03-04", "Nice"),
+			entry("2026-03-06", "Sad"), entry("2026-03-07", "Not bad"),
+		}, "declining", -2},
+		{"stable", []models.MoodLog{
+			entry("2026-03-01", "Nice"), entry("2026-03-02", "Not bad"),
+			entry("2026-03-05", "Not bad"), entry("2026-03-06", "Nice"), entry("2026-03-07", "Nice"),
+		}, "stable", 0.17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, change := trend(tt.logs, from, to)
+			assert.Equal(t, tt.trend, got)
+			assert.InDelta(t, tt.change, change, 0.001)
+		})
+	}
+}
+
+func TestInsights(t *testing.T) {
+	from, to := date("2026-03-02"), date("2026-03-08") // Monday to Sunday
+	logs := []models.MoodLog{
+		entry("2026-03-02", "Stressed", "exams"),
+		entry("2026-03-02", "Sad", "exams", "sleep"),
+		entry("2026-03-03", "Sad", "exams"),
+		entry("2026-03-05", "Nice", "sport"),
+		entry("2026-03-06", "Amazing", "sport"),
+		entry("2026-03-07", "Nice", "sport"),
+		entry("2026-03-08", "Amazing"),
+	}
+	loggedDays := []string{"2026-02-28", "2026-03-02", "2026-03-03", "2026-03-05", "2026-03-06", "2026-03-07", "2026-03-08"}
+
+	insights := Insights(logs, from, to, loggedDays, date("2026-03-08"))
+
+	assert.Equal(t, 7, insights.Entries)
+	assert.Equal(t, 6, insights.DaysLogged)
+	require.NotNil(t, insights.AverageScore)
+	assert.InDelta(t, float64(1+3+3+5+6+5+6)/7, *insights.AverageScore, 0.01)
+	assert.Equal(t, 4, insights.CurrentStreak)
+	assert.Equal(t, 4, insights.LongestStreak)
+	assert.Equal(t, "improving", insights.Trend)
+	assert.Equal(t, map[string]int{"Stressed": 1, "Sad": 2, "Nice": 2, "Amazing": 2}, insights.MoodCounts)
+
+	require.Len(t, insights.WeekdayAverages, 7)
+	assert.Equal(t, "Mon", insights.WeekdayAverages[0].DayOfWeek)
+	assert.Equal(t, 2, insights.WeekdayAverages[0].Entries)
+	assert.Equal(t, 2.0, *insights.WeekdayAverages[0].AverageScore)
+	assert.Nil(t, insights.WeekdayAverages[2].AverageScore) // nothing on Wednesday
+
+	// sleep is used once, too rarely to correlate; exams go with low moods, sport with high ones
+	require.Len(t, insights.TagCorrelations, 2)
+	tags := map[string]models.TagCorrelation{}
+	for _, c := range insights.TagCorrelations {
+		tags[c.Tag] = c
+	}
+	assert.Equal(t, 3, tags["exams"].Entries)
+	assert.Less(t, tags["exams"].Correlation, 0.0)
+	assert.Less(t, tags["exams"].Difference, 0.0)
+	assert.Greater(t, tags["sport"].Correlation, 0.0)
+	assert.InDelta(t, float64(5+6+5)/3, tags["sport"].AverageScore, 0.01)
+}
+
+func TestInsightsEmpty(t *testing.T) {
+	insights := Insights(nil, date("2026-03-02"), date("2026-03-08"), nil, date("2026-03-08"))
+
+	assert.Zero(t, insights.Entries)
+	assert.Nil(t, insights.AverageScore)
+	assert.Equal(t, "not_enough_data", insights.Trend)
+	assert.NotNil(t, insights.MoodCounts)
+	assert.NotNil(t, insights.TagCorrelations)
+	assert.Len(t, insights.WeekdayAverages, 7)
+}
+
+func TestAggregateByWeek(t *testing.T) {
+	// Wednesday to the Tuesday after next: the first and last weeks are cut to the range
+	points := Aggregate([]models.MoodLog{
+		entry("2026-03-04", "Sad"),
+		entry("2026-03-08", "Nice"),
+		entry("2026-03-10", "Amazing"),
+	}, date("2026-03-04"), date("2026-03-17"), GroupByWeek)
+
+	require.Len(t, points, 3)
+	assert.Equal(t, "2026-03-04", points[0].Date)
+	assert.Equal(t, "2026-03-08", points[0].EndDate)
+	assert.Equal(t, 2, points[0].Entries)
+	assert.Equal(t, "2026-03-09", points[1].Date)
+	assert.Equal(t, "Amazing", points[1].Mood)
+	assert.Equal(t, "2026-03-16", points[2].Date)
+	assert.Equal(t, "2026-03-17", points[2].EndDate)
+	assert.Nil(t, points[2].AverageScore)
+}
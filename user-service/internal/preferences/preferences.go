@@ -0,0 +1,63 @@
+// Package preferences resolves how a user wants to be notified.
+package preferences
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// Defaults are the preferences of a user who has not changed anything: every channel on
+// except for marketing, which is opt-in.
+func Defaults(userID string) models.NotificationPreference {
+	on := models.ChannelPreferences{Email: true, Telegram: true, InApp: true}
+	return models.NotificationPreference{
+		UserID: userID,
+		Categories: map[string]models.ChannelPreferences{
+			userprofile.CategoryReminders:       on,
+			userprofile.CategoryWaitlist:        on,
+			userprofile.CategoryRecommendations: on,
+			userprofile.CategoryMarketing:       {},
+		},
+	}
+}
+
+// Load returns the user's preferences with defaults for every category they have not set
+func Load(db *gorm.DB, userID string) (models.NotificationPreference, error) {
+	prefs := Defaults(userID)
+
+	var stored models.NotificationPreference
+	err := db.First(&stored, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, err
+	}
+
+	for category, channels := range stored.Categories {
+		if _, ok := prefs.Categories[category]; ok {
+			prefs.Categories[category] = channels
+		}
+	}
+	prefs.SMS = stored.SMS
+	prefs.QuietHours = stored.QuietHours
+	prefs.UpdatedAt = stored.UpdatedAt
+	return prefs, nil
+}
+
+// InQuietHours reports whether now, in loc, falls into the quiet hours. The start is inclusive
+// and the end exclusive; quiet hours with the same start and end are never active.
+func InQuietHours(q *models.QuietHours, now time.Time, loc *time.Location) bool {
+	if q == nil || q.Start == q.End {
+		return false
+	}
+	clock := now.In(loc).Format("15:04")
+	if q.Start < q.End {
+		return clock >= q.Start && clock < q.End
+	}
+	return clock >= q.Start || clock < q.End
+}
@@ -0,0 +1,90 @@
+package preferences
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/proto/userprofile"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestInQuietHours(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+	at := func(clock string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", "2026-03-10 "+clock, time.UTC)
+		require.NoError(t, err)
+		return tm
+	}
+
+	night := &models.QuietHours{Start: "22:00", End: "08:00"}
+	lunch := &models.QuietHours{Start: "12:00", End: "13:30"}
+
+	tests := []struct {
+		name  string
+		quiet *models.QuietHours
+		now   time.Time
+		loc   *time.Location
+		want  bool
+	}{
+		{"not set", nil, at("23:00"), time.UTC, false},
+		{"same start and end", &models.QuietHours{Start: "22:00", End: "22:00"}, at("22:00"), time.UTC, false},
+		{"inside a daytime window", lunch, at("12:30"), time.UTC, true},
+		{"start is inclusive", lunch, at("12:00"), time.UTC, true},
+		{"end is exclusive", lunch, at("13:30"), time.UTC, false},
+		{"before a daytime window", lunch, at("11:59"), time.UTC, false},
+		{"before midnight", night, at("23:15"), time.UTC, true},
+		{"after midnight", night, at("03:00"), time.UTC, true},
+		{"midnight itself", night, at("00:00"), time.UTC, true},
+		{"start of a window across midnight", night, at("22:00"), time.UTC, true},
+		{"end of a window across midnight", night, at("08:00"), time.UTC, false},
+		{"afternoon outside a window across midnight", night, at("15:00"), time.UTC, false},
+		// 17:30 UTC is 22:30 in Almaty (UTC+5)
+		{"evening in the user's time zone", night, at("17:30"), almaty, true},
+		// 02:30 UTC is 07:30 in Almaty, 03:00 UTC is 08:00
+		{"early morning in the user's time zone", night, at("02:30"), almaty, true},
+		{"morning in the user's time zone", night, at("03:00"), almaty, false},
+		// 06:00 is quiet in UTC but already 11:00 in Almaty
+		{"quiet in UTC is not quiet in the user's time zone", night, at("06:00"), almaty, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, InQuietHours(tt.quiet, tt.now, tt.loc))
+		})
+	}
+}
+
+func TestLoadMergesStoredPreferencesWithDefaults(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.NotificationPreference{}))
+
+	prefs, err := Load(db, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, prefs.Categories, len(userprofile.NotificationCategories))
+	assert.True(t, prefs.Categories[userprofile.CategoryReminders].Email)
+	assert.False(t, prefs.Categories[userprofile.CategoryMarketing].Email, "marketing is opt-in")
+	assert.Nil(t, prefs.QuietHours)
+
+	require.NoError(t, db.Create(&models.NotificationPreference{
+		UserID: "user-1",
+		Categories: map[string]models.ChannelPreferences{
+			userprofile.CategoryWaitlist: {InApp: true},
+			"retired_category":           {Email: true},
+		},
+		QuietHours: &models.QuietHours{Start: "22:00", End: "08:00"},
+	}).Error)
+
+	prefs, err = Load(db, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.ChannelPreferences{InApp: true}, prefs.Categories[userprofile.CategoryWaitlist])
+	assert.True(t, prefs.Categories[userprofile.CategoryReminders].Telegram, "unset categories keep the default")
+	assert.NotContains(t, prefs.Categories, "retired_category")
+	require.NotNil(t, prefs.QuietHours)
+	assert.Equal(t, "22:00", prefs.QuietHours.Start)
+}
@@ -0,0 +1,223 @@
+package risk
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/user-service/internal/clients"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/timezone"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EscalateAfter is how long an alert may stay unacknowledged before every psychologist is paged:
+// RISK_ALERT_ESCALATE_MINUTES (default 30)
+func EscalateAfter() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("RISK_ALERT_ESCALATE_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Raise stores an alert for the signal and notifies the on-duty psychologist. It returns nil
+// without an error when the same rule already has an open alert for the student or is cooling down.
+// A failed notification is logged and retried by the risk alert worker.
+func Raise(db *gorm.DB, sig Signal) (*models.RiskAlert, error) {
+	if sig.Cooldown > 0 {
+		var recent int64
+		err := db.Model(&models.RiskAlert{}).
+			Where("student_id = ? AND rule = ? AND created_at > ?", sig.StudentID, sig.Rule, time.Now().Add(-sig.Cooldown)).
+			Count(&recent).Error
+		if err != nil {
+			return nil, err
+		}
+		if recent > 0 {
+			return nil, nil
+		}
+	}
+
+	alert := models.RiskAlert{
+		ID:          uuid.NewString(),
+		StudentID:   sig.StudentID,
+		Source:      sig.Source,
+		Rule:        sig.Rule,
+		Description: sig.Description,
+		Severity:    sig.Severity,
+		Detail:      sig.Detail,
+		SlotID:      sig.SlotID,
+		Status:      models.RiskAlertOpen,
+	}
+
+	created := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// idx_risk_alert_open turns a second open alert for the same rule into a no-op
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return addEvent(tx, alert.ID, models.RiskEventRaised, "", sig.Detail)
+	})
+	if err != nil || !created {
+		return nil, err
+	}
+
+	log.Printf("Risk alert %s raised for student %s (%s)", alert.ID, alert.StudentID, alert.Rule)
+	if err := Notify(db, &alert); err != nil {
+		log.Printf("Failed to notify about risk alert %s, will retry: %v", alert.ID, err)
+	}
+	return &alert, nil
+}
+
+// Notify pages the psychologists on duty now, or every psychologist when nobody is on duty
+func Notify(db *gorm.DB, alert *models.RiskAlert) error {
+	recipients, err := OnDuty(db, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		log.Printf("Nobody is on duty for risk alert %s, paging every psychologist", alert.ID)
+		if recipients, err = allPsychologists(db); err != nil {
+			return err
+		}
+	}
+	return page(db, alert, recipients, "notified_at", models.RiskEventNotified)
+}
+
+// Escalate pages every psychologist about an alert that nobody acknowledged in time
+func Escalate(db *gorm.DB, alert *models.RiskAlert) error {
+	recipients, err := allPsychologists(db)
+	if err != nil {
+		return err
+	}
+	return page(db, alert, recipients, "escalated_at", models.RiskEventEscalated)
+}
+
+// OnDuty returns the psychologists with a duty shift covering the given time
+func OnDuty(db *gorm.DB, at time.Time) ([]models.UserProfile, error) {
+	var profiles []models.UserProfile
+	err := db.Where("role = ? AND id IN (?)", "psychologist",
+		db.Model(&models.DutyShift{}).Select("psychologist_id").Where("starts_at <= ? AND ends_at > ?", at, at),
+	).Find(&profiles).Error
+	return profiles, err
+}
+
+func allPsychologists(db *gorm.DB) ([]models.UserProfile, error) {
+	var profiles []models.UserProfile
+	err := db.Where("role = ?", "psychologist").Find(&profiles).Error
+	return profiles, err
+}
+
+// page sends the alert to every recipient it can, then stamps the given column and records the
+// trail entry. A failed send does not stop the others; the alert counts as delivered as soon as
+// one psychologist was reached, so a retry never pages the same people twice.
+func page(db *gorm.DB, alert *models.RiskAlert, recipients []models.UserProfile, stamp, action string) error {
+	if len(recipients) == 0 {
+		return fmt.Errorf("no psychologist to notify")
+	}
+
+	var student models.UserProfile
+	if err := db.First(&student, "id = ?", alert.StudentID).Error; err != nil {
+		return fmt.Errorf("student %s: %w", alert.StudentID, err)
+	}
+
+	var sent, failed []string
+	var lastErr error
+	for _, p := range recipients {
+		if p.Email == "" {
+			continue
+		}
+		msg := clients.NotificationMessage{
+			Type:    "urgent_risk_alert",
+			ToEmail: p.Email,
+			Data: map[string]string{
+				"alert_id":      alert.ID,
+				"alert_url":     alertURL(alert.ID),
+				"student_name":  student.FullName,
+				"student_email": student.Email,
+				"student_phone": student.Phone,
+				"description":   alert.Description,
+				"severity":      alert.Severity,
+				"source":        alert.Source,
+				"detail":        alert.Detail,
+				"raised_at":     alert.CreatedAt.In(timezone.Location(p.TimeZone)).Format("Monday, 02 Jan 2006 at 15:04"),
+				"escalated":     strconv.FormatBool(action == models.RiskEventEscalated),
+			},
+		}
+		if err := clients.PublishNotification(msg); err != nil {
+			log.Printf("Failed to page %s about risk alert %s: %v", p.ID, alert.ID, err)
+			failed = append(failed, p.FullName)
+			lastErr = err
+			continue
+		}
+		sent = append(sent, p.FullName)
+	}
+	if len(sent) == 0 {
+		if lastErr != nil {
+			return lastErr
+		}
+		return fmt.Errorf("no psychologist with an email to notify")
+	}
+
+	note := "Sent to " + strings.Join(sent, ", ")
+	if len(failed) > 0 {
+		note += ". Failed for " + strings.Join(failed, ", ")
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RiskAlert{}).Where("id = ?", alert.ID).Update(stamp, time.Now()).Error; err != nil {
+			return err
+		}
+		return addEvent(tx, alert.ID, action, "", note)
+	})
+}
+
+// Acknowledge closes an open alert on behalf of the psychologist. It returns false when the
+// alert was not open.
+func Acknowledge(db *gorm.DB, alertID, psychologistID, note string) (bool, error) {
+	acknowledged := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.RiskAlert{}).
+			Where("id = ? AND status = ?", alertID, models.RiskAlertOpen).
+			Updates(map[string]interface{}{
+				"status":          models.RiskAlertAcknowledged,
+				"acknowledged_by": psychologistID,
+				"acknowledged_at": now,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		acknowledged = true
+		return addEvent(tx, alertID, models.RiskEventAcknowledged, psychologistID, note)
+	})
+	return acknowledged, err
+}
+
+func addEvent(tx *gorm.DB, alertID, action, actorID, note string) error {
+	return tx.Create(&models.RiskAlertEvent{
+		ID:      uuid.NewString(),
+		AlertID: alertID,
+		Action:  action,
+		ActorID: actorID,
+		Note:    note,
+	}).Error
+}
+
+// alertURL is the frontend page where a psychologist reviews and acknowledges the alert
+func alertURL(alertID string) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "http://localhost:5173"
+	}
+	return strings.TrimRight(base, "/") + "/risk-alerts/" + alertID
+}
@@ -0,0 +1,140 @@
+// Package risk raises alerts when a student's mood logs or questionnaire answers suggest they
+// need help now, and pages the on-duty psychologist until someone acknowledges the alert.
+package risk
+
+import (
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// Severities, in increasing order of urgency
+const (
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// MoodRule triggers when the student's last entry of each of the last Days days, today included,
+// is one of Moods
+type MoodRule struct {
+	Name        string
+	Description string
+	Severity    string
+	Moods       []string
+	Days        int
+}
+
+// MoodRules are checked every time a student logs a mood
+var MoodRules = []MoodRule{
+	{
+		Name:        "distress_streak",
+		Description: "Reported feeling stressed or anxious 3 days in a row",
+		Severity:    SeverityHigh,
+		Moods:       []string{"Stressed", "Anxiously"},
+		Days:        3,
+	},
+	{
+		Name:        "low_mood_streak",
+		Description: "Reported a low mood every day for a week",
+		Severity:    SeverityHigh,
+		Moods:       []string{"Sad", "Anxiously", "Stressed"},
+		Days:        7,
+	},
+}
+
+// Signal is a rule that triggered for a student
+type Signal struct {
+	StudentID   string
+	Source      string
+	Rule        string
+	Description string
+	Severity    string
+	Detail      string
+	SlotID      string
+	// Cooldown stops the same rule from raising a new alert this soon after the last one,
+	// so a streak that goes on after an acknowledgement does not page again every day
+	Cooldown time.Duration
+}
+
+// EvaluateMood checks the student's recent mood logs, up to and including the day of at.
+// at should be in the student's time zone, like the logs' dates.
+func EvaluateMood(db *gorm.DB, studentID string, at time.Time) ([]Signal, error) {
+	longest := 0
+	for _, rule := range MoodRules {
+		if rule.Days > longest {
+			longest = rule.Days
+		}
+	}
+	if longest == 0 {
+		return nil, nil
+	}
+
+	var logs []models.MoodLog
+	err := db.Where("user_id = ? AND date > ?", studentID, at.AddDate(0, 0, -longest).Format("2006-01-02")).
+		Order("logged_at asc").
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// The day's latest entry decides how the day counts
+	byDate := make(map[string]string, len(logs))
+	for _, l := range logs {
+		byDate[l.Day()] = l.Mood
+	}
+
+	var signals []Signal
+	for _, rule := range MoodRules {
+		if !streak(rule, byDate, at) {
+			continue
+		}
+		signals = append(signals, Signal{
+			StudentID:   studentID,
+			Source:      models.RiskSourceMood,
+			Rule:        rule.Name,
+			Description: rule.Description,
+			Severity:    rule.Severity,
+			Cooldown:    time.Duration(rule.Days) * 24 * time.Hour,
+		})
+	}
+	return signals, nil
+}
+
+// CheckMood evaluates the mood rules after the student logged a mood and raises what triggered.
+// Failures are logged: a mood entry is never rejected because the check failed.
+func CheckMood(db *gorm.DB, studentID string, at time.Time) {
+	signals, err := EvaluateMood(db, studentID, at)
+	if err != nil {
+		log.Printf("Failed to check mood risk rules for %s: %v", studentID, err)
+		return
+	}
+	for _, sig := range signals {
+		if _, err := Raise(db, sig); err != nil {
+			log.Printf("Failed to raise risk alert for %s: %v", studentID, err)
+		}
+	}
+}
+
+func streak(rule MoodRule, byDate map[string]string, at time.Time) bool {
+	if rule.Days <= 0 {
+		return false
+	}
+	for i := 0; i < rule.Days; i++ {
+		last, ok := byDate[at.AddDate(0, 0, -i).Format("2006-01-02")]
+		if !ok || !contains(rule.Moods, last) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
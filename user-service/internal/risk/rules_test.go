@@ -0,0 +1,141 @@
+package risk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var day = time.Date(2026, 3, 10, 21, 0, 0, 0, time.UTC)
+
+// moods turns a list of daily moods ending on day into a date -> mood map, "" meaning no entry
+func moods(list ...string) map[string]string {
+	byDate := make(map[string]string)
+	for i, m := range list {
+		if m == "" {
+			continue
+		}
+		byDate[day.AddDate(0, 0, i-len(list)+1).Format("2006-01-02")] = m
+	}
+	return byDate
+}
+
+func TestStreak(t *testing.T) {
+	distress := MoodRules[0]
+	lowMood := MoodRules[1]
+
+	tests := []struct {
+		name   string
+		rule   MoodRule
+		byDate map[string]string
+		want   bool
+	}{
+		{"three distressed days", distress, moods("Stressed", "Anxiously", "Stressed"), true},
+		{"only two days", distress, moods("Stressed", "Stressed"), false},
+		{"a good day in between", distress, moods("Stressed", "Nice", "Stressed"), false},
+		{"a day without an entry", distress, moods("Stressed", "", "Stressed"), false},
+		{"streak ended yesterday", distress, moods("Stressed", "Stressed", "Stressed", "Nice"), false},
+		{"sad does not count as distress", distress, moods("Sad", "Sad", "Sad"), false},
+		{"a low week", lowMood, moods("Sad", "Sad", "Stressed", "Anxiously", "Sad", "Sad", "Sad"), true},
+		{"six low days", lowMood, moods("Sad", "Sad", "Sad", "Sad", "Sad", "Sad"), false},
+		{"rule without days", MoodRule{Moods: []string{"Sad"}}, moods("Sad"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, streak(tt.rule, tt.byDate, day))
+		})
+	}
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.MoodLog{}))
+	return db
+}
+
+func logMood(t *testing.T, db *gorm.DB, daysAgo int, hour int, mood string) {
+	t.Helper()
+	at := time.Date(day.Year(), day.Month(), day.Day()-daysAgo, hour, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Create(&models.MoodLog{
+		ID:       fmt.Sprintf("log-%d-%d", daysAgo, hour),
+		UserID:   "student-1",
+		LoggedAt: at,
+		Date:     at.Format("2006-01-02"),
+		Mood:     mood,
+		Score:    1,
+	}).Error)
+}
+
+func rulesOf(signals []Signal) []string {
+	var names []string
+	for _, s := range signals {
+		names = append(names, s.Rule)
+	}
+	return names
+}
+
+func TestEvaluateMoodDistressStreak(t *testing.T) {
+	db := setupTestDB(t)
+	logMood(t, db, 2, 9, "Stressed")
+	logMood(t, db, 1, 9, "Anxiously")
+	logMood(t, db, 0, 9, "Stressed")
+
+	signals, err := EvaluateMood(db, "student-1", day)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"distress_streak"}, rulesOf(signals))
+	assert.Equal(t, SeverityHigh, signals[0].Severity)
+	assert.Equal(t, models.RiskSourceMood, signals[0].Source)
+	assert.Equal(t, 3*24*time.Hour, signals[0].Cooldown)
+}
+
+func TestEvaluateMoodLatestEntryOfTheDayCounts(t *testing.T) {
+	db := setupTestDB(t)
+	logMood(t, db, 2, 9, "Stressed")
+	logMood(t, db, 1, 9, "Stressed")
+	logMood(t, db, 1, 20, "Nice") // felt better by the evening
+	logMood(t, db, 0, 9, "Stressed")
+
+	signals, err := EvaluateMood(db, "student-1", day)
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+
+	// And the other way round
+	logMood(t, db, 0, 8, "Amazing")
+	logMood(t, db, 1, 21, "Anxiously")
+	signals, err = EvaluateMood(db, "student-1", day)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"distress_streak"}, rulesOf(signals))
+}
+
+func TestEvaluateMoodLowWeek(t *testing.T) {
+	db := setupTestDB(t)
+	for i := 6; i >= 0; i-- {
+		logMood(t, db, i, 12, "Stressed")
+	}
+	// Old entries outside the window do not matter
+	logMood(t, db, 10, 12, "Amazing")
+
+	signals, err := EvaluateMood(db, "student-1", day)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"distress_streak", "low_mood_streak"}, rulesOf(signals))
+}
+
+func TestEvaluateMoodOtherStudent(t *testing.T) {
+	db := setupTestDB(t)
+	for i := 2; i >= 0; i-- {
+		logMood(t, db, i, 12, "Stressed")
+	}
+
+	signals, err := EvaluateMood(db, "student-2", day)
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+}
@@ -0,0 +1,164 @@
+// Package telegramlink issues and redeems the one-time deep-link tokens that connect a
+// Telegram chat to a profile.
+//
+// A token is a random nonce followed by an HMAC-SHA256 of the nonce, both base64url encoded,
+// so it fits Telegram's 64-character /start parameter. The signature lets the bot reject
+// forged tokens without a database lookup; the stored nonce hash makes a token single-use
+// and binds it to the user who requested it.
+package telegramlink
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TTL is how long a token can be redeemed
+const TTL = 10 * time.Minute
+
+const (
+	nonceSize = 16
+	macSize   = 16
+)
+
+var (
+	ErrInvalid = errors.New("invalid link token")
+	ErrExpired = errors.New("link token expired or already used")
+)
+
+// Strict rejects the non-canonical spellings a token would otherwise have
+var encoding = base64.RawURLEncoding.Strict()
+
+// secret is TELEGRAM_LINK_SECRET. It is deliberately separate from the bot token, which is
+// shared with notification-service and Telegram; linking is disabled while it is not set.
+func secret() []byte {
+	return []byte(os.Getenv("TELEGRAM_LINK_SECRET"))
+}
+
+func sign(nonce []byte) []byte {
+	mac := hmac.New(sha256.New, secret())
+	mac.Write([]byte("telegram-link:"))
+	mac.Write(nonce)
+	return mac.Sum(nil)[:macSize]
+}
+
+func hashNonce(nonce []byte) string {
+	sum := sha256.Sum256(nonce)
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a token for the user. Tokens issued earlier and not redeemed yet stop working.
+func Issue(db *gorm.DB, userID string) (string, time.Time, error) {
+	if len(secret()) == 0 {
+		return "", time.Time{}, errors.New("TELEGRAM_LINK_SECRET is not set")
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, err
+	}
+
+	record := models.TelegramLinkToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		NonceHash: hashNonce(nonce),
+		ExpiresAt: time.Now().Add(TTL),
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", userID).Delete(&models.TelegramLinkToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return encoding.EncodeToString(nonce) + encoding.EncodeToString(sign(nonce)), record.ExpiresAt, nil
+}
+
+// Redeem checks the token and marks it used, returning the user it was issued to
+func Redeem(db *gorm.DB, token string) (string, error) {
+	nonceLen := encoding.EncodedLen(nonceSize)
+	if len(token) != nonceLen+encoding.EncodedLen(macSize) {
+		return "", ErrInvalid
+	}
+	nonce, err := encoding.DecodeString(token[:nonceLen])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	mac, err := encoding.DecodeString(token[nonceLen:])
+	if err != nil || len(secret()) == 0 || !hmac.Equal(mac, sign(nonce)) {
+		return "", ErrInvalid
+	}
+
+	var record models.TelegramLinkToken
+	if err := db.First(&record, "nonce_hash = ?", hashNonce(nonce)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrExpired
+		}
+		return "", err
+	}
+
+	// The conditional update makes a concurrent second redemption a no-op
+	now := time.Now()
+	res := db.Model(&models.TelegramLinkToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", record.ID, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return "", res.Error
+	}
+	if res.RowsAffected == 0 {
+		return "", ErrExpired
+	}
+	return record.UserID, nil
+}
+
+// URL is the t.me deep link that opens the bot with the token, or "" when TELEGRAM_BOT_USERNAME is not set
+func URL(token string) string {
+	username := os.Getenv("TELEGRAM_BOT_USERNAME")
+	if username == "" {
+		return ""
+	}
+	return "https://t.me/" + username + "?start=" + token
+}
+
+// Link connects the chat to the user. A chat belongs to one profile at a time, so it is taken
+// away from any profile it was linked to before.
+func Link(db *gorm.DB, userID, chatID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.UserProfile{}).
+			Where("telegram_chat_id = ? AND id <> ?", chatID, userID).
+			Update("telegram_chat_id", "").Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.UserProfile{}).Where("id = ?", userID).Update("telegram_chat_id", chatID).Error
+	})
+}
+
+// Unlink disconnects the user's chat and turns off the daily mood check-in that needs it.
+// It reports whether a chat was linked.
+func Unlink(db *gorm.DB, userID string) (bool, error) {
+	var unlinked bool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.UserProfile{}).
+			Where("id = ? AND telegram_chat_id <> ''", userID).
+			Update("telegram_chat_id", "")
+		if res.Error != nil {
+			return res.Error
+		}
+		unlinked = res.RowsAffected > 0
+		return tx.Model(&models.MoodReminder{}).Where("user_id = ?", userID).Update("enabled", false).Error
+	})
+	return unlinked, err
+}
@@ -0,0 +1,116 @@
+package telegramlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("TELEGRAM_LINK_SECRET", "test-link-secret")
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.TelegramLinkToken{}))
+	return db
+}
+
+func TestRedeemReturnsUser(t *testing.T) {
+	db := setupTestDB(t)
+
+	token, expiresAt, err := Issue(db, "user-1")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(token), 64, "must fit Telegram's /start parameter")
+	assert.WithinDuration(t, time.Now().Add(TTL), expiresAt, time.Minute)
+
+	userID, err := Redeem(db, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+}
+
+func TestRedeemRejectsReuse(t *testing.T) {
+	db := setupTestDB(t)
+
+	token, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+
+	_, err = Redeem(db, token)
+	require.NoError(t, err)
+	_, err = Redeem(db, token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestRedeemRejectsExpired(t *testing.T) {
+	db := setupTestDB(t)
+
+	token, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+	db.Model(&models.TelegramLinkToken{}).Where("user_id = ?", "user-1").Update("expires_at", time.Now().Add(-time.Second))
+
+	_, err = Redeem(db, token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestIssueReplacesUnusedToken(t *testing.T) {
+	db := setupTestDB(t)
+
+	first, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+	second, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+
+	_, err = Redeem(db, first)
+	assert.ErrorIs(t, err, ErrExpired)
+	userID, err := Redeem(db, second)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+}
+
+func TestRedeemRejectsTamperedMAC(t *testing.T) {
+	db := setupTestDB(t)
+
+	token, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+
+	// Change one character of the MAC, keeping the token decodable
+	last := token[len(token)-2]
+	replacement := byte('A')
+	if last == 'A' {
+		replacement = 'B'
+	}
+	tampered := token[:len(token)-2] + string(replacement) + token[len(token)-1:]
+	_, err = Redeem(db, tampered)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// A token signed with another secret is rejected as well
+	t.Setenv("TELEGRAM_LINK_SECRET", "another-secret")
+	_, err = Redeem(db, token)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	for _, bad := range []string{"", "short", token + "A"} {
+		_, err = Redeem(db, bad)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+}
+
+func TestLinkSecretIsRequired(t *testing.T) {
+	db := setupTestDB(t)
+
+	token, _, err := Issue(db, "user-1")
+	require.NoError(t, err)
+
+	// The bot token is never used in its place
+	t.Setenv("TELEGRAM_LINK_SECRET", "")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:bot-token")
+
+	_, _, err = Issue(db, "user-2")
+	assert.Error(t, err)
+	_, err = Redeem(db, token)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,31 @@
+// Package timezone resolves the IANA time zones stored on user profiles.
+package timezone
+
+import (
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// Default is used for profiles without a valid time zone
+const Default = "Asia/Almaty"
+
+// Location loads the IANA time zone, falling back to Default
+func Location(name string) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	loc, err := time.LoadLocation(Default)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ForUser returns the time zone from the user's profile
+func ForUser(db *gorm.DB, userID string) *time.Location {
+	var profile models.UserProfile
+	db.Select("time_zone").First(&profile, "id = ?", userID)
+	return Location(profile.TimeZone)
+}
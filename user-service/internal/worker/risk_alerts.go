@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/risk"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StartRiskAlertWorker retries alerts that could not be delivered and escalates the ones
+// nobody acknowledged within risk.EscalateAfter
+func StartRiskAlertWorker() {
+	ticker := time.NewTicker(1 * time.Minute)
+
+	go func() {
+		for range ticker.C {
+			retryRiskAlerts()
+			escalateRiskAlerts()
+		}
+	}()
+}
+
+// claimRiskAlerts locks the alerts matching the query and hands them to fn in the same
+// transaction. SKIP LOCKED lets several user-service replicas run the worker without paging
+// anyone twice; fn must use the transaction it is given.
+func claimRiskAlerts(query func(tx *gorm.DB) *gorm.DB, fn func(tx *gorm.DB, alert *models.RiskAlert)) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var alerts []models.RiskAlert
+		if err := query(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})).Find(&alerts).Error; err != nil {
+			return err
+		}
+		for i := range alerts {
+			fn(tx, &alerts[i])
+		}
+		return nil
+	})
+}
+
+func retryRiskAlerts() {
+	err := claimRiskAlerts(func(tx *gorm.DB) *gorm.DB {
+		// Give Raise time to deliver the alert itself before retrying
+		return tx.Where("status = ? AND notified_at IS NULL AND created_at <= ?", models.RiskAlertOpen, time.Now().Add(-time.Minute))
+	}, func(tx *gorm.DB, alert *models.RiskAlert) {
+		if err := risk.Notify(tx, alert); err != nil {
+			log.Printf("[Worker Error] Failed to deliver risk alert %s: %v", alert.ID, err)
+			return
+		}
+		log.Printf("[Worker] Delivered risk alert %s", alert.ID)
+	})
+	if err != nil {
+		log.Printf("[Worker Error] Failed to retry undelivered risk alerts: %v", err)
+	}
+}
+
+func escalateRiskAlerts() {
+	err := claimRiskAlerts(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("status = ? AND escalated_at IS NULL AND notified_at <= ?", models.RiskAlertOpen, time.Now().Add(-risk.EscalateAfter()))
+	}, func(tx *gorm.DB, alert *models.RiskAlert) {
+		if err := risk.Escalate(tx, alert); err != nil {
+			log.Printf("[Worker Error] Failed to escalate risk alert %s: %v", alert.ID, err)
+			return
+		}
+		log.Printf("[Worker] Escalated unacknowledged risk alert %s", alert.ID)
+	})
+	if err != nil {
+		log.Printf("[Worker Error] Failed to escalate unacknowledged risk alerts: %v", err)
+	}
+}
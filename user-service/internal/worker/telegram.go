@@ -1,22 +1,52 @@
 package worker
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pokonti/psychologist-backend/proto/booking"
 	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/clients"
 	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/pokonti/psychologist-backend/user-service/internal/mood"
+	"github.com/pokonti/psychologist-backend/user-service/internal/risk"
+	"github.com/pokonti/psychologist-backend/user-service/internal/telegramlink"
+	"github.com/pokonti/psychologist-backend/user-service/internal/timezone"
 )
 
-func StartTelegramBot() {
+// sender is the part of the Bot API the handlers use
+type sender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// moodCallbackPrefix marks the data of the mood buttons, e.g. "mood:Nice"
+const moodCallbackPrefix = "mood:"
+
+var moodEmoji = map[string]string{
+	"Amazing":   "🤩",
+	"Nice":      "🙂",
+	"Not bad":   "😐",
+	"Sad":       "😢",
+	"Anxiously": "😟",
+	"Stressed":  "😫",
+}
+
+func StartTelegramBot(bookingClient booking.BookingServiceClient) {
 	token := os.Getenv("TELEGRAM_BOT_TOKEN")
 	if token == "" {
 		log.Println("TELEGRAM_BOT_TOKEN not set. Telegram linking is disabled.")
 		return
 	}
+	if os.Getenv("TELEGRAM_LINK_SECRET") == "" {
+		log.Println("TELEGRAM_LINK_SECRET not set. Link tokens cannot be issued or redeemed.")
+	}
 
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
@@ -26,63 +56,279 @@ func StartTelegramBot() {
 
 	log.Printf("Authorized on Telegram account @%s", bot.Self.UserName)
 
+	startMoodReminders(bot)
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := bot.GetUpdatesChan(u)
 
 	for update := range updates {
-		if update.Message == nil {
+		if update.CallbackQuery != nil {
+			handleMoodButton(bot, update.CallbackQuery)
 			continue
 		}
 
-		if update.Message.IsCommand() {
-			command := update.Message.Command()
-			args := update.Message.CommandArguments()
+		if update.Message == nil || !update.Message.IsCommand() {
+			continue
+		}
 
-			if command == "start" {
-				chatID := update.Message.Chat.ID
-				chatIDStr := fmt.Sprintf("%d", chatID)
+		chatID := update.Message.Chat.ID
+		switch update.Message.Command() {
+		case "start":
+			handleStart(bot, chatID, update.Message.CommandArguments())
+		case "mood":
+			if profile, ok := linkedProfile(bot, chatID); ok {
+				msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("How are you feeling, %s?", profile.FullName))
+				msg.ReplyMarkup = moodKeyboard()
+				bot.Send(msg)
+			}
+		case "appointments":
+			if profile, ok := linkedProfile(bot, chatID); ok {
+				handleAppointments(bot, bookingClient, chatID, profile)
+			}
+		case "stop":
+			if profile, ok := linkedProfile(bot, chatID); ok {
+				handleStop(bot, chatID, profile)
+			}
+		case "unlink":
+			if profile, ok := linkedProfile(bot, chatID); ok {
+				handleUnlink(bot, chatID, profile)
+			}
+		}
+	}
+}
 
-				var existingProfile models.UserProfile
-				err := config.DB.First(&existingProfile, "telegram_chat_id = ?", chatIDStr).Error
+func handleStart(bot sender, chatID int64, args string) {
+	chatIDStr := fmt.Sprintf("%d", chatID)
 
-				if err == nil {
-					reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("You are already linked to KBTU Care, %s!", existingProfile.FullName))
-					bot.Send(reply)
-					continue
-				}
+	token := strings.TrimSpace(args)
+	if token == "" {
+		var existingProfile models.UserProfile
+		if err := config.DB.First(&existingProfile, "telegram_chat_id = ?", chatIDStr).Error; err == nil {
+			reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("You are already linked to KBTU Care, %s!", existingProfile.FullName))
+			bot.Send(reply)
+			return
+		}
 
-				if args == "" {
-					reply := tgbotapi.NewMessage(chatID, "Welcome to KBTU Care! Please open your profile at https:// and click the 'Connect Telegram' button.")
-					bot.Send(reply)
-					continue
-				}
+		reply := tgbotapi.NewMessage(chatID, "Welcome to KBTU Care! Please open your profile and click the 'Connect Telegram' button.")
+		bot.Send(reply)
+		return
+	}
 
-				studentID := strings.TrimSpace(args)
+	userID, err := telegramlink.Redeem(config.DB, token)
+	if err != nil {
+		if !errors.Is(err, telegramlink.ErrInvalid) && !errors.Is(err, telegramlink.ErrExpired) {
+			log.Printf("[Telegram Error] Failed to redeem link token: %v", err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Database error. Please try again later."))
+			return
+		}
+		bot.Send(tgbotapi.NewMessage(chatID, "This link is invalid or has expired. Please click 'Connect Telegram' in your profile again."))
+		return
+	}
 
-				var profile models.UserProfile
-				if err := config.DB.First(&profile, "id = ?", studentID).Error; err != nil {
-					reply := tgbotapi.NewMessage(chatID, "User not found. Please check your ID.")
-					bot.Send(reply)
-					continue
-				}
+	var profile models.UserProfile
+	if err := config.DB.First(&profile, "id = ?", userID).Error; err != nil {
+		reply := tgbotapi.NewMessage(chatID, "User not found.")
+		bot.Send(reply)
+		return
+	}
+
+	if err := telegramlink.Link(config.DB, profile.ID, chatIDStr); err != nil {
+		reply := tgbotapi.NewMessage(chatID, "Database error. Please try again later.")
+		bot.Send(reply)
+		return
+	}
+
+	successMsg := fmt.Sprintf("Welcome, %s!\nYour Telegram account is now linked to KBTU Care. You will receive appointment reminders here.\n\n/mood - log how you feel\n/appointments - your upcoming sessions\n/stop - turn off the daily check-in\n/unlink - disconnect this chat", profile.FullName)
+	reply := tgbotapi.NewMessage(chatID, successMsg)
+	bot.Send(reply)
 
-				// Update their Telegram Chat ID
-				profile.TelegramChatID = chatIDStr
+	log.Printf("Linked Telegram Chat ID %s to User %s", chatIDStr, profile.ID)
+}
 
-				if err := config.DB.Save(&profile).Error; err != nil {
-					reply := tgbotapi.NewMessage(chatID, "Database error. Please try again later.")
-					bot.Send(reply)
-					continue
-				}
+func handleUnlink(bot sender, chatID int64, profile models.UserProfile) {
+	if _, err := telegramlink.Unlink(config.DB, profile.ID); err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Database error. Please try again later."))
+		return
+	}
+	bot.Send(tgbotapi.NewMessage(chatID, "This chat is no longer linked to KBTU Care. You will not receive messages here anymore."))
+	log.Printf("Unlinked Telegram Chat ID %d from User %s", chatID, profile.ID)
+}
 
-				successMsg := fmt.Sprintf("Welcome, %s!\nYour Telegram account is now linked to KBTU Care. You will receive appointment reminders here.", profile.FullName)
-				reply := tgbotapi.NewMessage(chatID, successMsg)
-				bot.Send(reply)
+// linkedProfile finds the profile linked to the chat and asks the user to link one if there is none
+func linkedProfile(bot sender, chatID int64) (models.UserProfile, bool) {
+	var profile models.UserProfile
+	if err := config.DB.First(&profile, "telegram_chat_id = ?", fmt.Sprintf("%d", chatID)).Error; err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "This chat is not linked to KBTU Care yet. Please open your profile and click the 'Connect Telegram' button."))
+		return profile, false
+	}
+	return profile, true
+}
 
-				log.Printf("Linked Telegram Chat ID %s to User %s", chatIDStr, profile.ID)
+// moodKeyboard has one button per mood, two rows of three
+func moodKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, m := range mood.Moods {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(moodEmoji[m]+" "+m, moodCallbackPrefix+m))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// parseMoodCallback returns the mood a button carries, e.g. "Nice" for "mood:Nice"
+func parseMoodCallback(data string) (string, bool) {
+	selected, ok := strings.CutPrefix(data, moodCallbackPrefix)
+	if !ok || mood.Score(selected) == 0 {
+		return "", false
+	}
+	return selected, true
+}
+
+// handleMoodButton logs the tapped mood the same way POST /users/me/mood does
+func handleMoodButton(bot sender, query *tgbotapi.CallbackQuery) {
+	if !strings.HasPrefix(query.Data, moodCallbackPrefix) || query.Message == nil {
+		bot.Request(tgbotapi.NewCallback(query.ID, ""))
+		return
+	}
+	chatID := query.Message.Chat.ID
+	selected, ok := parseMoodCallback(query.Data)
+	if !ok {
+		bot.Request(tgbotapi.NewCallback(query.ID, "Unknown mood"))
+		return
+	}
+
+	var profile models.UserProfile
+	if err := config.DB.First(&profile, "telegram_chat_id = ?", fmt.Sprintf("%d", chatID)).Error; err != nil {
+		bot.Request(tgbotapi.NewCallback(query.ID, "This chat is not linked to KBTU Care"))
+		return
+	}
+
+	now := time.Now()
+	if _, err := mood.Record(config.DB, profile.ID, models.LogMoodInput{Mood: selected}, now); err != nil {
+		log.Printf("[Telegram Error] Failed to log mood for %s: %v", profile.ID, err)
+		bot.Request(tgbotapi.NewCallback(query.ID, "Could not save your mood. Please try again."))
+		return
+	}
+	risk.CheckMood(config.DB, profile.ID, now.In(timezone.Location(profile.TimeZone)))
+
+	bot.Request(tgbotapi.NewCallback(query.ID, "Saved"))
+	// Drop the buttons so the same message cannot log twice
+	edit := tgbotapi.NewEditMessageText(chatID, query.Message.MessageID,
+		fmt.Sprintf("Logged: %s %s. Thank you for checking in!", moodEmoji[selected], selected))
+	bot.Send(edit)
+}
+
+func handleAppointments(bot sender, bookingClient booking.BookingServiceClient, chatID int64, profile models.UserProfile) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	appointments, err := clients.UpcomingAppointments(ctx, bookingClient, profile.ID, profile.Role)
+	if err != nil {
+		log.Printf("[Telegram Error] Failed to load appointments for %s: %v", profile.ID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Could not load your appointments. Please try again later."))
+		return
+	}
+	if len(appointments) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "You have no upcoming appointments."))
+		return
+	}
+
+	loc := timezone.Location(profile.TimeZone)
+	var b strings.Builder
+	b.WriteString("Your upcoming appointments:\n")
+	for i, a := range appointments {
+		if i == 5 {
+			fmt.Fprintf(&b, "…and %d more in KBTU Care", len(appointments)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n%s (%s)", a.StartTime.In(loc).Format("Mon 02 Jan, 15:04"), a.BookingType)
+		if a.With != "" {
+			fmt.Fprintf(&b, " with %s", a.With)
+		}
+	}
+	bot.Send(tgbotapi.NewMessage(chatID, b.String()))
+}
+
+func handleStop(bot sender, chatID int64, profile models.UserProfile) {
+	res := config.DB.Model(&models.MoodReminder{}).
+		Where("user_id = ? AND enabled", profile.ID).
+		Update("enabled", false)
+	if res.Error != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Database error. Please try again later."))
+		return
+	}
+	if res.RowsAffected == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "The daily check-in is already off."))
+		return
+	}
+	bot.Send(tgbotapi.NewMessage(chatID, "The daily check-in is off. You can turn it on again in your profile."))
+}
+
+// startMoodReminders sends the daily check-in once the chosen time has come in the user's time zone
+func startMoodReminders(bot sender) {
+	ticker := time.NewTicker(1 * time.Minute)
+
+	go func() {
+		for range ticker.C {
+			sendMoodReminders(bot)
+		}
+	}()
+}
+
+func sendMoodReminders(bot sender) {
+	var reminders []models.MoodReminder
+	if err := config.DB.Where("enabled").Find(&reminders).Error; err != nil {
+		log.Printf("[Worker Error] Failed to load mood reminders: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		var profile models.UserProfile
+		if err := config.DB.First(&profile, "id = ?", reminder.UserID).Error; err != nil || profile.TelegramChatID == "" {
+			continue
+		}
+
+		now := time.Now().In(timezone.Location(profile.TimeZone))
+		today := now.Format("2006-01-02")
+		if now.Format("15:04") < reminder.Time || reminder.LastSentOn == today {
+			continue
+		}
+
+		// Nothing to ask if they already logged today
+		var logged int64
+		config.DB.Model(&models.MoodLog{}).Where("user_id = ? AND date = ?", profile.ID, today).Count(&logged)
+		if logged == 0 {
+			if err := sendMoodCheckIn(bot, profile); err != nil {
+				// The day stays open, so the next tick tries again
+				log.Printf("[Worker Error] Failed to send mood check-in to %s: %v", profile.ID, err)
+				continue
 			}
+			log.Printf("[Worker] Sent mood check-in to %s", profile.ID)
 		}
+
+		if err := config.DB.Model(&models.MoodReminder{}).
+			Where("user_id = ?", reminder.UserID).
+			Update("last_sent_on", today).Error; err != nil {
+			log.Printf("[Worker Error] Failed to mark mood check-in for %s: %v", profile.ID, err)
+		}
+	}
+}
+
+func sendMoodCheckIn(bot sender, profile models.UserProfile) error {
+	var chatID int64
+	if _, err := fmt.Sscan(profile.TelegramChatID, &chatID); err != nil {
+		return err
 	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Hi %s! How are you feeling today?", profile.FullName))
+	msg.ReplyMarkup = moodKeyboard()
+	_, err := bot.Send(msg)
+	return err
 }
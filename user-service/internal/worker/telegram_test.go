@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pokonti/psychologist-backend/user-service/config"
+	"github.com/pokonti/psychologist-backend/user-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeBot records what would be sent to Telegram
+type fakeBot struct {
+	sent      []tgbotapi.Chattable
+	callbacks []tgbotapi.CallbackConfig
+	sendErr   error
+}
+
+func (b *fakeBot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	if b.sendErr != nil {
+		return tgbotapi.Message{}, b.sendErr
+	}
+	b.sent = append(b.sent, c)
+	return tgbotapi.Message{}, nil
+}
+
+func (b *fakeBot) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if cb, ok := c.(tgbotapi.CallbackConfig); ok {
+		b.callbacks = append(b.callbacks, cb)
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.UserProfile{}, &models.MoodLog{}, &models.MoodReminder{}))
+	config.DB = db
+
+	require.NoError(t, db.Create(&models.UserProfile{
+		ID:             "user-1",
+		Email:          "student@test.com",
+		Role:           "student",
+		FullName:       "Aruzhan",
+		TelegramChatID: "12345",
+	}).Error)
+}
+
+func moodTap(chatID int64, data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "query-1",
+		Data:    data,
+		Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: chatID}},
+	}
+}
+
+func TestParseMoodCallback(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+		ok   bool
+	}{
+		{"mood:Nice", "Nice", true},
+		{"mood:Not bad", "Not bad", true},
+		{"mood:Happy", "", false},
+		{"mood:", "", false},
+		{"mood:nice", "", false},
+		{"Nice", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseMoodCallback(tt.data)
+		assert.Equal(t, tt.ok, ok, tt.data)
+		assert.Equal(t, tt.want, got, tt.data)
+	}
+
+	// Every button on the keyboard parses back to its mood
+	for _, row := range moodKeyboard().InlineKeyboard {
+		for _, button := range row {
+			_, ok := parseMoodCallback(*button.CallbackData)
+			assert.True(t, ok, *button.CallbackData)
+		}
+	}
+}
+
+func TestHandleMoodButtonLogsMood(t *testing.T) {
+	setupTestDB(t)
+	bot := &fakeBot{}
+
+	handleMoodButton(bot, moodTap(12345, "mood:Nice"))
+
+	var logs []models.MoodLog
+	config.DB.Find(&logs, "user_id = ?", "user-1")
+	require.Len(t, logs, 1)
+	assert.Equal(t, "Nice", logs[0].Mood)
+	assert.Equal(t, 5, logs[0].Score)
+
+	require.Len(t, bot.callbacks, 1)
+	assert.Equal(t, "Saved", bot.callbacks[0].Text)
+	require.Len(t, bot.sent, 1)
+	edit, ok := bot.sent[0].(tgbotapi.EditMessageTextConfig)
+	require.True(t, ok, "the buttons are replaced")
+	assert.Equal(t, 7, edit.MessageID)
+}
+
+func TestHandleMoodButtonRejectsTaps(t *testing.T) {
+	setupTestDB(t)
+
+	tests := []struct {
+		name  string
+		query *tgbotapi.CallbackQuery
+		want  string
+	}{
+		{"unknown mood", moodTap(12345, "mood:Happy"), "Unknown mood"},
+		{"unlinked chat", moodTap(999, "mood:Nice"), "This chat is not linked to KBTU Care"},
+		{"other button", moodTap(12345, "other:Nice"), ""},
+		{"no message", &tgbotapi.CallbackQuery{ID: "query-1", Data: "mood:Nice"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &fakeBot{}
+			handleMoodButton(bot, tt.query)
+
+			require.Len(t, bot.callbacks, 1)
+			assert.Equal(t, tt.want, bot.callbacks[0].Text)
+			assert.Empty(t, bot.sent)
+		})
+	}
+
+	var logged int64
+	config.DB.Model(&models.MoodLog{}).Count(&logged)
+	assert.Zero(t, logged)
+}
+
+func TestSendMoodRemindersMarksTheDayAfterSending(t *testing.T) {
+	setupTestDB(t)
+	require.NoError(t, config.DB.Create(&models.MoodReminder{UserID: "user-1", Enabled: true, Time: "00:00"}).Error)
+	lastSentOn := func() string {
+		var r models.MoodReminder
+		require.NoError(t, config.DB.First(&r, "user_id = ?", "user-1").Error)
+		return r.LastSentOn
+	}
+
+	// A failed send leaves the day open for the next tick
+	sendMoodReminders(&fakeBot{sendErr: errors.New("telegram is down")})
+	assert.Empty(t, lastSentOn())
+
+	bot := &fakeBot{}
+	sendMoodReminders(bot)
+	require.Len(t, bot.sent, 1)
+	assert.NotEmpty(t, lastSentOn())
+
+	// Once a day
+	sendMoodReminders(bot)
+	assert.Len(t, bot.sent, 1)
+}
+
+func TestSendMoodRemindersSkipsUsersWhoLoggedToday(t *testing.T) {
+	setupTestDB(t)
+	require.NoError(t, config.DB.Create(&models.MoodReminder{UserID: "user-1", Enabled: true, Time: "00:00"}).Error)
+	handleMoodButton(&fakeBot{}, moodTap(12345, "mood:Sad"))
+
+	bot := &fakeBot{}
+	sendMoodReminders(bot)
+	assert.Empty(t, bot.sent)
+}
@@ -15,13 +15,31 @@ func SetupRoutes(r *gin.Engine, profileHandler *handlers.ProfileHandler) {
 		api.PUT("/me", profileHandler.UpdateMyProfile)
 		api.GET("/psychologists", profileHandler.GetPublicPsychologists)
 		api.POST("/me/mood", profileHandler.LogMood)
+		api.GET("/me/mood", profileHandler.GetMoodEntries)
+		api.DELETE("/me/mood/:id", profileHandler.DeleteMoodEntry)
+		api.POST("/me/mood/consents", profileHandler.GrantMoodConsent)
+		api.GET("/me/mood/consents", profileHandler.GetMoodConsents)
+		api.DELETE("/me/mood/consents/:psychologist_id", profileHandler.RevokeMoodConsent)
 		api.GET("/me/mood/graphic", profileHandler.GetMoodGraphic)
+		api.GET("/me/mood/chart", profileHandler.GetMoodChart)
+		api.GET("/me/mood/reminder", profileHandler.GetMoodReminder)
+		api.PUT("/me/mood/reminder", profileHandler.SetMoodReminder)
 		api.POST("/me/avatar-url", profileHandler.GenerateUploadURL)
+		api.POST("/me/telegram/link", profileHandler.CreateTelegramLink)
+		api.DELETE("/me/telegram", profileHandler.UnlinkTelegram)
+		api.GET("/me/notification-preferences", profileHandler.GetNotificationPreferences)
+		api.PUT("/me/notification-preferences", profileHandler.UpdateNotificationPreferences)
+		api.GET("/risk-alerts", profileHandler.GetRiskAlerts)
+		api.GET("/risk-alerts/:id", profileHandler.GetRiskAlert)
+		api.POST("/risk-alerts/:id/acknowledge", profileHandler.AcknowledgeRiskAlert)
 	}
 	admin := api.Group("/admin")
 	{
 		admin.GET("/users", profileHandler.ListAllUsers)
 		admin.GET("/psychologists", profileHandler.GetAllPsychologists)
+		admin.POST("/duty-shifts", profileHandler.CreateDutyShift)
+		admin.GET("/duty-shifts", profileHandler.GetDutyShifts)
+		admin.DELETE("/duty-shifts/:id", profileHandler.DeleteDutyShift)
 	}
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }